@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ukms/archaeform/pkg/aws"
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// discoveryOptions は AWS からのディスカバリ（F-01）に関する CLI の設定。
+type discoveryOptions struct {
+	// Scope のうち、クレデンシャル・エンドポイント関連の設定をクライアント生成に利用する。
+	Scope       terraform.DiscoveryScope
+	Concurrency int
+	Throttle    aws.ThrottleConfig
+	// NoCache が true の場合はディスカバリキャッシュを使わない。
+	NoCache bool
+	Cache   aws.CacheConfig
+}
+
+// newRegionalDiscoveryFactory はリージョンごとに AWS クライアントと
+// （キャッシュ付きの）VPC ディスカバリを生成する RegionalDiscoveryFactory を返す。
+// API 呼び出しの流量制御と呼び出し上限は全リージョンで共有する。
+func newRegionalDiscoveryFactory(opts discoveryOptions, logger aws.Logger) aws.RegionalDiscoveryFactory {
+	guard := aws.NewAPIGuard(opts.Throttle)
+	cacheEnabled := !opts.NoCache
+	var accountID string
+
+	return func(ctx context.Context, region string) (aws.RegionalDiscovery, error) {
+		clients, err := aws.NewAwsClients(ctx, aws.AwsClientConfig{
+			Region:            region,
+			Profile:           opts.Scope.Profile,
+			AssumeRoles:       opts.Scope.AssumeRoles,
+			EndpointURL:       opts.Scope.EndpointURL,
+			ServiceEndpoints:  opts.Scope.ServiceEndpoints,
+			StaticCredentials: opts.Scope.StaticCredentials,
+			Guard:             guard,
+		})
+		if err != nil {
+			return aws.RegionalDiscovery{}, fmt.Errorf("failed to initialize AWS clients: %w", err)
+		}
+
+		svc := aws.NewAwsVpcDiscoveryServiceWithClients(clients, logger)
+		svc.SetConcurrency(opts.Concurrency)
+		rd := aws.RegionalDiscovery{Discovery: svc, Ec2: clients.Ec2}
+
+		if cacheEnabled {
+			if accountID == "" {
+				// キャッシュキーに用いるアカウント ID は STS から取得する
+				accountID, err = clients.AccountID(ctx)
+				if err != nil {
+					logger.Warnf("discovery cache disabled: %v", err)
+					cacheEnabled = false
+					return rd, nil
+				}
+			}
+			cache := aws.NewDiscoveryCache(opts.Cache)
+			svc.WrapListers(func(l aws.ResourceLister) aws.ResourceLister {
+				return aws.NewCachedLister(l, cache, accountID, logger)
+			})
+		}
+		return rd, nil
+	}
+}
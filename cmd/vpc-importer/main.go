@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/ukms/archaeform/pkg/aws"
 	"github.com/ukms/archaeform/pkg/terraform"
@@ -24,38 +29,131 @@ func (l *stdLogger) Errorf(format string, args ...any) {
 	fmt.Fprintf(os.Stderr, "[ERROR] "+format+"\n", args...)
 }
 
+// splitList はカンマ区切りのフラグ値を分割し、空要素を除いて返す。
+func splitList(v string) []string {
+	var out []string
+	for _, e := range strings.Split(v, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
 func main() {
 	var (
 		vpcID     string
 		region    string
 		profile   string
 		tfDir     string
-		apply     bool
-		resFilter string
+		apply       bool
+		resFilter   string
+		concurrency int
+		timeout     time.Duration
+		maxAPICalls int64
+		apiRate     float64
+		apiBurst    int
+		maxRetries  int
+		dumpPath    string
+		snapPath    string
+		cacheDir    string
+		cacheTTL    time.Duration
+		refresh     bool
+		noCache     bool
+		allVpcs     bool
+		assumeRoles []terraform.AssumeRole
+		endpointURL string
+		endpoints   = make(map[string]string)
+		accessKey   string
+		secretKey   string
+		sessionTok  string
+		naclRules   string
+		sgRules     string
 	)
 
-	flag.StringVar(&vpcID, "vpc-id", "", "Target VPC ID (required)")
-	flag.StringVar(&region, "region", "", "AWS region (required, or from AWS_REGION/AWS_DEFAULT_REGION)")
+	flag.StringVar(&vpcID, "vpc-id", "", "Target VPC ID(s), comma-separated (required unless --all-vpcs)")
+	flag.BoolVar(&allVpcs, "all-vpcs", false, "Discover all VPCs in the target region(s)")
+	flag.StringVar(&region, "region", "", "AWS region(s), comma-separated (required, or from AWS_REGION/AWS_DEFAULT_REGION)")
 	flag.StringVar(&profile, "profile", "", "AWS profile name (optional)")
+	flag.Func("assume-role", "Role to assume as ARN[,external-id=ID][,session-name=NAME] (repeat to chain roles in order)", func(v string) error {
+		role, err := terraform.ParseAssumeRole(v)
+		if err != nil {
+			return err
+		}
+		assumeRoles = append(assumeRoles, role)
+		return nil
+	})
+	flag.StringVar(&endpointURL, "endpoint-url", "", "Override the endpoint URL for all AWS services (e.g. http://localhost:4566 for LocalStack)")
+	flag.Func("endpoint", "Override the endpoint URL for one service as SERVICE=URL (e.g. ec2=http://localhost:5000, repeatable)", func(v string) error {
+		svc, u, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(svc) == "" || strings.TrimSpace(u) == "" {
+			return fmt.Errorf("expected SERVICE=URL, got %q", v)
+		}
+		endpoints[strings.TrimSpace(svc)] = strings.TrimSpace(u)
+		return nil
+	})
+	flag.StringVar(&accessKey, "access-key-id", "", "Static AWS access key ID (for local emulators; prefer the default credential chain for real accounts)")
+	flag.StringVar(&secretKey, "secret-access-key", "", "Static AWS secret access key (used with --access-key-id)")
+	flag.StringVar(&sessionTok, "session-token", "", "Static AWS session token (optional, used with --access-key-id)")
 	flag.StringVar(&tfDir, "tf-dir", "", "Terraform configuration directory (required)")
 	flag.BoolVar(&apply, "apply", false, "Execute terraform import automatically")
 	flag.StringVar(&resFilter, "resource-filters", "", "Resource filter expression (e.g. type=aws_instance,tag:Env=prod)")
+	flag.IntVar(&concurrency, "concurrency", aws.DefaultDiscoveryConcurrency, "Number of AWS discovery calls to run in parallel")
+	flag.DurationVar(&timeout, "timeout", 0, "Overall timeout for AWS discovery (e.g. 10m, 0 = no timeout)")
+	throttleDefaults := aws.DefaultThrottleConfig()
+	flag.Int64Var(&maxAPICalls, "max-api-calls", 0, "Maximum number of AWS API calls including retries (0 = unlimited)")
+	flag.Float64Var(&apiRate, "api-rate", throttleDefaults.RatePerSecond, "Maximum AWS API calls per second per service (0 = unlimited)")
+	flag.IntVar(&apiBurst, "api-burst", throttleDefaults.Burst, "Burst size of the per-service AWS API rate limiter")
+	flag.IntVar(&maxRetries, "max-retries", throttleDefaults.Retry.MaxAttempts-1, "Maximum number of retries per AWS API call on throttling or transient errors")
+	flag.StringVar(&dumpPath, "dump-discovery", "", "Write discovered resources/relations to a snapshot JSON file")
+	flag.StringVar(&snapPath, "from-snapshot", "", "Read resources/relations from a snapshot JSON file instead of calling AWS")
+	flag.StringVar(&cacheDir, "cache-dir", "", "Directory for the discovery cache (default: <user cache dir>/archaeform/discovery)")
+	flag.DurationVar(&cacheTTL, "cache-ttl", aws.DefaultCacheTTL, "How long cached discovery results stay valid")
+	flag.BoolVar(&refresh, "refresh", false, "Ignore cached discovery results and re-fetch from AWS (the cache is updated)")
+	flag.BoolVar(&noCache, "no-cache", false, "Disable the discovery cache")
+	flag.StringVar(&naclRules, "nacl-rules", string(terraform.NetworkAclRulesInline), "How to generate network ACL rules: inline (ingress/egress blocks) or separate (aws_network_acl_rule resources)")
+	flag.StringVar(&sgRules, "sg-rules", string(terraform.SecurityGroupRulesSeparate), "How to generate security group rules: separate (aws_vpc_security_group_ingress/egress_rule resources) or inline (ingress/egress blocks)")
 
 	flag.Parse()
 
 	logger := &stdLogger{}
 
-	if vpcID == "" {
-		logger.Errorf("--vpc-id is required")
+	vpcIDs := splitList(vpcID)
+	regions := splitList(region)
+
+	// --from-snapshot 時は AWS を呼び出さず、スナップショットのスコープを既定値とする
+	var snapshot *terraform.DiscoverySnapshot
+	if snapPath != "" {
+		snap, err := terraform.ReadSnapshot(snapPath)
+		if err != nil {
+			logger.Errorf("%v", err)
+			os.Exit(1)
+		}
+		snapshot = &snap
+		if len(vpcIDs) == 0 && !allVpcs {
+			vpcIDs = snap.Scope.VpcIDList()
+			allVpcs = snap.Scope.AllVpcs
+		}
+		if len(regions) == 0 {
+			regions = snap.Scope.RegionList()
+		}
+		if len(assumeRoles) == 0 {
+			assumeRoles = snap.Scope.AssumeRoles
+		}
+	}
+
+	if len(vpcIDs) == 0 && !allVpcs {
+		logger.Errorf("--vpc-id or --all-vpcs is required")
 		os.Exit(1)
 	}
-	if region == "" {
+	if len(regions) == 0 && snapshot == nil {
 		region = os.Getenv("AWS_REGION")
 		if region == "" {
 			region = os.Getenv("AWS_DEFAULT_REGION")
 		}
+		regions = splitList(region)
 	}
-	if region == "" {
+	if len(regions) == 0 {
 		logger.Errorf("--region or AWS_REGION/AWS_DEFAULT_REGION is required")
 		os.Exit(1)
 	}
@@ -63,11 +161,47 @@ func main() {
 		logger.Errorf("--tf-dir is required")
 		os.Exit(1)
 	}
+	naclRuleMode, err := terraform.ParseNetworkAclRuleMode(naclRules)
+	if err != nil {
+		logger.Errorf("--nacl-rules: %v", err)
+		os.Exit(1)
+	}
+	sgRuleMode, err := terraform.ParseSecurityGroupRuleMode(sgRules)
+	if err != nil {
+		logger.Errorf("--sg-rules: %v", err)
+		os.Exit(1)
+	}
+	if (accessKey == "") != (secretKey == "") {
+		logger.Errorf("--access-key-id and --secret-access-key must be specified together")
+		os.Exit(1)
+	}
 
 	scope := terraform.DiscoveryScope{
-		VpcID:   vpcID,
-		Region:  region,
-		Profile: profile,
+		AllVpcs:     allVpcs,
+		Profile:     profile,
+		AssumeRoles: assumeRoles,
+		EndpointURL: endpointURL,
+	}
+	if len(endpoints) > 0 {
+		scope.ServiceEndpoints = endpoints
+	}
+	if accessKey != "" {
+		scope.StaticCredentials = &terraform.StaticCredentials{
+			AccessKeyID:     accessKey,
+			SecretAccessKey: secretKey,
+			SessionToken:    sessionTok,
+		}
+	}
+	// 単一の VPC / リージョンの場合は従来どおり VpcID / Region に設定する
+	if len(vpcIDs) == 1 {
+		scope.VpcID = vpcIDs[0]
+	} else {
+		scope.VpcIDs = vpcIDs
+	}
+	if len(regions) == 1 {
+		scope.Region = regions[0]
+	} else {
+		scope.Regions = regions
 	}
 
 	if resFilter != "" {
@@ -79,14 +213,45 @@ func main() {
 		scope.ResourceFilters = []terraform.ResourceFilter{f}
 	}
 
-	// TODO: 実際の AWS SDK クライアント実装を差し込む。
-	var ec2 aws.Ec2API
-	var elb aws.ElbAPI
-	var rds aws.RdsAPI
+	// Ctrl-C / SIGTERM で実行中の AWS API 呼び出しをキャンセルする
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var discovery aws.CloudDiscovery
+	if snapshot != nil {
+		discovery = aws.NewSnapshotDiscovery(*snapshot, logger)
+	} else {
+		throttleCfg := throttleDefaults
+		throttleCfg.RatePerSecond = apiRate
+		throttleCfg.Burst = apiBurst
+		throttleCfg.MaxAPICalls = maxAPICalls
+		throttleCfg.Retry.MaxAttempts = maxRetries + 1
 
-	discovery := aws.NewAwsVpcDiscoveryService(ec2, elb, rds, logger)
+		if cacheDir == "" && !noCache {
+			d, err := aws.DefaultCacheDir()
+			if err != nil {
+				logger.Warnf("discovery cache disabled: %v", err)
+				noCache = true
+			}
+			cacheDir = d
+		}
+
+		factory := newRegionalDiscoveryFactory(discoveryOptions{
+			Scope:       scope,
+			Concurrency: concurrency,
+			Throttle:    throttleCfg,
+			NoCache:     noCache,
+			Cache:       aws.CacheConfig{Dir: cacheDir, TTL: cacheTTL, Refresh: refresh},
+		}, logger)
+		discovery = aws.NewMultiScopeDiscovery(factory, logger)
+	}
 
-	resources, relations, err := discovery.ListResources(scope)
+	resources, relations, report, err := discovery.ListResources(ctx, scope)
 	if err != nil {
 		logger.Errorf("VPC discovery failed: %v", err)
 		os.Exit(1)
@@ -94,13 +259,36 @@ func main() {
 
 	logger.Infof("VPC discovery completed: resources=%d relations=%d", len(resources), len(relations))
 
-	// TODO: F-02 以降の処理:
-	//   - ExistingConfigAnalyzer による既存 .tf 解析
-	//   - HclGenerator による HCL 生成
-	//   - ImportCommandGenerator による import スクリプト生成
-	//   - --apply 時の terraform import 実行
-	_ = tfDir
-	_ = apply
-}
+	if dumpPath != "" {
+		snap := terraform.NewDiscoverySnapshot(scope, resources, relations, report, "vpc-importer")
+		if err := terraform.WriteSnapshot(dumpPath, snap); err != nil {
+			logger.Errorf("%v", err)
+			os.Exit(1)
+		}
+		logger.Infof("Discovery snapshot written to %s", dumpPath)
+	}
 
+	summary, outputs, err := runImport(resources, relations, report, importOptions{
+		TfDir:       tfDir,
+		Apply:       apply,
+		Filters:     scope.ResourceFilters,
+		MultiRegion: scope.IsMultiRegion(),
+		AssumeRoles: scope.AssumeRoles,
+		NaclRules:   naclRuleMode,
+		SgRules:     sgRuleMode,
+	}, logger)
+	if err != nil {
+		logger.Errorf("%v", err)
+		os.Exit(1)
+	}
 
+	targetVpcs := strings.Join(scope.VpcIDList(), ", ")
+	if scope.AllVpcs {
+		targetVpcs = "all VPCs"
+	}
+	if err := summary.WriteText(os.Stdout, targetVpcs, strings.Join(scope.RegionList(), ", "), outputs.HclOutputDir, outputs.ImportScriptPath); err != nil {
+		logger.Errorf("failed to write summary: %v", err)
+		os.Exit(1)
+	}
+	os.Exit(exitCode(summary))
+}
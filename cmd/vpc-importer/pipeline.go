@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ukms/archaeform/pkg/aws"
+	"github.com/ukms/archaeform/pkg/importer"
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// importOptions は F-02 以降の処理（HCL 生成・import スクリプト生成・apply）の設定。
+type importOptions struct {
+	TfDir   string
+	Apply   bool
+	Filters []terraform.ResourceFilter
+	// MultiRegion が true の場合、リージョンごとの provider alias を付与して HCL を生成する。
+	MultiRegion bool
+	// AssumeRoles を指定した場合、生成する provider ブロックに assume_role を付与する。
+	AssumeRoles []terraform.AssumeRole
+	// NaclRules はネットワーク ACL のルールを ingress / egress ブロックと aws_network_acl_rule のどちらで出力するか。
+	NaclRules terraform.NetworkAclRuleMode
+	// SgRules はセキュリティグループのルールを aws_vpc_security_group_ingress/egress_rule と ingress / egress ブロックのどちらで出力するか。
+	SgRules terraform.SecurityGroupRuleMode
+}
+
+// importOutputs は WriteText に渡すための生成物のパス。
+type importOutputs struct {
+	HclOutputDir     string
+	ImportScriptPath string
+}
+
+// runImport はディスカバリ結果をもとに、既存構成との競合検出・HCL 生成・
+// import スクリプト生成・（--apply 時は）terraform import 実行を行い、ImportSummary を構築する。
+// 致命的なエラー（出力先への書き込み失敗など）の場合のみ error を返す。
+func runImport(resources []terraform.Resource, relations []terraform.Relation, report terraform.DiscoveryReport, opts importOptions, logger aws.Logger) (importer.ImportSummary, importOutputs, error) {
+	var summary importer.ImportSummary
+	var outputs importOutputs
+
+	if opts.NaclRules == terraform.NetworkAclRulesSeparate {
+		resources, relations = terraform.SeparateNetworkAclRules(resources, relations)
+	}
+	if opts.SgRules == terraform.SecurityGroupRulesInline {
+		resources, relations = terraform.InlineSecurityGroupRules(resources, relations)
+	}
+
+	summary.TotalResources = len(resources)
+	summary.AddDiscoveryReport(report)
+
+	// F-08: リソースフィルタ
+	var filtered []terraform.Resource
+	for _, r := range resources {
+		if terraform.MatchResource(opts.Filters, r) {
+			filtered = append(filtered, r)
+		}
+	}
+	if excluded := len(resources) - len(filtered); excluded > 0 {
+		logger.Infof("Excluded %d resources by --resource-filters", excluded)
+	}
+
+	// F-04: 既存 .tf との競合検出
+	analyzer := importer.NewExistingConfigAnalyzer()
+	index, err := analyzer.AnalyzeExistingConfigs(opts.TfDir)
+	if err != nil {
+		return summary, outputs, fmt.Errorf("failed to analyze existing configs: %w", err)
+	}
+	importable, conflicted := analyzer.FilterConflicted(filtered, index)
+	summary.ImportableResources = len(importable)
+	summary.ConflictedResources = len(conflicted)
+	for _, c := range conflicted {
+		msg := fmt.Sprintf("%s.%s conflicts with existing configuration (%s:%d) and was skipped",
+			c.Imported.Type, c.Imported.Name, c.Existing.FilePath, c.Existing.Line)
+		logger.Warnf("%s", msg)
+		summary.Warnings = append(summary.Warnings, msg)
+	}
+
+	// F-03: HCL 生成
+	hclResult, err := importer.NewHclGenerator().Generate(importable, relations, importer.HclGenerationConfig{
+		TfDir:                  opts.TfDir,
+		ProviderAliasPerRegion: opts.MultiRegion || len(opts.AssumeRoles) > 0,
+		AssumeRoles:            opts.AssumeRoles,
+	})
+	if err != nil {
+		return summary, outputs, fmt.Errorf("failed to generate HCL: %w", err)
+	}
+	summary.GeneratedHclFiles = len(hclResult.GeneratedFiles)
+	outputs.HclOutputDir = hclResult.OutputDir
+
+	// F-05: import スクリプト生成
+	scriptPath, err := importer.NewImportCommandGenerator().GenerateImportScript(importable, importer.ImportScriptConfig{
+		TfDir: opts.TfDir,
+	})
+	if err != nil {
+		return summary, outputs, fmt.Errorf("failed to generate import script: %w", err)
+	}
+	outputs.ImportScriptPath = scriptPath
+	for _, r := range importable {
+		if r.IsDataSource() {
+			continue
+		}
+		if _, ok := importer.ResolveImportID(r); ok {
+			summary.GeneratedImportCommands++
+		} else {
+			logger.Warnf("no import ID for %s.%s, skipped", r.Type, r.Name)
+		}
+	}
+
+	// F-06: 自動 import 実行
+	summary.ApplyRequested = opts.Apply
+	if opts.Apply {
+		executor := terraform.NewDefaultTerraformExecutor()
+		if err := executor.Init(opts.TfDir); err != nil {
+			return summary, outputs, err
+		}
+		for _, r := range importable {
+			id, ok := importer.ResolveImportID(r)
+			if !ok || r.IsDataSource() {
+				continue
+			}
+			address := fmt.Sprintf("%s.%s", r.Type, r.Name)
+			if err := executor.Import(opts.TfDir, address, id); err != nil {
+				logger.Errorf("%v", err)
+				summary.ApplyFailed++
+				summary.Errors = append(summary.Errors, err.Error())
+				continue
+			}
+			summary.ApplySucceeded++
+		}
+	}
+
+	return summary, outputs, nil
+}
+
+// exitCode は F-07 の終了コード規約に従い、ImportSummary から終了コードを決める。
+//   - 正常終了（警告あり含む）: 0
+//   - 一部 import 失敗・競合あり: 2
+func exitCode(summary importer.ImportSummary) int {
+	if summary.ApplyFailed > 0 || summary.ConflictedResources > 0 {
+		return 2
+	}
+	return 0
+}
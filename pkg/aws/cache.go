@@ -0,0 +1,161 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// DefaultCacheTTL はディスカバリキャッシュの有効期限のデフォルト値。
+const DefaultCacheTTL = time.Hour
+
+// CacheConfig はディスカバリキャッシュの設定。
+type CacheConfig struct {
+	// Dir はキャッシュファイルの保存先ディレクトリ。
+	Dir string
+	// TTL はキャッシュエントリの有効期限。0 以下の場合は DefaultCacheTTL を用いる。
+	TTL time.Duration
+	// Refresh が true の場合、既存のキャッシュを読まずに AWS から再取得し、結果でキャッシュを更新する。
+	Refresh bool
+}
+
+// DefaultCacheDir はユーザーキャッシュディレクトリ配下のデフォルトのキャッシュディレクトリを返す。
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "archaeform", "discovery"), nil
+}
+
+// CacheKey はディスカバリキャッシュのエントリを識別するキー。
+type CacheKey struct {
+	AccountID string
+	Region    string
+	VpcID     string
+	Lister    string // lister の識別名 (例: "aws_subnet")
+}
+
+// path は key に対応するキャッシュファイルのパスを返す。
+func (k CacheKey) path(dir string) string {
+	return filepath.Join(dir, k.AccountID, k.Region, k.VpcID, k.Lister+".json")
+}
+
+// cacheEntry はキャッシュファイルの内容。
+type cacheEntry struct {
+	CreatedAt time.Time            `json:"createdAt"`
+	Resources []terraform.Resource `json:"resources"`
+	Relations []terraform.Relation `json:"relations"`
+}
+
+// DiscoveryCache は lister 単位の列挙結果をファイルに保存するキャッシュ。
+type DiscoveryCache struct {
+	cfg CacheConfig
+}
+
+// NewDiscoveryCache は DiscoveryCache を生成する。
+func NewDiscoveryCache(cfg CacheConfig) *DiscoveryCache {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultCacheTTL
+	}
+	return &DiscoveryCache{cfg: cfg}
+}
+
+// Get は key に対応する有効期限内のエントリを返す。
+// エントリが存在しない・期限切れ・読み込めない場合、および Refresh 指定時は ok=false を返す。
+func (c *DiscoveryCache) Get(key CacheKey) ([]terraform.Resource, []terraform.Relation, bool) {
+	if c.cfg.Refresh {
+		return nil, nil, false
+	}
+	data, err := os.ReadFile(key.path(c.cfg.Dir))
+	if err != nil {
+		return nil, nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, nil, false
+	}
+	if time.Now().Sub(entry.CreatedAt) > c.cfg.TTL {
+		return nil, nil, false
+	}
+	terraform.NormalizeJSONAttributes(entry.Resources)
+	return entry.Resources, entry.Relations, true
+}
+
+// Put は key に対応するエントリを書き込む。
+// 書き込み途中のファイルを読まないよう、一時ファイルに書いてからリネームする。
+func (c *DiscoveryCache) Put(key CacheKey, resources []terraform.Resource, relations []terraform.Relation) error {
+	path := key.path(c.cfg.Dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.Marshal(cacheEntry{
+		CreatedAt: time.Now().UTC(),
+		Resources: resources,
+		Relations: relations,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to close cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+// cachedLister は ResourceLister の列挙結果を DiscoveryCache に保存・再利用するデコレータ。
+// 失敗した（権限不足を含む）lister の結果はキャッシュしない。
+type cachedLister struct {
+	inner     ResourceLister
+	cache     *DiscoveryCache
+	accountID string
+	logger    Logger
+}
+
+// NewCachedLister は inner の列挙結果を cache に保存・再利用する ResourceLister を返す。
+// accountID はキャッシュキーに用い、アカウントをまたいで結果が混ざらないようにする。
+// awsVpcDiscoveryService.WrapListers と組み合わせて利用する。
+func NewCachedLister(inner ResourceLister, cache *DiscoveryCache, accountID string, logger Logger) ResourceLister {
+	return &cachedLister{inner: inner, cache: cache, accountID: accountID, logger: logger}
+}
+
+func (c *cachedLister) TypeName() string        { return c.inner.TypeName() }
+func (c *cachedLister) Dependencies() []string  { return c.inner.Dependencies() }
+func (c *cachedLister) ResourceTypes() []string { return ListerResourceTypes(c.inner) }
+
+// List は結果をキャッシュから返し、なければ inner を呼び出して結果をキャッシュする。
+func (c *cachedLister) List(ctx context.Context, scope terraform.DiscoveryScope) ([]terraform.Resource, []terraform.Relation, error) {
+	key := CacheKey{AccountID: c.accountID, Region: scope.Region, VpcID: scope.VpcID, Lister: c.inner.TypeName()}
+	if rs, rels, ok := c.cache.Get(key); ok {
+		callStatsFromContext(ctx).cacheHit.Store(true)
+		return rs, rels, nil
+	}
+
+	rs, rels, err := c.inner.List(ctx, scope)
+	if err != nil {
+		return rs, rels, err
+	}
+	if err := c.cache.Put(key, rs, rels); err != nil {
+		c.logger.Warnf("failed to cache %s: %v", key.Lister, err)
+	}
+	return rs, rels, nil
+}
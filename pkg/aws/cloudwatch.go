@@ -0,0 +1,295 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// logGroupSourceListers はロググループにログを出力するリソースの lister。
+var logGroupSourceListers = []string{"aws_lambda_function", "aws_ecs_service", "aws_codebuild_project", "aws_db_instance", "aws_flow_log"}
+
+// alarmTargetListers はメトリクスアラームの監視対象になるリソースの lister。
+var alarmTargetListers = []string{"aws_instance", "aws_lb", "aws_db_instance", "aws_elasticache_cluster", "aws_ecs_cluster", "aws_ecs_service", "aws_lambda_function", "aws_codebuild_project"}
+
+// ListCloudWatchLogGroups は VPC 内のリソース（Lambda・ECS タスク定義・CodeBuild・フローログ・RDS のログのエクスポート）の
+// ログの出力先ロググループを列挙する。ロググループは各リソースの lister の列挙結果から取得するため、アカウント内の全ロググループは列挙しない。
+// 参照されているが存在しない（まだログが出力されていない）ロググループは WARN ログを出して対象外にする。
+func (s *awsVpcDiscoveryService) ListCloudWatchLogGroups(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	region := ScopeRegion(ctx)
+	var names []string
+	for _, typeName := range logGroupSourceListers {
+		for _, r := range UpstreamResources(ctx, typeName) {
+			for _, name := range logGroupNames(r, region) {
+				names = appendUniqueName(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	var raws []terraform.RawCloudWatchLogGroup
+	for _, name := range names {
+		raw, ok, err := s.describeLogGroup(ctx, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			s.logger.Warnf("log group %s is referenced but does not exist", name)
+			continue
+		}
+		raws = append(raws, raw)
+	}
+
+	return s.mapper.MapCloudWatchLogGroup(raws, region, vpcID)
+}
+
+// describeLogGroup は名前が name と一致するロググループとそのタグを取得する。存在しない場合は ok = false を返す。
+// DescribeLogGroups は前方一致でしか絞り込めないため、結果から完全一致するものを探す。
+func (s *awsVpcDiscoveryService) describeLogGroup(ctx context.Context, name string) (terraform.RawCloudWatchLogGroup, bool, error) {
+	p := cloudwatchlogs.NewDescribeLogGroupsPaginator(s.logs, &cloudwatchlogs.DescribeLogGroupsInput{LogGroupNamePrefix: awssdk.String(name)})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return terraform.RawCloudWatchLogGroup{}, false, fmt.Errorf("DescribeLogGroups %s: %w", name, err)
+		}
+		for _, g := range page.LogGroups {
+			if awssdk.ToString(g.LogGroupName) != name {
+				continue
+			}
+			raw := terraform.RawCloudWatchLogGroup{
+				Name:            name,
+				Arn:             awssdk.ToString(g.LogGroupArn),
+				RetentionInDays: awssdk.ToInt32(g.RetentionInDays),
+				KmsKeyID:        awssdk.ToString(g.KmsKeyId),
+				LogGroupClass:   string(g.LogGroupClass),
+			}
+			tags, err := s.logs.ListTagsForResource(ctx, &cloudwatchlogs.ListTagsForResourceInput{ResourceArn: g.LogGroupArn})
+			if err != nil {
+				return terraform.RawCloudWatchLogGroup{}, false, fmt.Errorf("ListTagsForResource %s: %w", name, err)
+			}
+			raw.Tags = tags.Tags
+			if raw.Tags == nil {
+				raw.Tags = map[string]string{}
+			}
+			return raw, true, nil
+		}
+	}
+	return terraform.RawCloudWatchLogGroup{}, false, nil
+}
+
+// logGroupNames は r がログを出力するロググループ名を返す。region は列挙中のスコープのリージョン。
+func logGroupNames(r terraform.Resource, region string) []string {
+	switch r.Type {
+	case "aws_lambda_function":
+		if logging := firstBlock(r.Attributes, "logging_config"); logging != nil {
+			if name, ok := logging["log_group"].(string); ok && name != "" {
+				return []string{name}
+			}
+		}
+		name, _ := r.Attributes["function_name"].(string)
+		return []string{"/aws/lambda/" + name}
+	case "aws_ecs_task_definition":
+		defs, _ := r.Attributes["container_definitions"].(string)
+		return ecsAwslogsGroups(defs, region)
+	case "aws_codebuild_project":
+		var cw terraform.HCLBlock
+		if logs := firstBlock(r.Attributes, "logs_config"); logs != nil {
+			cw = firstBlock(logs, "cloudwatch_logs")
+		}
+		if cw["status"] == "DISABLED" {
+			return nil
+		}
+		if name, ok := cw["group_name"].(string); ok && name != "" {
+			return []string{name}
+		}
+		name, _ := r.Attributes["name"].(string)
+		return []string{"/aws/codebuild/" + name}
+	case "aws_db_instance", "aws_rds_cluster":
+		exports, _ := r.Attributes["enabled_cloudwatch_logs_exports"].([]string)
+		if r.Type == "aws_rds_cluster" {
+			id, _ := r.Attributes["cluster_identifier"].(string)
+			return terraform.RdsLogGroupNames("cluster", id, exports)
+		}
+		id, _ := r.Attributes["identifier"].(string)
+		return terraform.RdsLogGroupNames("instance", id, exports)
+	case "aws_flow_log":
+		if r.Attributes["log_destination_type"] != "cloud-watch-logs" {
+			return nil
+		}
+		arn, _ := r.Attributes["log_destination"].(string)
+		if name := terraform.LogGroupNameFromArn(arn); name != "" {
+			return []string{name}
+		}
+	}
+	return nil
+}
+
+// ecsAwslogsGroups はタスク定義の container_definitions（JSON）から、awslogs ログドライバーの出力先ロググループ名を返す。
+// region と異なるリージョンのロググループは対象外。
+func ecsAwslogsGroups(containerDefinitions string, region string) []string {
+	var containers []struct {
+		LogConfiguration struct {
+			LogDriver string            `json:"logDriver"`
+			Options   map[string]string `json:"options"`
+		} `json:"logConfiguration"`
+	}
+	if err := json.Unmarshal([]byte(containerDefinitions), &containers); err != nil {
+		return nil
+	}
+	var names []string
+	for _, c := range containers {
+		opts := c.LogConfiguration.Options
+		if c.LogConfiguration.LogDriver != "awslogs" || opts["awslogs-group"] == "" {
+			continue
+		}
+		if logRegion := opts["awslogs-region"]; logRegion != "" && region != "" && logRegion != region {
+			continue
+		}
+		names = appendUniqueName(names, opts["awslogs-group"])
+	}
+	return names
+}
+
+// firstBlock は attrs[key] のネストしたブロックの先頭を返す。ブロックがない場合は nil を返す。
+func firstBlock(attrs map[string]any, key string) terraform.HCLBlock {
+	if blocks, ok := attrs[key].([]terraform.HCLBlock); ok && len(blocks) > 0 {
+		return blocks[0]
+	}
+	return nil
+}
+
+// ListCloudWatchMetricAlarms はディメンションが VPC 内のリソース（インスタンス ID・ロードバランサー / ターゲットグループの ARN サフィックス・
+// DB 識別子・ElastiCache クラスター・ECS クラスター / サービス・Lambda 関数・CodeBuild プロジェクト）を指すメトリクスアラームを列挙する。
+// メトリクス数式（Metrics）を使うアラームと複合アラームは対象外。
+func (s *awsVpcDiscoveryService) ListCloudWatchMetricAlarms(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	targets := alarmDimensionTargets(ctx)
+	if len(targets) == 0 {
+		return nil, nil, nil
+	}
+
+	var raws []terraform.RawCloudWatchMetricAlarm
+	p := cloudwatch.NewDescribeAlarmsPaginator(s.cloudwatch, &cloudwatch.DescribeAlarmsInput{})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeAlarms: %w", err)
+		}
+		for _, a := range page.MetricAlarms {
+			dims := make(map[string]string, len(a.Dimensions))
+			for _, d := range a.Dimensions {
+				dims[awssdk.ToString(d.Name)] = awssdk.ToString(d.Value)
+			}
+			matched := alarmTargets(dims, targets)
+			if len(matched) == 0 {
+				continue
+			}
+			raw := terraform.RawCloudWatchMetricAlarm{
+				Name:                    awssdk.ToString(a.AlarmName),
+				Arn:                     awssdk.ToString(a.AlarmArn),
+				Description:             awssdk.ToString(a.AlarmDescription),
+				Namespace:               awssdk.ToString(a.Namespace),
+				MetricName:              awssdk.ToString(a.MetricName),
+				Statistic:               string(a.Statistic),
+				ExtendedStatistic:       awssdk.ToString(a.ExtendedStatistic),
+				ComparisonOperator:      string(a.ComparisonOperator),
+				Threshold:               awssdk.ToFloat64(a.Threshold),
+				EvaluationPeriods:       awssdk.ToInt32(a.EvaluationPeriods),
+				DatapointsToAlarm:       awssdk.ToInt32(a.DatapointsToAlarm),
+				Period:                  awssdk.ToInt32(a.Period),
+				Unit:                    string(a.Unit),
+				TreatMissingData:        awssdk.ToString(a.TreatMissingData),
+				ActionsEnabled:          awssdk.ToBool(a.ActionsEnabled),
+				AlarmActions:            a.AlarmActions,
+				OKActions:               a.OKActions,
+				InsufficientDataActions: a.InsufficientDataActions,
+				Dimensions:              dims,
+				Targets:                 matched,
+				Tags:                    map[string]string{},
+			}
+			tags, err := s.cloudwatch.ListTagsForResource(ctx, &cloudwatch.ListTagsForResourceInput{ResourceARN: a.AlarmArn})
+			if err != nil {
+				return nil, nil, fmt.Errorf("ListTagsForResource %s: %w", raw.Name, err)
+			}
+			for _, t := range tags.Tags {
+				raw.Tags[awssdk.ToString(t.Key)] = awssdk.ToString(t.Value)
+			}
+			raws = append(raws, raw)
+		}
+	}
+
+	return s.mapper.MapCloudWatchMetricAlarm(raws, ScopeRegion(ctx), vpcID)
+}
+
+// alarmDimensionTargets は監視対象の lister の列挙結果から、「<ディメンション名>=<値>」-> Resource ID のマップを作る。
+// ECS サービスはクラスター名と組み合わせて「ServiceName=<クラスター名>/<サービス名>」とする。
+func alarmDimensionTargets(ctx context.Context) map[string]string {
+	targets := make(map[string]string)
+	add := func(dimension string, value any, r terraform.Resource) {
+		if v, ok := value.(string); ok && v != "" {
+			targets[dimension+"="+v] = r.ID
+		}
+	}
+	for _, typeName := range alarmTargetListers {
+		for _, r := range UpstreamResources(ctx, typeName) {
+			switch r.Type {
+			case "aws_instance":
+				add("InstanceId", r.Attributes["id"], r)
+			case "aws_lb":
+				// ディメンションの値は ARN の "loadbalancer/" 以降（app/<name>/<id>）
+				if arn, ok := r.Attributes["id"].(string); ok {
+					if _, suffix, ok := strings.Cut(arn, ":loadbalancer/"); ok {
+						add("LoadBalancer", suffix, r)
+					}
+				}
+			case "aws_lb_target_group":
+				if arn, ok := r.Attributes["id"].(string); ok {
+					add("TargetGroup", arn[strings.LastIndex(arn, ":")+1:], r)
+				}
+			case "aws_db_instance", "aws_rds_cluster_instance":
+				add("DBInstanceIdentifier", r.Attributes["identifier"], r)
+			case "aws_rds_cluster":
+				add("DBClusterIdentifier", r.Attributes["cluster_identifier"], r)
+			case "aws_elasticache_cluster":
+				add("CacheClusterId", r.Attributes["id"], r)
+			case "aws_elasticache_replication_group":
+				add("ReplicationGroupId", r.Attributes["id"], r)
+			case "aws_ecs_cluster":
+				add("ClusterName", r.Attributes["name"], r)
+			case "aws_ecs_service":
+				add("ServiceName", r.Attributes["id"], r)
+			case "aws_lambda_function":
+				add("FunctionName", r.Attributes["function_name"], r)
+			case "aws_codebuild_project":
+				add("ProjectName", r.Attributes["name"], r)
+			}
+		}
+	}
+	return targets
+}
+
+// alarmTargets はアラームのディメンション dims が指す Resource ID を返す。
+// ECS サービスのアラームはクラスターとの組で照合し、クラスター単体には関連付けない。
+func alarmTargets(dims map[string]string, targets map[string]string) []string {
+	var matched []string
+	for name, value := range dims {
+		if name == "ClusterName" && dims["ServiceName"] != "" {
+			continue
+		}
+		if name == "ServiceName" {
+			value = dims["ClusterName"] + "/" + value
+		}
+		if id, ok := targets[name+"="+value]; ok {
+			matched = appendUniqueName(matched, id)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
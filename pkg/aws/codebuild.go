@@ -0,0 +1,118 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codebuild"
+	cbtypes "github.com/aws/aws-sdk-go-v2/service/codebuild/types"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// BatchGetProjects の 1 回あたりの最大件数。
+const codeBuildBatchGetProjectsSize = 100
+
+// ListCodeBuildProjects は vpc_config で VPC に接続された CodeBuild プロジェクトを列挙する。
+// ListProjects は名前のみを返し VPC フィルタもないため、BatchGetProjects の VpcConfig.VpcId で絞り込む。
+func (s *awsVpcDiscoveryService) ListCodeBuildProjects(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var names []string
+	p := codebuild.NewListProjectsPaginator(s.codebuild, &codebuild.ListProjectsInput{})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ListProjects: %w", err)
+		}
+		names = append(names, page.Projects...)
+	}
+
+	var raws []terraform.RawCodeBuildProject
+	for start := 0; start < len(names); start += codeBuildBatchGetProjectsSize {
+		end := min(start+codeBuildBatchGetProjectsSize, len(names))
+		out, err := s.codebuild.BatchGetProjects(ctx, &codebuild.BatchGetProjectsInput{Names: names[start:end]})
+		if err != nil {
+			return nil, nil, fmt.Errorf("BatchGetProjects: %w", err)
+		}
+		for _, project := range out.Projects {
+			if project.VpcConfig == nil || awssdk.ToString(project.VpcConfig.VpcId) != vpcID {
+				continue
+			}
+			raws = append(raws, rawCodeBuildProject(project, vpcID))
+		}
+	}
+
+	return s.mapper.MapCodeBuildProject(raws, ScopeRegion(ctx))
+}
+
+// rawCodeBuildProject は SDK の Project を RawCodeBuildProject に変換する。
+func rawCodeBuildProject(p cbtypes.Project, vpcID string) terraform.RawCodeBuildProject {
+	raw := terraform.RawCodeBuildProject{
+		Name:                 awssdk.ToString(p.Name),
+		Arn:                  awssdk.ToString(p.Arn),
+		VpcID:                vpcID,
+		Description:          awssdk.ToString(p.Description),
+		ServiceRole:          awssdk.ToString(p.ServiceRole),
+		BuildTimeoutMinutes:  awssdk.ToInt32(p.TimeoutInMinutes),
+		QueuedTimeoutMinutes: awssdk.ToInt32(p.QueuedTimeoutInMinutes),
+		ConcurrentBuildLimit: awssdk.ToInt32(p.ConcurrentBuildLimit),
+		EncryptionKey:        awssdk.ToString(p.EncryptionKey),
+		SubnetIDs:            append([]string(nil), p.VpcConfig.Subnets...),
+		SecurityGroupIDs:     append([]string(nil), p.VpcConfig.SecurityGroupIds...),
+		Tags:                 make(map[string]string, len(p.Tags)),
+	}
+	sort.Strings(raw.SubnetIDs)
+	sort.Strings(raw.SecurityGroupIDs)
+	for _, t := range p.Tags {
+		raw.Tags[awssdk.ToString(t.Key)] = awssdk.ToString(t.Value)
+	}
+
+	if src := p.Source; src != nil {
+		raw.SourceType = string(src.Type)
+		raw.SourceLocation = awssdk.ToString(src.Location)
+		raw.Buildspec = awssdk.ToString(src.Buildspec)
+		raw.GitCloneDepth = awssdk.ToInt32(src.GitCloneDepth)
+	}
+	if a := p.Artifacts; a != nil {
+		raw.ArtifactsType = string(a.Type)
+		raw.ArtifactsLocation = awssdk.ToString(a.Location)
+		raw.ArtifactsName = awssdk.ToString(a.Name)
+		raw.ArtifactsPath = awssdk.ToString(a.Path)
+		raw.ArtifactsPackaging = string(a.Packaging)
+		raw.ArtifactsNamespaceType = string(a.NamespaceType)
+	}
+	if env := p.Environment; env != nil {
+		raw.EnvironmentType = string(env.Type)
+		raw.ComputeType = string(env.ComputeType)
+		raw.Image = awssdk.ToString(env.Image)
+		raw.ImagePullCredentials = string(env.ImagePullCredentialsType)
+		raw.PrivilegedMode = awssdk.ToBool(env.PrivilegedMode)
+		for _, v := range env.EnvironmentVariables {
+			raw.EnvironmentVariables = append(raw.EnvironmentVariables, terraform.RawCodeBuildEnvironmentVariable{
+				Name:  awssdk.ToString(v.Name),
+				Value: awssdk.ToString(v.Value),
+				Type:  string(v.Type),
+			})
+		}
+	}
+	if c := p.Cache; c != nil {
+		raw.CacheType = string(c.Type)
+		raw.CacheLocation = awssdk.ToString(c.Location)
+		for _, mode := range c.Modes {
+			raw.CacheModes = append(raw.CacheModes, string(mode))
+		}
+	}
+	if lc := p.LogsConfig; lc != nil {
+		if cw := lc.CloudWatchLogs; cw != nil {
+			raw.CloudWatchLogsStatus = string(cw.Status)
+			raw.LogGroupName = awssdk.ToString(cw.GroupName)
+			raw.LogStreamName = awssdk.ToString(cw.StreamName)
+		}
+		if s3 := lc.S3Logs; s3 != nil {
+			raw.S3LogsStatus = string(s3.Status)
+			raw.S3LogsLocation = awssdk.ToString(s3.Location)
+		}
+	}
+	return raw
+}
@@ -0,0 +1,71 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// loadAwsConfig は cfg に従って AWS SDK v2 の設定を読み込み、クレデンシャルを構成する。
+//   - Region / Profile を指定した場合はそれを利用する
+//   - StaticCredentials を指定した場合は、それを基底のクレデンシャルとする
+//   - EndpointURL を指定した場合は、全サービスのエンドポイントとする
+//   - AssumeRoles を指定した場合は、基底のクレデンシャルから順にロールを引き受ける
+//   - Guard を指定した場合は、サービスクライアントの SDK 標準リトライを無効にする
+func loadAwsConfig(ctx context.Context, cfg AwsClientConfig) (awssdk.Config, error) {
+	var opts []func(*config.LoadOptions) error
+	if cfg.Region != "" {
+		opts = append(opts, config.WithRegion(cfg.Region))
+	}
+	if cfg.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(cfg.Profile))
+	}
+	if c := cfg.StaticCredentials; c != nil {
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(c.AccessKeyID, c.SecretAccessKey, c.SessionToken)))
+	}
+	if cfg.EndpointURL != "" {
+		opts = append(opts, config.WithBaseEndpoint(cfg.EndpointURL))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return awssdk.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	// ロールチェーンの AssumeRole 呼び出しは SDK 標準のリトライで行う
+	for _, role := range cfg.AssumeRoles {
+		awsCfg.Credentials = assumeRoleCredentials(awsCfg, role, cfg.serviceEndpoint(serviceSts))
+	}
+
+	if cfg.Guard != nil {
+		// リトライは APIGuard 側で行うため、SDK のリトライと二重にならないようにする
+		awsCfg.Retryer = func() awssdk.Retryer { return awssdk.NopRetryer{} }
+	}
+	return awsCfg, nil
+}
+
+// assumeRoleCredentials は base のクレデンシャルで role を引き受けるクレデンシャルプロバイダを返す。
+// 取得したクレデンシャルは有効期限までキャッシュされる。
+// stsEndpoint が nil でない場合は STS のエンドポイントを上書きする。
+func assumeRoleCredentials(base awssdk.Config, role terraform.AssumeRole, stsEndpoint *string) awssdk.CredentialsProvider {
+	client := sts.NewFromConfig(base, func(o *sts.Options) {
+		if stsEndpoint != nil {
+			o.BaseEndpoint = stsEndpoint
+		}
+	})
+	provider := stscreds.NewAssumeRoleProvider(client, role.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = role.SessionNameOrDefault()
+		if role.ExternalID != "" {
+			o.ExternalID = awssdk.String(role.ExternalID)
+		}
+	})
+	return awssdk.NewCredentialsCache(provider)
+}
@@ -2,27 +2,49 @@ package aws
 
 import (
 	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/codebuild"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 
 	"github.com/ukms/archaeform/pkg/terraform"
 )
 
 // CloudDiscovery は、任意クラウド上のスコープ（VPC など）に含まれる
-// リソース一覧を返す抽象インターフェース。
+// リソース一覧と、列挙処理の実行結果（DiscoveryReport）を返す抽象インターフェース。
 // F-01 詳細設計の CloudDiscovery に対応する。
 type CloudDiscovery interface {
-	ListResources(scope terraform.DiscoveryScope) ([]terraform.Resource, []terraform.Relation, error)
+	ListResources(ctx context.Context, scope terraform.DiscoveryScope) ([]terraform.Resource, []terraform.Relation, terraform.DiscoveryReport, error)
 }
 
 // AwsVpcDiscoveryService は AWS VPC 内リソース列挙のためのインターフェース。
 // F-01 詳細設計書のメソッド構成に対応する。
 type AwsVpcDiscoveryService interface {
 	// VPC 自体およびネットワーク構成
-	ListVpcs(ctx context.Context) ([]terraform.Resource, []terraform.Relation, error)
+	ListVpcs(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
 	ListSubnets(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
 	ListRouteTables(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
 	ListSecurityGroups(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
 	ListInternetGateways(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
 	ListNatGateways(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+	ListVpcEndpoints(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+	ListNetworkAcls(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+	ListVpcPeeringConnections(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+	ListTransitGatewayVpcAttachments(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+	ListNetworkInterfaces(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+	ListEips(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+	ListFlowLogs(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
 
 	// 代表的な常駐ワークロード
 	ListInstances(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
@@ -35,6 +57,14 @@ type AwsVpcDiscoveryService interface {
 	ListElastiCacheClusters(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
 	ListCodeBuildProjects(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
 	ListLambdaFunctions(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+
+	// VPC 内のワークロードが参照するグローバルなリソース
+	ListIamRoles(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+	ListKmsKeys(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+
+	// VPC 内のリソースの監視（ログの出力先・メトリクスアラーム）
+	ListCloudWatchLogGroups(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+	ListCloudWatchMetricAlarms(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
 }
 
 // Logger は F-01 で想定されている簡易ログインターフェース。
@@ -45,128 +75,352 @@ type Logger interface {
 	Errorf(format string, args ...any)
 }
 
-// 各 AWS クライアントは AWS SDK v2 のラッパとして定義する想定。
-// ここでは F-01 のテスト容易性のためにインターフェースのみ定義し、
-// 実装は後続タスクで追加する。
+// 各 AWS クライアントは AWS SDK v2 のラッパとして定義する。
+// メソッドシグネチャは SDK v2 のクライアントと同一にしているため、
+// *ec2.Client などをそのまま渡せるほか、テストではインメモリのフェイクに差し替えられる。
+// また ec2.NewDescribeXxxPaginator の引数としても利用できる。
 type Ec2API interface {
-	// TODO: DescribeVpcs, DescribeSubnets などを必要に応じて追加
+	DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
+	DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+	DescribeRouteTables(ctx context.Context, params *ec2.DescribeRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error)
+	DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+	DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error)
+	DescribeNatGateways(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error)
+	DescribeVpcEndpoints(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error)
+	DescribeNetworkAcls(ctx context.Context, params *ec2.DescribeNetworkAclsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkAclsOutput, error)
+	DescribeVpcPeeringConnections(ctx context.Context, params *ec2.DescribeVpcPeeringConnectionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcPeeringConnectionsOutput, error)
+	DescribeTransitGatewayVpcAttachments(ctx context.Context, params *ec2.DescribeTransitGatewayVpcAttachmentsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeTransitGatewayVpcAttachmentsOutput, error)
+	DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error)
+	DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error)
+	DescribeSecurityGroupRules(ctx context.Context, params *ec2.DescribeSecurityGroupRulesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupRulesOutput, error)
+	DescribeFlowLogs(ctx context.Context, params *ec2.DescribeFlowLogsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeFlowLogsOutput, error)
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
 }
 
+// ElbAPI は Elastic Load Balancing v2（ALB / NLB）の列挙に利用する。
 type ElbAPI interface {
-	// TODO: DescribeLoadBalancers などを必要に応じて追加
+	DescribeLoadBalancers(ctx context.Context, params *elbv2.DescribeLoadBalancersInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeLoadBalancersOutput, error)
+	DescribeTargetGroups(ctx context.Context, params *elbv2.DescribeTargetGroupsInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTargetGroupsOutput, error)
+	DescribeTargetHealth(ctx context.Context, params *elbv2.DescribeTargetHealthInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTargetHealthOutput, error)
+	DescribeListeners(ctx context.Context, params *elbv2.DescribeListenersInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeListenersOutput, error)
+	DescribeRules(ctx context.Context, params *elbv2.DescribeRulesInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeRulesOutput, error)
+	DescribeTags(ctx context.Context, params *elbv2.DescribeTagsInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTagsOutput, error)
 }
 
-type RdsAPI interface {
-	// TODO: DescribeDBInstances などを必要に応じて追加
+// AcmAPI は HTTPS / TLS リスナーが参照する ACM 証明書の取得に利用する。
+type AcmAPI interface {
+	DescribeCertificate(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error)
 }
 
-// awsVpcDiscoveryService は AwsVpcDiscoveryService / CloudDiscovery のデフォルト実装。
-type awsVpcDiscoveryService struct {
-	ec2    Ec2API
-	elb    ElbAPI
-	rds    RdsAPI
-	logger Logger
+// RdsAPI は RDS（DB インスタンス・Aurora クラスター・サブネット / パラメータ / オプショングループ）の列挙に利用する。
+type RdsAPI interface {
+	DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+	DescribeDBClusters(ctx context.Context, params *rds.DescribeDBClustersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error)
+	DescribeDBSubnetGroups(ctx context.Context, params *rds.DescribeDBSubnetGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBSubnetGroupsOutput, error)
+	DescribeDBParameterGroups(ctx context.Context, params *rds.DescribeDBParameterGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBParameterGroupsOutput, error)
+	DescribeDBParameters(ctx context.Context, params *rds.DescribeDBParametersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBParametersOutput, error)
+	DescribeDBClusterParameterGroups(ctx context.Context, params *rds.DescribeDBClusterParameterGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClusterParameterGroupsOutput, error)
+	DescribeDBClusterParameters(ctx context.Context, params *rds.DescribeDBClusterParametersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClusterParametersOutput, error)
+	DescribeOptionGroups(ctx context.Context, params *rds.DescribeOptionGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeOptionGroupsOutput, error)
+	ListTagsForResource(ctx context.Context, params *rds.ListTagsForResourceInput, optFns ...func(*rds.Options)) (*rds.ListTagsForResourceOutput, error)
 }
 
-// NewAwsVpcDiscoveryService は AwsVpcDiscoveryService を生成する。
-// CloudDiscovery としても利用できる。
-func NewAwsVpcDiscoveryService(ec2 Ec2API, elb ElbAPI, rds RdsAPI, logger Logger) *awsVpcDiscoveryService {
-	return &awsVpcDiscoveryService{
-		ec2:    ec2,
-		elb:    elb,
-		rds:    rds,
-		logger: logger,
-	}
+// ElastiCacheAPI は ElastiCache（レプリケーショングループ・クラスター・サブネット / パラメータグループ）の列挙に利用する。
+type ElastiCacheAPI interface {
+	DescribeCacheClusters(ctx context.Context, params *elasticache.DescribeCacheClustersInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error)
+	DescribeReplicationGroups(ctx context.Context, params *elasticache.DescribeReplicationGroupsInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error)
+	DescribeCacheSubnetGroups(ctx context.Context, params *elasticache.DescribeCacheSubnetGroupsInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheSubnetGroupsOutput, error)
+	DescribeCacheParameterGroups(ctx context.Context, params *elasticache.DescribeCacheParameterGroupsInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheParameterGroupsOutput, error)
+	DescribeCacheParameters(ctx context.Context, params *elasticache.DescribeCacheParametersInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheParametersOutput, error)
+	ListTagsForResource(ctx context.Context, params *elasticache.ListTagsForResourceInput, optFns ...func(*elasticache.Options)) (*elasticache.ListTagsForResourceOutput, error)
 }
 
-// ListResources は F-01 で定義された全体フローに従い、
-// 各種 ListXXX を順次呼び出して結果を集約する。
-// 初期実装では、まだ AWS API 連携を行わず、空の結果を返す。
-func (s *awsVpcDiscoveryService) ListResources(scope terraform.DiscoveryScope) ([]terraform.Resource, []terraform.Relation, error) {
-	ctx := context.Background()
-
-	s.logger.Infof("Starting VPC discovery: vpc_id=%s region=%s", scope.VpcID, scope.Region)
-
-	var allResources []terraform.Resource
-	var allRelations []terraform.Relation
-
-	// 1. VPC 存在確認および VPC リソース
-	vpcs, vpcRels, err := s.ListVpcs(ctx)
-	if err != nil {
-		s.logger.Errorf("failed to list VPCs: %v", err)
-		return nil, nil, err
-	}
-	allResources = append(allResources, vpcs...)
-	allRelations = append(allRelations, vpcRels...)
-
-	// 以降の呼び出しは、初期実装では「空実装」を想定。
-	// 後続コミットで順次 AWS API 連携を追加していく。
-
-	s.logger.Infof("Finished VPC discovery: resources=%d relations=%d", len(allResources), len(allRelations))
+// EcsAPI は ECS（クラスター・サービス・タスク定義・キャパシティプロバイダー）の列挙に利用する。
+type EcsAPI interface {
+	ListClusters(ctx context.Context, params *ecs.ListClustersInput, optFns ...func(*ecs.Options)) (*ecs.ListClustersOutput, error)
+	DescribeClusters(ctx context.Context, params *ecs.DescribeClustersInput, optFns ...func(*ecs.Options)) (*ecs.DescribeClustersOutput, error)
+	ListServices(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error)
+	DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error)
+	DescribeTaskDefinition(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error)
+	DescribeCapacityProviders(ctx context.Context, params *ecs.DescribeCapacityProvidersInput, optFns ...func(*ecs.Options)) (*ecs.DescribeCapacityProvidersOutput, error)
+}
 
-	return allResources, allRelations, nil
+// LambdaAPI は VPC に接続された Lambda 関数とイベントソースマッピングの列挙に利用する。
+type LambdaAPI interface {
+	ListFunctions(ctx context.Context, params *lambda.ListFunctionsInput, optFns ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error)
+	GetFunction(ctx context.Context, params *lambda.GetFunctionInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error)
+	ListEventSourceMappings(ctx context.Context, params *lambda.ListEventSourceMappingsInput, optFns ...func(*lambda.Options)) (*lambda.ListEventSourceMappingsOutput, error)
 }
 
-// ListVpcs は VPC 情報を列挙する。
-// 初期実装では空のスライスを返し、後続タスクで AWS API 連携を追加する。
-func (s *awsVpcDiscoveryService) ListVpcs(ctx context.Context) ([]terraform.Resource, []terraform.Relation, error) {
-	// TODO: AWS EC2 DescribeVpcs を呼び出す実装を追加する。
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// CodeBuildAPI は VPC に接続された CodeBuild プロジェクトの列挙に利用する。
+type CodeBuildAPI interface {
+	ListProjects(ctx context.Context, params *codebuild.ListProjectsInput, optFns ...func(*codebuild.Options)) (*codebuild.ListProjectsOutput, error)
+	BatchGetProjects(ctx context.Context, params *codebuild.BatchGetProjectsInput, optFns ...func(*codebuild.Options)) (*codebuild.BatchGetProjectsOutput, error)
 }
 
-// 以下のメソッドも同様にプレースホルダ実装とし、
-// 実際の AWS API 呼び出しは別コミットで行う。
+// IamAPI は VPC 内のワークロードが参照する IAM ロール・インスタンスプロファイル・ロールポリシーの取得に利用する。
+type IamAPI interface {
+	GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error)
+	GetInstanceProfile(ctx context.Context, params *iam.GetInstanceProfileInput, optFns ...func(*iam.Options)) (*iam.GetInstanceProfileOutput, error)
+	ListRolePolicies(ctx context.Context, params *iam.ListRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error)
+	GetRolePolicy(ctx context.Context, params *iam.GetRolePolicyInput, optFns ...func(*iam.Options)) (*iam.GetRolePolicyOutput, error)
+	ListAttachedRolePolicies(ctx context.Context, params *iam.ListAttachedRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error)
+}
 
-func (s *awsVpcDiscoveryService) ListSubnets(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// KmsAPI は VPC 内のリソースが暗号化に利用する KMS キーとエイリアスの取得に利用する。
+type KmsAPI interface {
+	DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error)
+	ListAliases(ctx context.Context, params *kms.ListAliasesInput, optFns ...func(*kms.Options)) (*kms.ListAliasesOutput, error)
+	GetKeyPolicy(ctx context.Context, params *kms.GetKeyPolicyInput, optFns ...func(*kms.Options)) (*kms.GetKeyPolicyOutput, error)
+	GetKeyRotationStatus(ctx context.Context, params *kms.GetKeyRotationStatusInput, optFns ...func(*kms.Options)) (*kms.GetKeyRotationStatusOutput, error)
+	ListResourceTags(ctx context.Context, params *kms.ListResourceTagsInput, optFns ...func(*kms.Options)) (*kms.ListResourceTagsOutput, error)
 }
 
-func (s *awsVpcDiscoveryService) ListRouteTables(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// CloudWatchLogsAPI は VPC 内のリソースのログの出力先ロググループの取得に利用する。
+type CloudWatchLogsAPI interface {
+	DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+	ListTagsForResource(ctx context.Context, params *cloudwatchlogs.ListTagsForResourceInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.ListTagsForResourceOutput, error)
 }
 
-func (s *awsVpcDiscoveryService) ListSecurityGroups(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// CloudWatchAPI は VPC 内のリソースを監視するメトリクスアラームの列挙に利用する。
+type CloudWatchAPI interface {
+	DescribeAlarms(ctx context.Context, params *cloudwatch.DescribeAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error)
+	ListTagsForResource(ctx context.Context, params *cloudwatch.ListTagsForResourceInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListTagsForResourceOutput, error)
 }
 
-func (s *awsVpcDiscoveryService) ListInternetGateways(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// StsAPI は呼び出し元の AWS アカウント ID の取得に利用する。
+type StsAPI interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
 }
 
-func (s *awsVpcDiscoveryService) ListNatGateways(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// awsVpcDiscoveryService は AwsVpcDiscoveryService / CloudDiscovery のデフォルト実装。
+// 各 ListXXX は ResourceLister として ListerRegistry に登録され、ListResources から依存関係順に呼び出される。
+type awsVpcDiscoveryService struct {
+	ec2         Ec2API
+	elb         ElbAPI
+	acm         AcmAPI
+	rds         RdsAPI
+	elasticache ElastiCacheAPI
+	ecs         EcsAPI
+	lambda      LambdaAPI
+	codebuild   CodeBuildAPI
+	iam         IamAPI
+	kms         KmsAPI
+	logs        CloudWatchLogsAPI
+	cloudwatch  CloudWatchAPI
+	logger      Logger
+
+	mapper   *terraform.AwsToResourceMapper
+	registry *ListerRegistry
+	// wrappers は ListResources 実行時に各 lister に適用するデコレータ（キャッシュ等）。
+	wrappers []func(ResourceLister) ResourceLister
+	// concurrency は ListXXX を並列実行するワーカー数。
+	concurrency int
 }
 
-func (s *awsVpcDiscoveryService) ListInstances(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// NewAwsVpcDiscoveryService は AwsVpcDiscoveryService を生成する。
+// CloudDiscovery としても利用できる。EC2 / ELB / RDS 以外のクライアントを使う場合は NewAwsVpcDiscoveryServiceWithClients を利用する。
+func NewAwsVpcDiscoveryService(ec2 Ec2API, elb ElbAPI, rds RdsAPI, logger Logger) *awsVpcDiscoveryService {
+	return NewAwsVpcDiscoveryServiceWithClients(&AwsClients{Ec2: ec2, Elb: elb, Rds: rds}, logger)
 }
 
-func (s *awsVpcDiscoveryService) ListLoadBalancers(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// NewAwsVpcDiscoveryServiceWithClients は clients の各サービスクライアントを用いる AwsVpcDiscoveryService を生成する。
+// nil のクライアント（EC2 を除く）を利用する lister は実行せず、DiscoveryReport に skipped として記録する。
+func NewAwsVpcDiscoveryServiceWithClients(clients *AwsClients, logger Logger) *awsVpcDiscoveryService {
+	s := &awsVpcDiscoveryService{
+		ec2:         clients.Ec2,
+		elb:         clients.Elb,
+		acm:         clients.Acm,
+		rds:         clients.Rds,
+		elasticache: clients.ElastiCache,
+		ecs:         clients.Ecs,
+		lambda:      clients.Lambda,
+		codebuild:   clients.CodeBuild,
+		iam:         clients.Iam,
+		kms:         clients.Kms,
+		logs:        clients.CloudWatchLogs,
+		cloudwatch:  clients.CloudWatch,
+		logger:      logger,
+		mapper:      terraform.NewAwsToResourceMapper(nil),
+		registry:    NewListerRegistry(),
+		concurrency: DefaultDiscoveryConcurrency,
+	}
+	listerClients := s.listerClients()
+	for _, l := range defaultListers(s) {
+		if c, ok := listerClients[l.TypeName()]; ok && !c.configured {
+			l = &unconfiguredLister{ResourceLister: l, service: c.service}
+		}
+		s.registry.MustRegister(l)
+	}
+	return s
 }
 
-func (s *awsVpcDiscoveryService) ListRdsInstances(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// listerClient は lister が利用するサービスクライアントと、それが設定されているかどうか。
+type listerClient struct {
+	service    string
+	configured bool
 }
 
-func (s *awsVpcDiscoveryService) ListEcsClusters(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// listerClients は defaultListers のうち EC2 以外のクライアントを利用する lister ごとの listerClient を返す。
+// ListLoadBalancers の ACM クライアントは任意のため含めない（nil の場合は証明書を ARN のまま出力する）。
+func (s *awsVpcDiscoveryService) listerClients() map[string]listerClient {
+	return map[string]listerClient{
+		"aws_lb":                      {serviceElb, s.elb != nil},
+		"aws_db_instance":             {serviceRds, s.rds != nil},
+		"aws_ecs_cluster":             {serviceEcs, s.ecs != nil},
+		"aws_ecs_service":             {serviceEcs, s.ecs != nil},
+		"aws_elasticache_cluster":     {serviceElastiCache, s.elasticache != nil},
+		"aws_codebuild_project":       {serviceCodeBuild, s.codebuild != nil},
+		"aws_lambda_function":         {serviceLambda, s.lambda != nil},
+		"aws_iam_role":                {serviceIam, s.iam != nil},
+		"aws_kms_key":                 {serviceKms, s.kms != nil},
+		"aws_cloudwatch_log_group":    {serviceCloudWatchLogs, s.logs != nil},
+		"aws_cloudwatch_metric_alarm": {serviceCloudWatch, s.cloudwatch != nil},
+	}
 }
 
-func (s *awsVpcDiscoveryService) ListEcsServices(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// defaultListers は AwsVpcDiscoveryService の各 ListXXX を ResourceLister として返す。
+// この順序が集約結果の順序になる。VPC 存在確認（aws_vpc）以外はすべて aws_vpc に依存する。
+func defaultListers(svc AwsVpcDiscoveryService) []ResourceLister {
+	vpc := []string{vpcListerName}
+	return []ResourceLister{
+		NewFuncLister(vpcListerName, nil, svc.ListVpcs),
+		NewFuncLister("aws_subnet", vpc, svc.ListSubnets),
+		NewFuncListerWithTypes("aws_route_table",
+			[]string{"aws_route_table", "aws_route", "aws_route_table_association", "aws_main_route_table_association"}, vpc, svc.ListRouteTables),
+		NewFuncLister("aws_internet_gateway", vpc, svc.ListInternetGateways),
+		NewFuncLister("aws_nat_gateway", vpc, svc.ListNatGateways),
+		NewFuncListerWithTypes("aws_security_group",
+			[]string{"aws_security_group", "aws_vpc_security_group_ingress_rule", "aws_vpc_security_group_egress_rule"}, vpc, svc.ListSecurityGroups),
+		NewFuncLister("aws_vpc_endpoint", vpc, svc.ListVpcEndpoints),
+		NewFuncListerWithTypes("aws_network_acl",
+			[]string{"aws_network_acl", "aws_default_network_acl", "aws_network_acl_rule"}, vpc, svc.ListNetworkAcls),
+		NewFuncListerWithTypes("aws_vpc_peering_connection",
+			[]string{"aws_vpc_peering_connection", "aws_vpc_peering_connection_accepter"}, vpc, svc.ListVpcPeeringConnections),
+		NewFuncListerWithTypes("aws_ec2_transit_gateway_vpc_attachment",
+			[]string{"aws_ec2_transit_gateway_vpc_attachment", "aws_ec2_transit_gateway"}, vpc, svc.ListTransitGatewayVpcAttachments),
+		NewFuncLister("aws_network_interface", vpc, svc.ListNetworkInterfaces),
+		// NAT ゲートウェイの EIP を関連付けるため、aws_nat_gateway の結果を参照する
+		NewFuncListerWithTypes("aws_eip", []string{"aws_eip", "aws_eip_association"},
+			[]string{vpcListerName, "aws_nat_gateway"}, svc.ListEips),
+		// サブネット単位のフローログを対象にするため、aws_subnet の結果を参照する
+		NewFuncLister("aws_flow_log", []string{vpcListerName, "aws_subnet"}, svc.ListFlowLogs),
+		NewFuncLister("aws_instance", vpc, svc.ListInstances),
+		NewFuncListerWithTypes("aws_lb",
+			[]string{"aws_lb", "aws_lb_target_group", "aws_lb_target_group_attachment", "aws_lb_listener", "aws_lb_listener_rule", "aws_acm_certificate"}, vpc, svc.ListLoadBalancers),
+		NewFuncListerWithTypes("aws_db_instance",
+			[]string{"aws_db_instance", "aws_rds_cluster", "aws_rds_cluster_instance", "aws_db_subnet_group", "aws_db_parameter_group", "aws_rds_cluster_parameter_group", "aws_db_option_group"}, vpc, svc.ListRdsInstances),
+		// ECS はサービスの awsvpc サブネットで VPC 内かどうかを判定するため、aws_subnet の結果を参照する
+		NewFuncListerWithTypes("aws_ecs_cluster",
+			[]string{"aws_ecs_cluster", "aws_ecs_cluster_capacity_providers", "aws_ecs_capacity_provider"}, []string{vpcListerName, "aws_subnet"}, svc.ListEcsClusters),
+		NewFuncListerWithTypes("aws_ecs_service",
+			[]string{"aws_ecs_service", "aws_ecs_task_definition"}, []string{vpcListerName, "aws_subnet", "aws_ecs_cluster"}, svc.ListEcsServices),
+		NewFuncListerWithTypes("aws_elasticache_cluster",
+			[]string{"aws_elasticache_cluster", "aws_elasticache_replication_group", "aws_elasticache_subnet_group", "aws_elasticache_parameter_group"}, vpc, svc.ListElastiCacheClusters),
+		NewFuncLister("aws_codebuild_project", vpc, svc.ListCodeBuildProjects),
+		NewFuncListerWithTypes("aws_lambda_function",
+			[]string{"aws_lambda_function", "aws_lambda_event_source_mapping"}, vpc, svc.ListLambdaFunctions),
+		// IAM ロールはワークロードが参照するものだけを取り込むため、各ワークロードの lister の結果を参照する
+		NewFuncListerWithTypes("aws_iam_role",
+			[]string{"aws_iam_role", "aws_iam_role_policy", "aws_iam_role_policy_attachment", "aws_iam_instance_profile"},
+			append([]string{vpcListerName}, iamWorkloadListers...), svc.ListIamRoles),
+		// KMS キーは暗号化に利用されているものだけを取り込むため、暗号化されたリソースの lister の結果を参照する
+		NewFuncListerWithTypes("aws_kms_key", []string{"aws_kms_key", "aws_kms_alias"},
+			append([]string{vpcListerName}, kmsEncryptedListers...), svc.ListKmsKeys),
+		// ロググループはログの出力元のリソース、アラームは監視対象のリソースの lister の結果を参照する
+		NewFuncLister("aws_cloudwatch_log_group", append([]string{vpcListerName}, logGroupSourceListers...), svc.ListCloudWatchLogGroups),
+		NewFuncLister("aws_cloudwatch_metric_alarm", append([]string{vpcListerName}, alarmTargetListers...), svc.ListCloudWatchMetricAlarms),
+	}
 }
 
-func (s *awsVpcDiscoveryService) ListElastiCacheClusters(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// Registry は ListResources が利用する ListerRegistry を返す。
+// 新しいリソースタイプの lister は、ディスカバリ開始前にここへ登録する。
+func (s *awsVpcDiscoveryService) Registry() *ListerRegistry {
+	return s.registry
 }
 
-func (s *awsVpcDiscoveryService) ListCodeBuildProjects(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// WrapListers は ListResources 実行時に各 lister へ適用するデコレータを追加する。
+// 追加した順に内側から適用する。
+func (s *awsVpcDiscoveryService) WrapListers(wrap func(ResourceLister) ResourceLister) {
+	s.wrappers = append(s.wrappers, wrap)
 }
 
-func (s *awsVpcDiscoveryService) ListLambdaFunctions(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
-	return []terraform.Resource{}, []terraform.Relation{}, nil
+// SetConcurrency は ListXXX を並列実行するワーカー数を設定する。
+// 1 未満の値は 1（逐次実行）として扱う。
+func (s *awsVpcDiscoveryService) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	s.concurrency = n
 }
 
+// ListResources は F-01 で定義された全体フローに従い、
+// VPC 存在確認の後、ListerRegistry に登録された lister を依存関係順にワーカープールで並列に呼び出して結果を集約する。
+// scope.ResourceFilters の type= で除外されるリソースタイプのみを出力する lister は実行しない。
+// ctx のキャンセル・タイムアウトは実行中の AWS API 呼び出しにも伝播する。
+// 結果は呼び出しの完了順によらず、lister の登録順で決定的に並ぶ。
+//
+// VPC 存在確認の失敗と ctx のキャンセルのみを致命的エラーとして扱う。
+// それ以外の lister の失敗は WARN ログを出した上で DiscoveryReport に記録し、列挙を継続する。
+func (s *awsVpcDiscoveryService) ListResources(ctx context.Context, scope terraform.DiscoveryScope) ([]terraform.Resource, []terraform.Relation, terraform.DiscoveryReport, error) {
+	var report terraform.DiscoveryReport
+
+	listers, excluded, err := s.registry.Plan(scope.ResourceFilters)
+	if err != nil {
+		return nil, nil, report, err
+	}
+	if len(excluded) > 0 {
+		s.logger.Infof("Skipping listers excluded by resource filters: %v", excluded)
+	}
+	for i := range listers {
+		for _, wrap := range s.wrappers {
+			listers[i] = wrap(listers[i])
+		}
+	}
+
+	s.logger.Infof("Starting VPC discovery: vpc_id=%s region=%s listers=%d concurrency=%d", scope.VpcID, scope.Region, len(listers), s.concurrency)
+
+	var allResources []terraform.Resource
+	var allRelations []terraform.Relation
+
+	results := runListers(ctx, listers, scope, s.concurrency)
+	if err := ctx.Err(); err != nil {
+		s.logger.Errorf("VPC discovery canceled: %v", err)
+		return nil, nil, report, err
+	}
+	for i, res := range results {
+		lr := res.report(listers[i].TypeName())
+		report.Listers = append(report.Listers, lr)
+
+		if lr.Name == vpcListerName && res.err != nil {
+			// VPC 存在確認の失敗
+			s.logger.Errorf("failed to list VPCs: %v", res.err)
+			return nil, nil, report, res.err
+		}
+		switch lr.Status {
+		case terraform.ListerStatusSkipped:
+			if errors.Is(res.err, ErrClientNotConfigured) {
+				s.logger.Infof("skipped %s: %v", lr.Name, res.err)
+			} else {
+				s.logger.Warnf("skipped %s due to missing permissions: %v", lr.Name, res.err)
+			}
+			continue
+		case terraform.ListerStatusFailed:
+			s.logger.Warnf("failed to list %s, these resources will not be imported: %v", lr.Name, res.err)
+			continue
+		}
+		allResources = append(allResources, res.resources...)
+		allRelations = append(allRelations, res.relations...)
+	}
+
+	s.logger.Infof("Finished VPC discovery: resources=%d relations=%d failed=%d skipped=%d cached=%d",
+		len(allResources), len(allRelations),
+		report.CountByStatus(terraform.ListerStatusFailed), report.CountByStatus(terraform.ListerStatusSkipped),
+		report.CacheHits())
+
+	return allResources, allRelations, report, nil
+}
 
+// ネットワーク系 ListXXX（ListVpcs〜ListNetworkAcls）の実装は ec2_network.go、
+// VPC ピアリング・Transit Gateway は ec2_transit.go、ENI・EIP は ec2_interfaces.go、EC2 インスタンスは ec2_instances.go、
+// ロードバランサーは elb.go、RDS は rds.go、ECS は ecs.go、ElastiCache は elasticache.go、Lambda は lambda.go、CodeBuild は codebuild.go、IAM は iam.go、KMS は kms.go、フローログは ec2_flow_logs.go、CloudWatch は cloudwatch.go を参照。
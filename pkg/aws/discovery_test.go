@@ -0,0 +1,144 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// TestListResourcesConcurrentScopes は 1 つのサービスで複数のスコープを同時に列挙しても、
+// 各スコープの Resource に自身のリージョンのラベルが付くことを確認する。
+func TestListResourcesConcurrentScopes(t *testing.T) {
+	s := NewAwsVpcDiscoveryService(newFakeEc2(0), nil, nil, nopLogger{})
+
+	regions := []string{"ap-northeast-1", "us-east-1", "eu-west-1", "us-west-2"}
+	results := make([][]terraform.Resource, len(regions))
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rs, _, _, err := s.ListResources(context.Background(), terraform.DiscoveryScope{
+				VpcID:           "vpc-1",
+				Region:          region,
+				ResourceFilters: []terraform.ResourceFilter{{Type: "aws_subnet"}},
+			})
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", region, err)
+			}
+			results[i] = rs
+		}()
+	}
+	wg.Wait()
+
+	for i, rs := range results {
+		if len(rs) == 0 {
+			t.Errorf("%s: no resources", regions[i])
+		}
+		for _, r := range rs {
+			if got := r.Labels["aws_region"]; got != regions[i] {
+				t.Errorf("%s: %s labeled with region %q", regions[i], r.ID, got)
+			}
+		}
+	}
+}
+
+// TestListResourcesNilClients は nil のクライアントを利用する lister が実行されずに skipped として記録され、
+// それに依存する lister と他の lister の列挙は継続されることを確認する。
+func TestListResourcesNilClients(t *testing.T) {
+	s := NewAwsVpcDiscoveryService(newFakeEc2(0), nil, nil, nopLogger{})
+
+	// fakeEc2 が実装する API だけで列挙できる aws_subnet と、クライアントが nil の lister を対象にする
+	filters := []terraform.ResourceFilter{{Type: "aws_subnet"}}
+	for name := range s.listerClients() {
+		filters = append(filters, terraform.ResourceFilter{Type: name})
+	}
+	rs, _, report, err := s.ListResources(context.Background(), terraform.DiscoveryScope{VpcID: "vpc-1", Region: "ap-northeast-1", ResourceFilters: filters})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	findResource(t, rs, "aws:aws_subnet:subnet-a")
+
+	status := make(map[string]terraform.ListerStatus)
+	for _, l := range report.Listers {
+		status[l.Name] = l.Status
+		if l.Status == terraform.ListerStatusSkipped && !errors.Is(l.Err, ErrClientNotConfigured) {
+			t.Errorf("%s: unexpected error: %v", l.Name, l.Err)
+		}
+	}
+	for name := range s.listerClients() {
+		if status[name] != terraform.ListerStatusSkipped {
+			t.Errorf("%s: status = %q, want skipped", name, status[name])
+		}
+	}
+	if status["aws_subnet"] != terraform.ListerStatusOK {
+		t.Errorf("aws_subnet: status = %q, want ok", status["aws_subnet"])
+	}
+	if n := report.CountByStatus(terraform.ListerStatusFailed); n != 0 {
+		t.Errorf("failed listers = %d, want 0", n)
+	}
+}
+
+// TestListResourcesListerPanic は lister の panic がその lister の失敗として記録されることを確認する。
+func TestListResourcesListerPanic(t *testing.T) {
+	s := NewAwsVpcDiscoveryService(newFakeEc2(0), nil, nil, nopLogger{})
+	s.Registry().MustRegister(NewFuncLister("panicking", []string{vpcListerName}, func(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+		panic("boom")
+	}))
+
+	_, _, report, err := s.ListResources(context.Background(), terraform.DiscoveryScope{
+		VpcID:           "vpc-1",
+		Region:          "ap-northeast-1",
+		ResourceFilters: []terraform.ResourceFilter{{Type: "panicking"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, l := range report.Listers {
+		if l.Name != "panicking" {
+			continue
+		}
+		if l.Status != terraform.ListerStatusFailed || !strings.Contains(l.Error, "boom") {
+			t.Errorf("status = %q, error = %q, want failed with the panic value", l.Status, l.Error)
+		}
+		return
+	}
+	t.Errorf("no report for the panicking lister")
+}
+
+// TestListResourcesSkippedDependency は権限不足で skipped になった lister に依存する lister が、
+// 依存先の失敗とみなされずに実行されることを確認する。
+func TestListResourcesSkippedDependency(t *testing.T) {
+	s := NewAwsVpcDiscoveryService(newFakeEc2(0), nil, nil, nopLogger{})
+	s.Registry().MustRegister(NewFuncLister("denied", []string{vpcListerName}, func(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+		return nil, nil, errAccessDenied
+	}))
+	s.Registry().MustRegister(NewFuncLister("dependent", []string{"denied"}, func(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+		return []terraform.Resource{{ID: "aws:dependent:1", Type: "dependent"}}, nil, nil
+	}))
+
+	rs, _, report, err := s.ListResources(context.Background(), terraform.DiscoveryScope{
+		VpcID:           "vpc-1",
+		Region:          "ap-northeast-1",
+		ResourceFilters: []terraform.ResourceFilter{{Type: "dependent"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	findResource(t, rs, "aws:dependent:1")
+
+	status := make(map[string]terraform.ListerStatus)
+	for _, l := range report.Listers {
+		status[l.Name] = l.Status
+	}
+	if status["denied"] != terraform.ListerStatusSkipped {
+		t.Errorf("denied: status = %q, want skipped", status["denied"])
+	}
+	if status["dependent"] != terraform.ListerStatusOK {
+		t.Errorf("dependent: status = %q, want ok", status["dependent"])
+	}
+}
@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// ListFlowLogs は DescribeFlowLogs で、VPC とそのサブネットに設定されたフローログを列挙する。
+// ENI / Transit Gateway 単位のフローログは対象外。
+func (s *awsVpcDiscoveryService) ListFlowLogs(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	resourceIDs := []string{vpcID}
+	for id := range upstreamSubnetIDs(ctx) {
+		resourceIDs = append(resourceIDs, id)
+	}
+	sort.Strings(resourceIDs[1:])
+
+	var raws []terraform.RawFlowLog
+	p := ec2.NewDescribeFlowLogsPaginator(s.ec2, &ec2.DescribeFlowLogsInput{
+		Filter:     ec2Filter("resource-id", resourceIDs...),
+		MaxResults: awssdk.Int32(ec2MaxResults),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeFlowLogs: %w", err)
+		}
+		for _, fl := range page.FlowLogs {
+			raw := terraform.RawFlowLog{
+				ID:                     awssdk.ToString(fl.FlowLogId),
+				VpcID:                  vpcID,
+				TrafficType:            string(fl.TrafficType),
+				LogDestinationType:     string(fl.LogDestinationType),
+				LogDestination:         awssdk.ToString(fl.LogDestination),
+				LogGroupName:           awssdk.ToString(fl.LogGroupName),
+				IamRoleArn:             awssdk.ToString(fl.DeliverLogsPermissionArn),
+				LogFormat:              awssdk.ToString(fl.LogFormat),
+				MaxAggregationInterval: awssdk.ToInt32(fl.MaxAggregationInterval),
+				Tags:                   tagsToMap(fl.Tags),
+			}
+			if id := awssdk.ToString(fl.ResourceId); strings.HasPrefix(id, "subnet-") {
+				raw.SubnetID = id
+			}
+			raws = append(raws, raw)
+		}
+	}
+
+	return s.mapper.MapFlowLog(raws, ScopeRegion(ctx))
+}
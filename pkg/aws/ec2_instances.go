@@ -0,0 +1,62 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// autoScalingGroupTag は Auto Scaling グループが起動したインスタンスに付与されるタグ。
+const autoScalingGroupTag = "aws:autoscaling:groupName"
+
+// ListInstances は DescribeInstances で VPC 内の EC2 インスタンスを列挙する。
+// 終了済み・終了処理中のインスタンスと、Auto Scaling グループが管理するインスタンス（グループ側で管理されるため import しない）は対象外。
+func (s *awsVpcDiscoveryService) ListInstances(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var raws []terraform.RawInstance
+
+	p := ec2.NewDescribeInstancesPaginator(s.ec2, &ec2.DescribeInstancesInput{
+		Filters:    append(ec2Filter("vpc-id", vpcID), ec2Filter("instance-state-name", "pending", "running", "stopping", "stopped")...),
+		MaxResults: awssdk.Int32(ec2MaxResults),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeInstances: %w", err)
+		}
+		for _, r := range page.Reservations {
+			for _, inst := range r.Instances {
+				tags := tagsToMap(inst.Tags)
+				if tags[autoScalingGroupTag] != "" {
+					continue
+				}
+				raw := terraform.RawInstance{
+					ID:           awssdk.ToString(inst.InstanceId),
+					Ami:          awssdk.ToString(inst.ImageId),
+					InstanceType: string(inst.InstanceType),
+					VpcID:        awssdk.ToString(inst.VpcId),
+					SubnetID:     awssdk.ToString(inst.SubnetId),
+					Tags:         tags,
+				}
+				for _, g := range inst.SecurityGroups {
+					raw.SecurityGroupIDs = append(raw.SecurityGroupIDs, awssdk.ToString(g.GroupId))
+				}
+				sort.Strings(raw.SecurityGroupIDs)
+				// DescribeInstances はインスタンスプロファイルの ARN のみを返す（名前は ARN の最後の要素）
+				if prof := inst.IamInstanceProfile; prof != nil {
+					if arn := awssdk.ToString(prof.Arn); arn != "" {
+						raw.IamInstanceProfile = arn[strings.LastIndex(arn, "/")+1:]
+					}
+				}
+				raws = append(raws, raw)
+			}
+		}
+	}
+
+	return s.mapper.MapInstance(raws, ScopeRegion(ctx))
+}
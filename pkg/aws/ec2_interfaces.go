@@ -0,0 +1,142 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// describeVpcNetworkInterfaces は DescribeNetworkInterfaces で VPC 内のすべての ENI を列挙する。
+func (s *awsVpcDiscoveryService) describeVpcNetworkInterfaces(ctx context.Context, vpcID string) ([]ec2types.NetworkInterface, error) {
+	var enis []ec2types.NetworkInterface
+
+	p := ec2.NewDescribeNetworkInterfacesPaginator(s.ec2, &ec2.DescribeNetworkInterfacesInput{
+		Filters:    ec2Filter("vpc-id", vpcID),
+		MaxResults: awssdk.Int32(ec2MaxResults),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeNetworkInterfaces: %w", err)
+		}
+		enis = append(enis, page.NetworkInterfaces...)
+	}
+	return enis, nil
+}
+
+// isManagedNetworkInterface は ENI を aws_network_interface として import すべきかどうかを判定する。
+//   - ELB / Lambda / RDS / NAT ゲートウェイ / VPC エンドポイント等が作成したもの（requester-managed）は除外する
+//   - インスタンスのプライマリ ENI（device index 0）は aws_instance と一体で管理されるため除外する
+func isManagedNetworkInterface(eni ec2types.NetworkInterface) bool {
+	if awssdk.ToBool(eni.RequesterManaged) {
+		return false
+	}
+	switch eni.InterfaceType {
+	case "", ec2types.NetworkInterfaceTypeInterface, ec2types.NetworkInterfaceTypeEfa,
+		ec2types.NetworkInterfaceTypeBranch, ec2types.NetworkInterfaceTypeTrunk:
+	default:
+		return false
+	}
+	if a := eni.Attachment; a != nil && awssdk.ToString(a.InstanceId) != "" && awssdk.ToInt32(a.DeviceIndex) == 0 {
+		return false
+	}
+	return true
+}
+
+// ListNetworkInterfaces は VPC 内の ENI のうち、利用者が作成・管理しているものを列挙する。
+func (s *awsVpcDiscoveryService) ListNetworkInterfaces(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	enis, err := s.describeVpcNetworkInterfaces(ctx, vpcID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var raws []terraform.RawNetworkInterface
+	for _, eni := range enis {
+		if !isManagedNetworkInterface(eni) {
+			continue
+		}
+		raw := terraform.RawNetworkInterface{
+			ID:              awssdk.ToString(eni.NetworkInterfaceId),
+			VpcID:           awssdk.ToString(eni.VpcId),
+			SubnetID:        awssdk.ToString(eni.SubnetId),
+			Description:     awssdk.ToString(eni.Description),
+			InterfaceType:   string(eni.InterfaceType),
+			SourceDestCheck: awssdk.ToBool(eni.SourceDestCheck),
+			Tags:            tagsToMap(eni.TagSet),
+		}
+		for _, ip := range eni.PrivateIpAddresses {
+			raw.PrivateIPs = append(raw.PrivateIPs, awssdk.ToString(ip.PrivateIpAddress))
+		}
+		for _, g := range eni.Groups {
+			raw.SecurityGroupIDs = append(raw.SecurityGroupIDs, awssdk.ToString(g.GroupId))
+		}
+		if a := eni.Attachment; a != nil && awssdk.ToString(a.InstanceId) != "" {
+			raw.AttachmentInstanceID = awssdk.ToString(a.InstanceId)
+			raw.AttachmentDeviceIndex = awssdk.ToInt32(a.DeviceIndex)
+		}
+		raws = append(raws, raw)
+	}
+
+	return s.mapper.MapNetworkInterface(raws, ScopeRegion(ctx))
+}
+
+// ListEips は DescribeAddresses で、VPC 内の ENI に関連付けられた Elastic IP を列挙する。
+// Elastic IP 自体は VPC に属さないため、未関連付けのもの・他 VPC で利用中のものは対象外とする。
+// NAT ゲートウェイの EIP は、aws_nat_gateway の allocation_id から所有者を特定する。
+func (s *awsVpcDiscoveryService) ListEips(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	enis, err := s.describeVpcNetworkInterfaces(ctx, vpcID)
+	if err != nil {
+		return nil, nil, err
+	}
+	eniByID := make(map[string]ec2types.NetworkInterface, len(enis))
+	for _, eni := range enis {
+		eniByID[awssdk.ToString(eni.NetworkInterfaceId)] = eni
+	}
+
+	natByAllocation := make(map[string]string)
+	for _, nat := range UpstreamResources(ctx, "aws_nat_gateway") {
+		if alloc, ok := nat.Attributes["allocation_id"].(string); ok && alloc != "" {
+			natID, _ := nat.Attributes["id"].(string)
+			natByAllocation[alloc] = natID
+		}
+	}
+
+	// DescribeAddresses はページングをサポートしない
+	out, err := s.ec2.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{
+		Filters: []ec2types.Filter{{Name: awssdk.String("domain"), Values: []string{"vpc"}}},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("DescribeAddresses: %w", err)
+	}
+
+	var raws []terraform.RawEip
+	for _, addr := range out.Addresses {
+		eniID := awssdk.ToString(addr.NetworkInterfaceId)
+		eni, ok := eniByID[eniID]
+		if !ok {
+			continue
+		}
+		raw := terraform.RawEip{
+			AllocationID:       awssdk.ToString(addr.AllocationId),
+			PublicIP:           awssdk.ToString(addr.PublicIp),
+			VpcID:              vpcID,
+			NetworkInterfaceID: eniID,
+			PrivateIPAddress:   awssdk.ToString(addr.PrivateIpAddress),
+			InstanceID:         awssdk.ToString(addr.InstanceId),
+			NatGatewayID:       natByAllocation[awssdk.ToString(addr.AllocationId)],
+			Tags:               tagsToMap(addr.Tags),
+		}
+		// 関連付けを AWS サービス側が管理している場合は aws_eip_association を生成しない
+		if !awssdk.ToBool(eni.RequesterManaged) && raw.NatGatewayID == "" {
+			raw.AssociationID = awssdk.ToString(addr.AssociationId)
+		}
+		raws = append(raws, raw)
+	}
+
+	return s.mapper.MapEip(raws, ScopeRegion(ctx))
+}
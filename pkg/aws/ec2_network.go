@@ -0,0 +1,433 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// ec2MaxResults は Describe 系 API の 1 ページあたりの取得件数。
+const ec2MaxResults int32 = 100
+
+// ec2Filter は name の値が values のいずれかに一致するもので絞り込むための EC2 フィルタを返す。
+func ec2Filter(name string, values ...string) []ec2types.Filter {
+	return []ec2types.Filter{
+		{Name: awssdk.String(name), Values: values},
+	}
+}
+
+// tagsToMap は EC2 のタグ配列を map に変換する。
+func tagsToMap(tags []ec2types.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, t := range tags {
+		m[awssdk.ToString(t.Key)] = awssdk.ToString(t.Value)
+	}
+	return m
+}
+
+// ListVpcs は DescribeVpcs で対象 VPC を取得する。
+// VPC が存在しない場合はエラーを返す（F-01 の VPC 存在確認）。
+func (s *awsVpcDiscoveryService) ListVpcs(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var raws []terraform.RawVpc
+
+	p := ec2.NewDescribeVpcsPaginator(s.ec2, &ec2.DescribeVpcsInput{
+		VpcIds: []string{vpcID},
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeVpcs: %w", err)
+		}
+		for _, v := range page.Vpcs {
+			raws = append(raws, terraform.RawVpc{
+				ID:              awssdk.ToString(v.VpcId),
+				CidrBlock:       awssdk.ToString(v.CidrBlock),
+				InstanceTenancy: string(v.InstanceTenancy),
+				IsDefault:       awssdk.ToBool(v.IsDefault),
+				Tags:            tagsToMap(v.Tags),
+			})
+		}
+	}
+
+	if len(raws) == 0 {
+		return nil, nil, fmt.Errorf("vpc %s not found", vpcID)
+	}
+
+	return s.mapper.MapVpc(raws, ScopeRegion(ctx))
+}
+
+// ListSubnets は DescribeSubnets で VPC 内のサブネットを列挙する。
+func (s *awsVpcDiscoveryService) ListSubnets(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var raws []terraform.RawSubnet
+
+	p := ec2.NewDescribeSubnetsPaginator(s.ec2, &ec2.DescribeSubnetsInput{
+		Filters:    ec2Filter("vpc-id", vpcID),
+		MaxResults: awssdk.Int32(ec2MaxResults),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeSubnets: %w", err)
+		}
+		for _, sn := range page.Subnets {
+			tags := tagsToMap(sn.Tags)
+			raws = append(raws, terraform.RawSubnet{
+				ID:                  awssdk.ToString(sn.SubnetId),
+				VpcID:               awssdk.ToString(sn.VpcId),
+				CidrBlock:           awssdk.ToString(sn.CidrBlock),
+				Name:                tags["Name"],
+				Tags:                tags,
+				Az:                  awssdk.ToString(sn.AvailabilityZone),
+				MapPublicIpOnLaunch: awssdk.ToBool(sn.MapPublicIpOnLaunch),
+			})
+		}
+	}
+
+	return s.mapper.MapSubnet(raws, ScopeRegion(ctx))
+}
+
+// ListRouteTables は DescribeRouteTables で VPC 内のルートテーブルを、ルート・関連付けとともに列挙する。
+func (s *awsVpcDiscoveryService) ListRouteTables(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var raws []terraform.RawRouteTable
+
+	p := ec2.NewDescribeRouteTablesPaginator(s.ec2, &ec2.DescribeRouteTablesInput{
+		Filters:    ec2Filter("vpc-id", vpcID),
+		MaxResults: awssdk.Int32(ec2MaxResults),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeRouteTables: %w", err)
+		}
+		for _, rt := range page.RouteTables {
+			raw := terraform.RawRouteTable{
+				ID:    awssdk.ToString(rt.RouteTableId),
+				VpcID: awssdk.ToString(rt.VpcId),
+				Tags:  tagsToMap(rt.Tags),
+			}
+			for _, r := range rt.Routes {
+				if route, ok := rawRoute(r); ok {
+					raw.Routes = append(raw.Routes, route)
+				}
+			}
+			for _, a := range rt.Associations {
+				if a.AssociationState != nil {
+					switch a.AssociationState.State {
+					case ec2types.RouteTableAssociationStateCodeDisassociated, ec2types.RouteTableAssociationStateCodeDisassociating,
+						ec2types.RouteTableAssociationStateCodeFailed:
+						continue
+					}
+				}
+				raw.Associations = append(raw.Associations, terraform.RawRouteTableAssociation{
+					ID:        awssdk.ToString(a.RouteTableAssociationId),
+					SubnetID:  awssdk.ToString(a.SubnetId),
+					GatewayID: awssdk.ToString(a.GatewayId),
+					Main:      awssdk.ToBool(a.Main),
+				})
+			}
+			raws = append(raws, raw)
+		}
+	}
+
+	return s.mapper.MapRouteTable(raws, ScopeRegion(ctx))
+}
+
+// rawRoute は aws_route として管理すべきルートを RawRoute に変換する。
+// 以下のルートは別の仕組みで作成・管理されるため false を返す。
+//   - VPC 作成時のローカルルート（CreateRouteTable 由来 / gateway_id = local）
+//   - 仮想プライベートゲートウェイからのルート伝播
+//   - ゲートウェイ型 VPC エンドポイント（S3 / DynamoDB）がプレフィックスリスト宛てに作成するルート
+func rawRoute(r ec2types.Route) (terraform.RawRoute, bool) {
+	gatewayID := awssdk.ToString(r.GatewayId)
+	if r.Origin == ec2types.RouteOriginCreateRouteTable || r.Origin == ec2types.RouteOriginEnableVgwRoutePropagation || gatewayID == "local" {
+		return terraform.RawRoute{}, false
+	}
+
+	route := terraform.RawRoute{
+		DestinationCidrBlock:     awssdk.ToString(r.DestinationCidrBlock),
+		DestinationIpv6CidrBlock: awssdk.ToString(r.DestinationIpv6CidrBlock),
+		DestinationPrefixListID:  awssdk.ToString(r.DestinationPrefixListId),
+		EgressOnlyGatewayID:      awssdk.ToString(r.EgressOnlyInternetGatewayId),
+		NatGatewayID:             awssdk.ToString(r.NatGatewayId),
+		NetworkInterfaceID:       awssdk.ToString(r.NetworkInterfaceId),
+		VpcPeeringConnectionID:   awssdk.ToString(r.VpcPeeringConnectionId),
+		TransitGatewayID:         awssdk.ToString(r.TransitGatewayId),
+		CarrierGatewayID:         awssdk.ToString(r.CarrierGatewayId),
+		LocalGatewayID:           awssdk.ToString(r.LocalGatewayId),
+		CoreNetworkArn:           awssdk.ToString(r.CoreNetworkArn),
+	}
+	// VPC エンドポイント宛てのルートは gateway_id に vpce- が入る
+	if strings.HasPrefix(gatewayID, "vpce-") {
+		if route.DestinationPrefixListID != "" {
+			return terraform.RawRoute{}, false
+		}
+		route.VpcEndpointID = gatewayID
+	} else {
+		route.GatewayID = gatewayID
+	}
+	return route, true
+}
+
+// ListSecurityGroups は DescribeSecurityGroups で VPC 内のセキュリティグループを列挙し、
+// DescribeSecurityGroupRules で取得したルールを各セキュリティグループに割り当てる。
+func (s *awsVpcDiscoveryService) ListSecurityGroups(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var raws []terraform.RawSecurityGroup
+
+	p := ec2.NewDescribeSecurityGroupsPaginator(s.ec2, &ec2.DescribeSecurityGroupsInput{
+		Filters:    ec2Filter("vpc-id", vpcID),
+		MaxResults: awssdk.Int32(ec2MaxResults),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeSecurityGroups: %w", err)
+		}
+		for _, sg := range page.SecurityGroups {
+			raws = append(raws, terraform.RawSecurityGroup{
+				ID:          awssdk.ToString(sg.GroupId),
+				VpcID:       awssdk.ToString(sg.VpcId),
+				GroupName:   awssdk.ToString(sg.GroupName),
+				Description: awssdk.ToString(sg.Description),
+				Tags:        tagsToMap(sg.Tags),
+			})
+		}
+	}
+
+	groupIDs := make([]string, len(raws))
+	for i, g := range raws {
+		groupIDs[i] = g.ID
+	}
+	rules, err := s.describeSecurityGroupRules(ctx, groupIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range raws {
+		raws[i].Rules = rules[raws[i].ID]
+	}
+
+	return s.mapper.MapSecurityGroup(raws, ScopeRegion(ctx))
+}
+
+// securityGroupRuleFilterChunk は DescribeSecurityGroupRules の group-id フィルタに一度に指定する SG 数。
+const securityGroupRuleFilterChunk = 200
+
+// describeSecurityGroupRules は groupIDs のルールを取得し、SG ID ごとに（ルール ID 順で）返す。
+func (s *awsVpcDiscoveryService) describeSecurityGroupRules(ctx context.Context, groupIDs []string) (map[string][]terraform.RawSecurityGroupRule, error) {
+	rules := make(map[string][]terraform.RawSecurityGroupRule)
+
+	for start := 0; start < len(groupIDs); start += securityGroupRuleFilterChunk {
+		end := min(start+securityGroupRuleFilterChunk, len(groupIDs))
+
+		p := ec2.NewDescribeSecurityGroupRulesPaginator(s.ec2, &ec2.DescribeSecurityGroupRulesInput{
+			Filters:    ec2Filter("group-id", groupIDs[start:end]...),
+			MaxResults: awssdk.Int32(ec2MaxResults),
+		})
+		for p.HasMorePages() {
+			page, err := p.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("DescribeSecurityGroupRules: %w", err)
+			}
+			for _, r := range page.SecurityGroupRules {
+				raw := terraform.RawSecurityGroupRule{
+					ID:           awssdk.ToString(r.SecurityGroupRuleId),
+					GroupID:      awssdk.ToString(r.GroupId),
+					IsEgress:     awssdk.ToBool(r.IsEgress),
+					IpProtocol:   awssdk.ToString(r.IpProtocol),
+					FromPort:     awssdk.ToInt32(r.FromPort),
+					ToPort:       awssdk.ToInt32(r.ToPort),
+					CidrIpv4:     awssdk.ToString(r.CidrIpv4),
+					CidrIpv6:     awssdk.ToString(r.CidrIpv6),
+					PrefixListID: awssdk.ToString(r.PrefixListId),
+					Description:  awssdk.ToString(r.Description),
+					Tags:         tagsToMap(r.Tags),
+				}
+				if r.ReferencedGroupInfo != nil {
+					raw.ReferencedGroupID = awssdk.ToString(r.ReferencedGroupInfo.GroupId)
+				}
+				rules[raw.GroupID] = append(rules[raw.GroupID], raw)
+			}
+		}
+	}
+
+	for _, rs := range rules {
+		sort.Slice(rs, func(i, j int) bool { return rs[i].ID < rs[j].ID })
+	}
+	return rules, nil
+}
+
+// ListInternetGateways は DescribeInternetGateways で VPC にアタッチされた IGW を列挙する。
+func (s *awsVpcDiscoveryService) ListInternetGateways(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var raws []terraform.RawInternetGateway
+
+	p := ec2.NewDescribeInternetGatewaysPaginator(s.ec2, &ec2.DescribeInternetGatewaysInput{
+		Filters:    ec2Filter("attachment.vpc-id", vpcID),
+		MaxResults: awssdk.Int32(ec2MaxResults),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeInternetGateways: %w", err)
+		}
+		for _, igw := range page.InternetGateways {
+			raw := terraform.RawInternetGateway{
+				ID:   awssdk.ToString(igw.InternetGatewayId),
+				Tags: tagsToMap(igw.Tags),
+			}
+			for _, a := range igw.Attachments {
+				if awssdk.ToString(a.VpcId) == vpcID {
+					raw.VpcID = vpcID
+				}
+			}
+			raws = append(raws, raw)
+		}
+	}
+
+	return s.mapper.MapInternetGateway(raws, ScopeRegion(ctx))
+}
+
+// ListNatGateways は DescribeNatGateways で VPC 内の NAT ゲートウェイを列挙する。
+// 削除済み・削除中のものは import 対象外とする。
+func (s *awsVpcDiscoveryService) ListNatGateways(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var raws []terraform.RawNatGateway
+
+	p := ec2.NewDescribeNatGatewaysPaginator(s.ec2, &ec2.DescribeNatGatewaysInput{
+		Filter:     ec2Filter("vpc-id", vpcID),
+		MaxResults: awssdk.Int32(ec2MaxResults),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeNatGateways: %w", err)
+		}
+		for _, ng := range page.NatGateways {
+			if ng.State == ec2types.NatGatewayStateDeleted || ng.State == ec2types.NatGatewayStateDeleting {
+				continue
+			}
+			raw := terraform.RawNatGateway{
+				ID:               awssdk.ToString(ng.NatGatewayId),
+				VpcID:            awssdk.ToString(ng.VpcId),
+				SubnetID:         awssdk.ToString(ng.SubnetId),
+				ConnectivityType: string(ng.ConnectivityType),
+				Tags:             tagsToMap(ng.Tags),
+			}
+			for _, addr := range ng.NatGatewayAddresses {
+				if awssdk.ToBool(addr.IsPrimary) || raw.AllocationID == "" {
+					raw.AllocationID = awssdk.ToString(addr.AllocationId)
+				}
+			}
+			raws = append(raws, raw)
+		}
+	}
+
+	return s.mapper.MapNatGateway(raws, ScopeRegion(ctx))
+}
+
+// ListVpcEndpoints は DescribeVpcEndpoints で VPC 内の VPC エンドポイント（Gateway / Interface 等）を列挙する。
+// 削除済み・削除中・失敗状態のものは import 対象外とする。
+func (s *awsVpcDiscoveryService) ListVpcEndpoints(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var raws []terraform.RawVpcEndpoint
+
+	p := ec2.NewDescribeVpcEndpointsPaginator(s.ec2, &ec2.DescribeVpcEndpointsInput{
+		Filters:    ec2Filter("vpc-id", vpcID),
+		MaxResults: awssdk.Int32(ec2MaxResults),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeVpcEndpoints: %w", err)
+		}
+		for _, ep := range page.VpcEndpoints {
+			switch ep.State {
+			case ec2types.StateDeleted, ec2types.StateDeleting, ec2types.StateFailed:
+				continue
+			}
+			raw := terraform.RawVpcEndpoint{
+				ID:                awssdk.ToString(ep.VpcEndpointId),
+				VpcID:             awssdk.ToString(ep.VpcId),
+				ServiceName:       awssdk.ToString(ep.ServiceName),
+				VpcEndpointType:   string(ep.VpcEndpointType),
+				RouteTableIDs:     ep.RouteTableIds,
+				SubnetIDs:         ep.SubnetIds,
+				PrivateDnsEnabled: awssdk.ToBool(ep.PrivateDnsEnabled),
+				Tags:              tagsToMap(ep.Tags),
+			}
+			for _, g := range ep.Groups {
+				raw.SecurityGroupIDs = append(raw.SecurityGroupIDs, awssdk.ToString(g.GroupId))
+			}
+			raws = append(raws, raw)
+		}
+	}
+
+	return s.mapper.MapVpcEndpoint(raws, ScopeRegion(ctx))
+}
+
+// defaultNetworkAclRuleNumber は AWS が各 NACL に自動作成する既定の拒否ルール（*）の番号。
+// Terraform 側でも管理対象外のため列挙結果から除外する。
+const defaultNetworkAclRuleNumber int32 = 32767
+
+// ListNetworkAcls は DescribeNetworkAcls で VPC 内のネットワーク ACL をルール・サブネット関連付けとともに列挙する。
+// VPC の既定 NACL は aws_default_network_acl として扱う。
+func (s *awsVpcDiscoveryService) ListNetworkAcls(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var raws []terraform.RawNetworkAcl
+
+	p := ec2.NewDescribeNetworkAclsPaginator(s.ec2, &ec2.DescribeNetworkAclsInput{
+		Filters:    ec2Filter("vpc-id", vpcID),
+		MaxResults: awssdk.Int32(ec2MaxResults),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeNetworkAcls: %w", err)
+		}
+		for _, acl := range page.NetworkAcls {
+			raw := terraform.RawNetworkAcl{
+				ID:        awssdk.ToString(acl.NetworkAclId),
+				VpcID:     awssdk.ToString(acl.VpcId),
+				IsDefault: awssdk.ToBool(acl.IsDefault),
+				Tags:      tagsToMap(acl.Tags),
+			}
+			for _, a := range acl.Associations {
+				raw.SubnetIDs = append(raw.SubnetIDs, awssdk.ToString(a.SubnetId))
+			}
+			for _, e := range acl.Entries {
+				ruleNumber := awssdk.ToInt32(e.RuleNumber)
+				if ruleNumber == defaultNetworkAclRuleNumber {
+					continue
+				}
+				entry := terraform.RawNetworkAclEntry{
+					RuleNumber:    ruleNumber,
+					Egress:        awssdk.ToBool(e.Egress),
+					Protocol:      awssdk.ToString(e.Protocol),
+					RuleAction:    string(e.RuleAction),
+					CidrBlock:     awssdk.ToString(e.CidrBlock),
+					Ipv6CidrBlock: awssdk.ToString(e.Ipv6CidrBlock),
+				}
+				if e.PortRange != nil {
+					entry.FromPort = awssdk.ToInt32(e.PortRange.From)
+					entry.ToPort = awssdk.ToInt32(e.PortRange.To)
+				}
+				if e.IcmpTypeCode != nil {
+					entry.IcmpType = awssdk.ToInt32(e.IcmpTypeCode.Type)
+					entry.IcmpCode = awssdk.ToInt32(e.IcmpTypeCode.Code)
+				}
+				raw.Entries = append(raw.Entries, entry)
+			}
+			sort.Slice(raw.Entries, func(i, j int) bool {
+				if raw.Entries[i].Egress != raw.Entries[j].Egress {
+					return !raw.Entries[i].Egress
+				}
+				return raw.Entries[i].RuleNumber < raw.Entries[j].RuleNumber
+			})
+			raws = append(raws, raw)
+		}
+	}
+
+	return s.mapper.MapNetworkAcl(raws, ScopeRegion(ctx))
+}
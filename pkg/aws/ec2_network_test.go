@@ -0,0 +1,489 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// fakeEc2 は Ec2API のインメモリ実装。Describe 系 API は pageSize 件ずつ NextToken でページングして返し、
+// vpc-id などのフィルタを実際の API と同様に適用する。未実装の API を呼び出すと panic する。
+type fakeEc2 struct {
+	Ec2API
+
+	// pageSize が 0 の場合は 1 ページで返す。
+	pageSize int
+
+	vpcs               []ec2types.Vpc
+	subnets            []ec2types.Subnet
+	routeTables        []ec2types.RouteTable
+	securityGroups     []ec2types.SecurityGroup
+	securityGroupRules []ec2types.SecurityGroupRule
+	internetGateways   []ec2types.InternetGateway
+	natGateways        []ec2types.NatGateway
+	instances          []ec2types.Instance
+
+	// calls は API 名ごとの呼び出し回数（ページ数）。
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (f *fakeEc2) called(api string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = make(map[string]int)
+	}
+	f.calls[api]++
+}
+
+// fakePage は items から token の位置以降の 1 ページ分と、次ページの NextToken を返す。
+func fakePage[T any](items []T, token *string, size int) ([]T, *string) {
+	start, _ := strconv.Atoi(awssdk.ToString(token))
+	if size <= 0 || start+size >= len(items) {
+		return items[start:], nil
+	}
+	return items[start : start+size], awssdk.String(strconv.Itoa(start + size))
+}
+
+// fakeFilter は items のうち、filters の各条件（name に対応する値が Values のいずれかに一致）を満たすものを返す。
+func fakeFilter[T any](items []T, filters []ec2types.Filter, values func(item T, name string) []string) []T {
+	var matched []T
+	for _, item := range items {
+		ok := true
+		for _, f := range filters {
+			if !containsAny(f.Values, values(item, awssdk.ToString(f.Name))) {
+				ok = false
+			}
+		}
+		if ok {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+func containsAny(want []string, got []string) bool {
+	for _, w := range want {
+		for _, g := range got {
+			if w == g {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f *fakeEc2) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	f.called("DescribeVpcs")
+	var vpcs []ec2types.Vpc
+	for _, v := range f.vpcs {
+		if len(params.VpcIds) == 0 || containsAny(params.VpcIds, []string{awssdk.ToString(v.VpcId)}) {
+			vpcs = append(vpcs, v)
+		}
+	}
+	page, next := fakePage(vpcs, params.NextToken, f.pageSize)
+	return &ec2.DescribeVpcsOutput{Vpcs: page, NextToken: next}, nil
+}
+
+func (f *fakeEc2) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	f.called("DescribeSubnets")
+	subnets := fakeFilter(f.subnets, params.Filters, func(s ec2types.Subnet, name string) []string {
+		return []string{awssdk.ToString(s.VpcId)}
+	})
+	page, next := fakePage(subnets, params.NextToken, f.pageSize)
+	return &ec2.DescribeSubnetsOutput{Subnets: page, NextToken: next}, nil
+}
+
+func (f *fakeEc2) DescribeRouteTables(ctx context.Context, params *ec2.DescribeRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error) {
+	f.called("DescribeRouteTables")
+	tables := fakeFilter(f.routeTables, params.Filters, func(t ec2types.RouteTable, name string) []string {
+		return []string{awssdk.ToString(t.VpcId)}
+	})
+	page, next := fakePage(tables, params.NextToken, f.pageSize)
+	return &ec2.DescribeRouteTablesOutput{RouteTables: page, NextToken: next}, nil
+}
+
+func (f *fakeEc2) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	f.called("DescribeSecurityGroups")
+	groups := fakeFilter(f.securityGroups, params.Filters, func(g ec2types.SecurityGroup, name string) []string {
+		return []string{awssdk.ToString(g.VpcId)}
+	})
+	page, next := fakePage(groups, params.NextToken, f.pageSize)
+	return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: page, NextToken: next}, nil
+}
+
+func (f *fakeEc2) DescribeSecurityGroupRules(ctx context.Context, params *ec2.DescribeSecurityGroupRulesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupRulesOutput, error) {
+	f.called("DescribeSecurityGroupRules")
+	rules := fakeFilter(f.securityGroupRules, params.Filters, func(r ec2types.SecurityGroupRule, name string) []string {
+		return []string{awssdk.ToString(r.GroupId)}
+	})
+	page, next := fakePage(rules, params.NextToken, f.pageSize)
+	return &ec2.DescribeSecurityGroupRulesOutput{SecurityGroupRules: page, NextToken: next}, nil
+}
+
+func (f *fakeEc2) DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+	f.called("DescribeInternetGateways")
+	igws := fakeFilter(f.internetGateways, params.Filters, func(g ec2types.InternetGateway, name string) []string {
+		var ids []string
+		for _, a := range g.Attachments {
+			ids = append(ids, awssdk.ToString(a.VpcId))
+		}
+		return ids
+	})
+	page, next := fakePage(igws, params.NextToken, f.pageSize)
+	return &ec2.DescribeInternetGatewaysOutput{InternetGateways: page, NextToken: next}, nil
+}
+
+func (f *fakeEc2) DescribeNatGateways(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
+	f.called("DescribeNatGateways")
+	ngws := fakeFilter(f.natGateways, params.Filter, func(g ec2types.NatGateway, name string) []string {
+		return []string{awssdk.ToString(g.VpcId)}
+	})
+	page, next := fakePage(ngws, params.NextToken, f.pageSize)
+	return &ec2.DescribeNatGatewaysOutput{NatGateways: page, NextToken: next}, nil
+}
+
+// DescribeInstances はインスタンスを 1 件ずつ別の Reservation に入れて返す。
+func (f *fakeEc2) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	f.called("DescribeInstances")
+	instances := fakeFilter(f.instances, params.Filters, func(i ec2types.Instance, name string) []string {
+		if name == "instance-state-name" {
+			return []string{string(i.State.Name)}
+		}
+		return []string{awssdk.ToString(i.VpcId)}
+	})
+	page, next := fakePage(instances, params.NextToken, f.pageSize)
+	out := &ec2.DescribeInstancesOutput{NextToken: next}
+	for _, i := range page {
+		out.Reservations = append(out.Reservations, ec2types.Reservation{Instances: []ec2types.Instance{i}})
+	}
+	return out, nil
+}
+
+// nopLogger はログを出力しない Logger。
+type nopLogger struct{}
+
+func (nopLogger) Infof(format string, args ...any)  {}
+func (nopLogger) Warnf(format string, args ...any)  {}
+func (nopLogger) Errorf(format string, args ...any) {}
+
+// newFakeEc2 は 2 つの VPC（vpc-1 / vpc-2）にまたがるネットワーク構成を持つ fakeEc2 を返す。
+func newFakeEc2(pageSize int) *fakeEc2 {
+	tags := func(kv ...string) []ec2types.Tag {
+		var t []ec2types.Tag
+		for i := 0; i < len(kv); i += 2 {
+			t = append(t, ec2types.Tag{Key: awssdk.String(kv[i]), Value: awssdk.String(kv[i+1])})
+		}
+		return t
+	}
+	return &fakeEc2{
+		pageSize: pageSize,
+		vpcs: []ec2types.Vpc{
+			{VpcId: awssdk.String("vpc-1"), CidrBlock: awssdk.String("10.0.0.0/16"), InstanceTenancy: ec2types.TenancyDefault, Tags: tags("Name", "main")},
+			{VpcId: awssdk.String("vpc-2"), CidrBlock: awssdk.String("10.1.0.0/16")},
+		},
+		subnets: []ec2types.Subnet{
+			{SubnetId: awssdk.String("subnet-a"), VpcId: awssdk.String("vpc-1"), CidrBlock: awssdk.String("10.0.1.0/24"), AvailabilityZone: awssdk.String("ap-northeast-1a"), MapPublicIpOnLaunch: awssdk.Bool(true)},
+			{SubnetId: awssdk.String("subnet-x"), VpcId: awssdk.String("vpc-2"), CidrBlock: awssdk.String("10.1.1.0/24")},
+			{SubnetId: awssdk.String("subnet-b"), VpcId: awssdk.String("vpc-1"), CidrBlock: awssdk.String("10.0.2.0/24"), AvailabilityZone: awssdk.String("ap-northeast-1c")},
+			{SubnetId: awssdk.String("subnet-c"), VpcId: awssdk.String("vpc-1"), CidrBlock: awssdk.String("10.0.3.0/24"), AvailabilityZone: awssdk.String("ap-northeast-1d")},
+		},
+		routeTables: []ec2types.RouteTable{
+			{RouteTableId: awssdk.String("rtb-main"), VpcId: awssdk.String("vpc-1"),
+				Routes: []ec2types.Route{
+					{DestinationCidrBlock: awssdk.String("10.0.0.0/16"), GatewayId: awssdk.String("local"), Origin: ec2types.RouteOriginCreateRouteTable},
+				},
+				Associations: []ec2types.RouteTableAssociation{{RouteTableAssociationId: awssdk.String("rtbassoc-main"), Main: awssdk.Bool(true)}}},
+			{RouteTableId: awssdk.String("rtb-public"), VpcId: awssdk.String("vpc-1"),
+				Routes: []ec2types.Route{
+					{DestinationCidrBlock: awssdk.String("10.0.0.0/16"), GatewayId: awssdk.String("local"), Origin: ec2types.RouteOriginCreateRouteTable},
+					{DestinationCidrBlock: awssdk.String("0.0.0.0/0"), GatewayId: awssdk.String("igw-1"), Origin: ec2types.RouteOriginCreateRoute},
+					{DestinationCidrBlock: awssdk.String("192.168.0.0/16"), GatewayId: awssdk.String("vgw-1"), Origin: ec2types.RouteOriginEnableVgwRoutePropagation},
+					{DestinationPrefixListId: awssdk.String("pl-s3"), GatewayId: awssdk.String("vpce-s3"), Origin: ec2types.RouteOriginCreateRoute},
+				},
+				Associations: []ec2types.RouteTableAssociation{
+					{RouteTableAssociationId: awssdk.String("rtbassoc-a"), SubnetId: awssdk.String("subnet-a")},
+					{RouteTableAssociationId: awssdk.String("rtbassoc-old"), SubnetId: awssdk.String("subnet-b"),
+						AssociationState: &ec2types.RouteTableAssociationState{State: ec2types.RouteTableAssociationStateCodeDisassociated}},
+				}},
+			{RouteTableId: awssdk.String("rtb-other"), VpcId: awssdk.String("vpc-2")},
+		},
+		securityGroups: []ec2types.SecurityGroup{
+			{GroupId: awssdk.String("sg-web"), VpcId: awssdk.String("vpc-1"), GroupName: awssdk.String("web"), Description: awssdk.String("web")},
+			{GroupId: awssdk.String("sg-db"), VpcId: awssdk.String("vpc-1"), GroupName: awssdk.String("db"), Description: awssdk.String("db")},
+			{GroupId: awssdk.String("sg-other"), VpcId: awssdk.String("vpc-2"), GroupName: awssdk.String("other"), Description: awssdk.String("other")},
+		},
+		securityGroupRules: []ec2types.SecurityGroupRule{
+			{SecurityGroupRuleId: awssdk.String("sgr-2"), GroupId: awssdk.String("sg-db"), IpProtocol: awssdk.String("tcp"), FromPort: awssdk.Int32(5432), ToPort: awssdk.Int32(5432),
+				ReferencedGroupInfo: &ec2types.ReferencedSecurityGroup{GroupId: awssdk.String("sg-web")}},
+			{SecurityGroupRuleId: awssdk.String("sgr-1"), GroupId: awssdk.String("sg-web"), IpProtocol: awssdk.String("tcp"), FromPort: awssdk.Int32(443), ToPort: awssdk.Int32(443), CidrIpv4: awssdk.String("0.0.0.0/0")},
+			{SecurityGroupRuleId: awssdk.String("sgr-3"), GroupId: awssdk.String("sg-web"), IsEgress: awssdk.Bool(true), IpProtocol: awssdk.String("-1"), CidrIpv4: awssdk.String("0.0.0.0/0")},
+			{SecurityGroupRuleId: awssdk.String("sgr-9"), GroupId: awssdk.String("sg-other"), IpProtocol: awssdk.String("-1"), CidrIpv4: awssdk.String("0.0.0.0/0")},
+		},
+		internetGateways: []ec2types.InternetGateway{
+			{InternetGatewayId: awssdk.String("igw-1"), Attachments: []ec2types.InternetGatewayAttachment{{VpcId: awssdk.String("vpc-1")}}},
+			{InternetGatewayId: awssdk.String("igw-2"), Attachments: []ec2types.InternetGatewayAttachment{{VpcId: awssdk.String("vpc-2")}}},
+			{InternetGatewayId: awssdk.String("igw-detached")},
+		},
+		natGateways: []ec2types.NatGateway{
+			{NatGatewayId: awssdk.String("nat-1"), VpcId: awssdk.String("vpc-1"), SubnetId: awssdk.String("subnet-a"), State: ec2types.NatGatewayStateAvailable, ConnectivityType: ec2types.ConnectivityTypePublic,
+				NatGatewayAddresses: []ec2types.NatGatewayAddress{
+					{AllocationId: awssdk.String("eipalloc-secondary")},
+					{AllocationId: awssdk.String("eipalloc-primary"), IsPrimary: awssdk.Bool(true)},
+				}},
+			{NatGatewayId: awssdk.String("nat-deleted"), VpcId: awssdk.String("vpc-1"), SubnetId: awssdk.String("subnet-b"), State: ec2types.NatGatewayStateDeleted},
+			{NatGatewayId: awssdk.String("nat-2"), VpcId: awssdk.String("vpc-2"), SubnetId: awssdk.String("subnet-x"), State: ec2types.NatGatewayStateAvailable},
+		},
+		instances: []ec2types.Instance{
+			{InstanceId: awssdk.String("i-web"), VpcId: awssdk.String("vpc-1"), SubnetId: awssdk.String("subnet-a"), ImageId: awssdk.String("ami-1"), InstanceType: ec2types.InstanceTypeT3Micro,
+				State:              &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning},
+				SecurityGroups:     []ec2types.GroupIdentifier{{GroupId: awssdk.String("sg-web")}},
+				IamInstanceProfile: &ec2types.IamInstanceProfile{Arn: awssdk.String("arn:aws:iam::111122223333:instance-profile/app/web-profile")},
+				Tags:               tags("Name", "web")},
+			{InstanceId: awssdk.String("i-stopped"), VpcId: awssdk.String("vpc-1"), SubnetId: awssdk.String("subnet-b"), State: &ec2types.InstanceState{Name: ec2types.InstanceStateNameStopped}},
+			{InstanceId: awssdk.String("i-terminated"), VpcId: awssdk.String("vpc-1"), State: &ec2types.InstanceState{Name: ec2types.InstanceStateNameTerminated}},
+			{InstanceId: awssdk.String("i-asg"), VpcId: awssdk.String("vpc-1"), SubnetId: awssdk.String("subnet-a"), State: &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning},
+				Tags: tags("aws:autoscaling:groupName", "workers")},
+			{InstanceId: awssdk.String("i-other"), VpcId: awssdk.String("vpc-2"), SubnetId: awssdk.String("subnet-x"), State: &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning}},
+		},
+	}
+}
+
+// resourceIDs は rs の Resource ID を並べ替えて返す。
+func resourceIDs(rs []terraform.Resource) []string {
+	ids := make([]string, len(rs))
+	for i, r := range rs {
+		ids[i] = r.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// findResource は rs から ID が id の Resource を返す。
+func findResource(t *testing.T, rs []terraform.Resource, id string) terraform.Resource {
+	t.Helper()
+	for _, r := range rs {
+		if r.ID == id {
+			return r
+		}
+	}
+	t.Fatalf("resource %s not found in %v", id, resourceIDs(rs))
+	return terraform.Resource{}
+}
+
+func TestNetworkListers(t *testing.T) {
+	tests := []struct {
+		name string
+		api  string
+		list func(s *awsVpcDiscoveryService) func(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+		want []string
+		// pages は 1 件ずつページングした場合の API 呼び出し回数。
+		pages int
+		// check は列挙結果の属性・関係を検証する。
+		check func(t *testing.T, rs []terraform.Resource, rels []terraform.Relation)
+	}{
+		{
+			name:  "vpcs",
+			api:   "DescribeVpcs",
+			pages: 1,
+			list: func(s *awsVpcDiscoveryService) func(context.Context, string) ([]terraform.Resource, []terraform.Relation, error) {
+				return s.ListVpcs
+			},
+			want: []string{"aws:aws_vpc:vpc-1"},
+			check: func(t *testing.T, rs []terraform.Resource, rels []terraform.Relation) {
+				vpc := findResource(t, rs, "aws:aws_vpc:vpc-1")
+				if got := vpc.Attributes["cidr_block"]; got != "10.0.0.0/16" {
+					t.Errorf("cidr_block = %v, want 10.0.0.0/16", got)
+				}
+				if got := vpc.Labels["Name"]; got != "main" {
+					t.Errorf("Name label = %q, want main", got)
+				}
+			},
+		},
+		{
+			name:  "subnets",
+			api:   "DescribeSubnets",
+			pages: 3,
+			list: func(s *awsVpcDiscoveryService) func(context.Context, string) ([]terraform.Resource, []terraform.Relation, error) {
+				return s.ListSubnets
+			},
+			want: []string{"aws:aws_subnet:subnet-a", "aws:aws_subnet:subnet-b", "aws:aws_subnet:subnet-c"},
+			check: func(t *testing.T, rs []terraform.Resource, rels []terraform.Relation) {
+				subnet := findResource(t, rs, "aws:aws_subnet:subnet-a")
+				if got := subnet.Attributes["cidr_block"]; got != "10.0.1.0/24" {
+					t.Errorf("cidr_block = %v, want 10.0.1.0/24", got)
+				}
+				if got := subnet.Attributes["map_public_ip_on_launch"]; got != true {
+					t.Errorf("map_public_ip_on_launch = %v, want true", got)
+				}
+			},
+		},
+		{
+			name:  "route tables",
+			api:   "DescribeRouteTables",
+			pages: 2,
+			list: func(s *awsVpcDiscoveryService) func(context.Context, string) ([]terraform.Resource, []terraform.Relation, error) {
+				return s.ListRouteTables
+			},
+			// ローカルルート・ルート伝播・ゲートウェイ型 VPC エンドポイントのルートと、解除済みの関連付けは対象外
+			want: []string{
+				"aws:aws_main_route_table_association:rtbassoc-main",
+				"aws:aws_route:rtb-public_0.0.0.0/0",
+				"aws:aws_route_table:rtb-main",
+				"aws:aws_route_table:rtb-public",
+				"aws:aws_route_table_association:subnet-a/rtb-public",
+			},
+			check: func(t *testing.T, rs []terraform.Resource, rels []terraform.Relation) {
+				route := findResource(t, rs, "aws:aws_route:rtb-public_0.0.0.0/0")
+				if got := route.Attributes["gateway_id"]; got != "igw-1" {
+					t.Errorf("gateway_id = %v, want igw-1", got)
+				}
+				want := terraform.Relation{From: route.ID, To: "aws:aws_internet_gateway:igw-1", Kind: terraform.RelationNetwork}
+				if !containsRelation(rels, want) {
+					t.Errorf("relation %v not found", want)
+				}
+			},
+		},
+		{
+			name:  "security groups",
+			api:   "DescribeSecurityGroups",
+			pages: 2,
+			list: func(s *awsVpcDiscoveryService) func(context.Context, string) ([]terraform.Resource, []terraform.Relation, error) {
+				return s.ListSecurityGroups
+			},
+			want: []string{
+				"aws:aws_security_group:sg-db",
+				"aws:aws_security_group:sg-web",
+				"aws:aws_vpc_security_group_egress_rule:sgr-3",
+				"aws:aws_vpc_security_group_ingress_rule:sgr-1",
+				"aws:aws_vpc_security_group_ingress_rule:sgr-2",
+			},
+			check: func(t *testing.T, rs []terraform.Resource, rels []terraform.Relation) {
+				rule := findResource(t, rs, "aws:aws_vpc_security_group_ingress_rule:sgr-2")
+				if got := rule.Attributes["referenced_security_group_id"]; got != "sg-web" {
+					t.Errorf("referenced_security_group_id = %v, want sg-web", got)
+				}
+				want := terraform.Relation{From: rule.ID, To: "aws:aws_security_group:sg-web", Kind: terraform.RelationSecurity}
+				if !containsRelation(rels, want) {
+					t.Errorf("relation %v not found", want)
+				}
+			},
+		},
+		{
+			name:  "internet gateways",
+			api:   "DescribeInternetGateways",
+			pages: 1,
+			list: func(s *awsVpcDiscoveryService) func(context.Context, string) ([]terraform.Resource, []terraform.Relation, error) {
+				return s.ListInternetGateways
+			},
+			want: []string{"aws:aws_internet_gateway:igw-1"},
+			check: func(t *testing.T, rs []terraform.Resource, rels []terraform.Relation) {
+				igw := findResource(t, rs, "aws:aws_internet_gateway:igw-1")
+				if got := igw.Attributes["vpc_id"]; got != "vpc-1" {
+					t.Errorf("vpc_id = %v, want vpc-1", got)
+				}
+			},
+		},
+		{
+			name:  "nat gateways",
+			api:   "DescribeNatGateways",
+			pages: 2,
+			list: func(s *awsVpcDiscoveryService) func(context.Context, string) ([]terraform.Resource, []terraform.Relation, error) {
+				return s.ListNatGateways
+			},
+			// 削除済みの NAT ゲートウェイは対象外
+			want: []string{"aws:aws_nat_gateway:nat-1"},
+			check: func(t *testing.T, rs []terraform.Resource, rels []terraform.Relation) {
+				nat := findResource(t, rs, "aws:aws_nat_gateway:nat-1")
+				if got := nat.Attributes["allocation_id"]; got != "eipalloc-primary" {
+					t.Errorf("allocation_id = %v, want eipalloc-primary", got)
+				}
+				want := terraform.Relation{From: nat.ID, To: "aws:aws_subnet:subnet-a", Kind: terraform.RelationNetwork}
+				if !containsRelation(rels, want) {
+					t.Errorf("relation %v not found", want)
+				}
+			},
+		},
+		{
+			name:  "instances",
+			api:   "DescribeInstances",
+			pages: 3,
+			list: func(s *awsVpcDiscoveryService) func(context.Context, string) ([]terraform.Resource, []terraform.Relation, error) {
+				return s.ListInstances
+			},
+			// 終了済みのインスタンスと Auto Scaling グループのインスタンスは対象外
+			want: []string{"aws:aws_instance:i-stopped", "aws:aws_instance:i-web"},
+			check: func(t *testing.T, rs []terraform.Resource, rels []terraform.Relation) {
+				inst := findResource(t, rs, "aws:aws_instance:i-web")
+				if got := inst.Attributes["iam_instance_profile"]; got != "web-profile" {
+					t.Errorf("iam_instance_profile = %v, want web-profile", got)
+				}
+				if got := inst.Labels["vpc_id"]; got != "vpc-1" {
+					t.Errorf("vpc_id label = %q, want vpc-1", got)
+				}
+				for _, want := range []terraform.Relation{
+					{From: inst.ID, To: "aws:aws_subnet:subnet-a", Kind: terraform.RelationNetwork},
+					{From: inst.ID, To: "aws:aws_security_group:sg-web", Kind: terraform.RelationSecurity},
+					{From: inst.ID, To: "aws:aws_iam_instance_profile:web-profile", Kind: terraform.RelationIAM},
+				} {
+					if !containsRelation(rels, want) {
+						t.Errorf("relation %v not found", want)
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		// 1 ページで返す場合と、1 件ずつページングする場合で同じ結果になることを確認する
+		for _, pageSize := range []int{0, 1} {
+			t.Run(tt.name+"/page_size="+strconv.Itoa(pageSize), func(t *testing.T) {
+				fake := newFakeEc2(pageSize)
+				s := NewAwsVpcDiscoveryService(fake, nil, nil, nopLogger{})
+
+				rs, rels, err := tt.list(s)(context.Background(), "vpc-1")
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got := resourceIDs(rs); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("resources = %v, want %v", got, tt.want)
+				}
+				tt.check(t, rs, rels)
+
+				wantCalls := 1
+				if pageSize > 0 {
+					wantCalls = tt.pages
+				}
+				if got := fake.calls[tt.api]; got != wantCalls {
+					t.Errorf("%s called %d time(s), want %d", tt.api, got, wantCalls)
+				}
+			})
+		}
+	}
+}
+
+func TestListVpcsNotFound(t *testing.T) {
+	s := NewAwsVpcDiscoveryService(newFakeEc2(0), nil, nil, nopLogger{})
+	if _, _, err := s.ListVpcs(context.Background(), "vpc-missing"); err == nil {
+		t.Fatal("expected error for missing VPC")
+	}
+}
+
+func containsRelation(rels []terraform.Relation, want terraform.Relation) bool {
+	for _, r := range rels {
+		if r == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// ListVpcPeeringConnections は DescribeVpcPeeringConnections で、VPC がリクエスタ側・アクセプタ側の
+// いずれかであるピアリング接続を列挙する。
+// 削除済み・拒否・失敗・期限切れのものは import 対象外とする。
+func (s *awsVpcDiscoveryService) ListVpcPeeringConnections(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var raws []terraform.RawVpcPeeringConnection
+	seen := make(map[string]bool)
+
+	// リクエスタ側・アクセプタ側はフィルタを OR 指定できないため、それぞれ列挙する
+	for _, side := range []string{"requester-vpc-info.vpc-id", "accepter-vpc-info.vpc-id"} {
+		p := ec2.NewDescribeVpcPeeringConnectionsPaginator(s.ec2, &ec2.DescribeVpcPeeringConnectionsInput{
+			Filters:    ec2Filter(side, vpcID),
+			MaxResults: awssdk.Int32(ec2MaxResults),
+		})
+		for p.HasMorePages() {
+			page, err := p.NextPage(ctx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("DescribeVpcPeeringConnections: %w", err)
+			}
+			for _, pc := range page.VpcPeeringConnections {
+				id := awssdk.ToString(pc.VpcPeeringConnectionId)
+				if seen[id] || !isLiveVpcPeering(pc.Status) {
+					continue
+				}
+				seen[id] = true
+				raws = append(raws, rawVpcPeeringConnection(pc, vpcID))
+			}
+		}
+	}
+
+	return s.mapper.MapVpcPeeringConnection(raws, ScopeRegion(ctx))
+}
+
+// isLiveVpcPeering はピアリング接続が import 対象となる状態（active / pending-acceptance / provisioning 等）かどうかを判定する。
+func isLiveVpcPeering(status *ec2types.VpcPeeringConnectionStateReason) bool {
+	if status == nil {
+		return true
+	}
+	switch status.Code {
+	case ec2types.VpcPeeringConnectionStateReasonCodeDeleted,
+		ec2types.VpcPeeringConnectionStateReasonCodeDeleting,
+		ec2types.VpcPeeringConnectionStateReasonCodeRejected,
+		ec2types.VpcPeeringConnectionStateReasonCodeFailed,
+		ec2types.VpcPeeringConnectionStateReasonCodeExpired:
+		return false
+	}
+	return true
+}
+
+// rawVpcPeeringConnection は vpcID 側から見た RawVpcPeeringConnection を生成する。
+func rawVpcPeeringConnection(pc ec2types.VpcPeeringConnection, vpcID string) terraform.RawVpcPeeringConnection {
+	raw := terraform.RawVpcPeeringConnection{
+		ID:    awssdk.ToString(pc.VpcPeeringConnectionId),
+		VpcID: vpcID,
+		Tags:  tagsToMap(pc.Tags),
+	}
+	peer := pc.AccepterVpcInfo
+	if peer != nil && awssdk.ToString(peer.VpcId) == vpcID {
+		raw.IsAccepter = true
+		peer = pc.RequesterVpcInfo
+	}
+	if peer != nil {
+		raw.PeerVpcID = awssdk.ToString(peer.VpcId)
+		raw.PeerOwnerID = awssdk.ToString(peer.OwnerId)
+		raw.PeerRegion = awssdk.ToString(peer.Region)
+	}
+	return raw
+}
+
+// ListTransitGatewayVpcAttachments は DescribeTransitGatewayVpcAttachments で VPC の Transit Gateway アタッチメントを列挙する。
+// 削除済み・削除中・失敗・拒否状態のものは import 対象外とする。
+func (s *awsVpcDiscoveryService) ListTransitGatewayVpcAttachments(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var raws []terraform.RawTransitGatewayVpcAttachment
+
+	p := ec2.NewDescribeTransitGatewayVpcAttachmentsPaginator(s.ec2, &ec2.DescribeTransitGatewayVpcAttachmentsInput{
+		Filters:    ec2Filter("vpc-id", vpcID),
+		MaxResults: awssdk.Int32(ec2MaxResults),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeTransitGatewayVpcAttachments: %w", err)
+		}
+		for _, a := range page.TransitGatewayVpcAttachments {
+			switch a.State {
+			case ec2types.TransitGatewayAttachmentStateDeleted, ec2types.TransitGatewayAttachmentStateDeleting,
+				ec2types.TransitGatewayAttachmentStateFailed, ec2types.TransitGatewayAttachmentStateRejected:
+				continue
+			}
+			raw := terraform.RawTransitGatewayVpcAttachment{
+				ID:               awssdk.ToString(a.TransitGatewayAttachmentId),
+				VpcID:            awssdk.ToString(a.VpcId),
+				TransitGatewayID: awssdk.ToString(a.TransitGatewayId),
+				SubnetIDs:        a.SubnetIds,
+				Tags:             tagsToMap(a.Tags),
+			}
+			if o := a.Options; o != nil {
+				raw.DnsSupport = string(o.DnsSupport)
+				raw.Ipv6Support = string(o.Ipv6Support)
+				raw.ApplianceModeSupport = string(o.ApplianceModeSupport)
+			}
+			raws = append(raws, raw)
+		}
+	}
+
+	return s.mapper.MapTransitGatewayVpcAttachment(raws, ScopeRegion(ctx))
+}
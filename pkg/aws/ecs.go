@@ -0,0 +1,455 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// DescribeClusters / DescribeServices の 1 回あたりの最大件数。
+const (
+	ecsDescribeClustersBatchSize = 100
+	ecsDescribeServicesBatchSize = 10
+)
+
+// ListEcsClusters は VPC 内のサブネットに awsvpc のサービスを持つ ECS クラスターと、
+// そのキャパシティプロバイダーの関連付け・カスタムのキャパシティプロバイダー（Auto Scaling グループ）を列挙する。
+// ECS の API には VPC フィルタがないため、aws_subnet の列挙結果とサービスのサブネットで VPC 内かどうかを判定する。
+func (s *awsVpcDiscoveryService) ListEcsClusters(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	subnets := upstreamSubnetIDs(ctx)
+	if len(subnets) == 0 {
+		return []terraform.Resource{}, []terraform.Relation{}, nil
+	}
+
+	var clusterArns []string
+	p := ecs.NewListClustersPaginator(s.ecs, &ecs.ListClustersInput{})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ListClusters: %w", err)
+		}
+		clusterArns = append(clusterArns, page.ClusterArns...)
+	}
+
+	var raws []terraform.RawEcsCluster
+	var providerNames []string
+	for start := 0; start < len(clusterArns); start += ecsDescribeClustersBatchSize {
+		end := min(start+ecsDescribeClustersBatchSize, len(clusterArns))
+		out, err := s.ecs.DescribeClusters(ctx, &ecs.DescribeClustersInput{
+			Clusters: clusterArns[start:end],
+			Include:  []ecstypes.ClusterField{ecstypes.ClusterFieldTags, ecstypes.ClusterFieldSettings},
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeClusters: %w", err)
+		}
+		for _, c := range out.Clusters {
+			services, err := s.describeVpcEcsServices(ctx, awssdk.ToString(c.ClusterArn), subnets)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(services) == 0 {
+				continue
+			}
+			raw := terraform.RawEcsCluster{
+				Arn:               awssdk.ToString(c.ClusterArn),
+				Name:              awssdk.ToString(c.ClusterName),
+				VpcID:             vpcID,
+				CapacityProviders: c.CapacityProviders,
+				DefaultStrategy:   rawEcsStrategy(c.DefaultCapacityProviderStrategy),
+				Tags:              ecsTagsToMap(c.Tags),
+			}
+			for _, setting := range c.Settings {
+				if setting.Name == ecstypes.ClusterSettingNameContainerInsights {
+					raw.ContainerInsights = awssdk.ToString(setting.Value)
+				}
+			}
+			for _, name := range c.CapacityProviders {
+				if name != "FARGATE" && name != "FARGATE_SPOT" {
+					providerNames = appendUniqueName(providerNames, name)
+				}
+			}
+			raws = append(raws, raw)
+		}
+	}
+
+	providers, err := s.describeEcsCapacityProviders(ctx, providerNames, vpcID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.mapper.MapEcsCluster(raws, providers, ScopeRegion(ctx))
+}
+
+// ListEcsServices は aws_ecs_cluster で列挙したクラスターのうち、VPC 内のサブネットに配置された awsvpc のサービスと、
+// それらが利用している（アクティブな）タスク定義のリビジョンを列挙する。
+// bridge / host ネットワークモードのサービスは VPC との対応が取れないため対象外。
+func (s *awsVpcDiscoveryService) ListEcsServices(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	subnets := upstreamSubnetIDs(ctx)
+
+	var services []ecstypes.Service
+	for _, cluster := range UpstreamResources(ctx, "aws_ecs_cluster") {
+		if cluster.Type != "aws_ecs_cluster" {
+			continue
+		}
+		arn, _ := cluster.Attributes["arn"].(string)
+		found, err := s.describeVpcEcsServices(ctx, arn, subnets)
+		if err != nil {
+			return nil, nil, err
+		}
+		services = append(services, found...)
+	}
+
+	var taskDefs []terraform.RawEcsTaskDefinition
+	taskRoles := make(map[string]string)
+	for _, svc := range services {
+		arn := awssdk.ToString(svc.TaskDefinition)
+		if _, ok := taskRoles[arn]; ok || arn == "" {
+			continue
+		}
+		out, err := s.ecs.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: awssdk.String(arn),
+			Include:        []ecstypes.TaskDefinitionField{ecstypes.TaskDefinitionFieldTags},
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeTaskDefinition %s: %w", arn, err)
+		}
+		raw, err := rawEcsTaskDefinition(out.TaskDefinition, out.Tags, vpcID)
+		if err != nil {
+			return nil, nil, err
+		}
+		taskRoles[arn] = raw.TaskRoleArn
+		taskDefs = append(taskDefs, raw)
+	}
+
+	raws := make([]terraform.RawEcsService, 0, len(services))
+	for _, svc := range services {
+		raw := rawEcsService(svc, vpcID)
+		raw.TaskRoleArn = taskRoles[raw.TaskDefinitionArn]
+		raws = append(raws, raw)
+	}
+	return s.mapper.MapEcsService(raws, taskDefs, ScopeRegion(ctx))
+}
+
+// upstreamSubnetIDs は aws_subnet の列挙結果からサブネット ID の集合を返す。
+func upstreamSubnetIDs(ctx context.Context) map[string]bool {
+	subnets := make(map[string]bool)
+	for _, sn := range UpstreamResources(ctx, "aws_subnet") {
+		if id, ok := sn.Attributes["id"].(string); ok && sn.Type == "aws_subnet" {
+			subnets[id] = true
+		}
+	}
+	return subnets
+}
+
+// describeVpcEcsServices はクラスター内のサービスのうち、awsvpc のサブネットが subnets に含まれるものを返す。
+func (s *awsVpcDiscoveryService) describeVpcEcsServices(ctx context.Context, clusterArn string, subnets map[string]bool) ([]ecstypes.Service, error) {
+	var serviceArns []string
+	p := ecs.NewListServicesPaginator(s.ecs, &ecs.ListServicesInput{Cluster: awssdk.String(clusterArn)})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ListServices %s: %w", clusterArn, err)
+		}
+		serviceArns = append(serviceArns, page.ServiceArns...)
+	}
+
+	var services []ecstypes.Service
+	for start := 0; start < len(serviceArns); start += ecsDescribeServicesBatchSize {
+		end := min(start+ecsDescribeServicesBatchSize, len(serviceArns))
+		out, err := s.ecs.DescribeServices(ctx, &ecs.DescribeServicesInput{
+			Cluster:  awssdk.String(clusterArn),
+			Services: serviceArns[start:end],
+			Include:  []ecstypes.ServiceField{ecstypes.ServiceFieldTags},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("DescribeServices %s: %w", clusterArn, err)
+		}
+		for _, svc := range out.Services {
+			if awssdk.ToString(svc.Status) == "INACTIVE" || svc.NetworkConfiguration == nil || svc.NetworkConfiguration.AwsvpcConfiguration == nil {
+				continue
+			}
+			for _, id := range svc.NetworkConfiguration.AwsvpcConfiguration.Subnets {
+				if subnets[id] {
+					services = append(services, svc)
+					break
+				}
+			}
+		}
+	}
+	return services, nil
+}
+
+// describeEcsCapacityProviders は names のキャパシティプロバイダーを取得する。
+func (s *awsVpcDiscoveryService) describeEcsCapacityProviders(ctx context.Context, names []string, vpcID string) ([]terraform.RawEcsCapacityProvider, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var raws []terraform.RawEcsCapacityProvider
+	input := &ecs.DescribeCapacityProvidersInput{
+		CapacityProviders: names,
+		Include:           []ecstypes.CapacityProviderField{ecstypes.CapacityProviderFieldTags},
+	}
+	for {
+		out, err := s.ecs.DescribeCapacityProviders(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeCapacityProviders: %w", err)
+		}
+		for _, cp := range out.CapacityProviders {
+			asg := cp.AutoScalingGroupProvider
+			if asg == nil {
+				continue
+			}
+			raw := terraform.RawEcsCapacityProvider{
+				Arn:                          awssdk.ToString(cp.CapacityProviderArn),
+				Name:                         awssdk.ToString(cp.Name),
+				VpcID:                        vpcID,
+				AutoScalingGroupArn:          awssdk.ToString(asg.AutoScalingGroupArn),
+				ManagedTerminationProtection: string(asg.ManagedTerminationProtection),
+				Tags:                         ecsTagsToMap(cp.Tags),
+			}
+			if ms := asg.ManagedScaling; ms != nil {
+				raw.ManagedScalingStatus = string(ms.Status)
+				raw.TargetCapacity = awssdk.ToInt32(ms.TargetCapacity)
+				raw.MinimumScalingStepSize = awssdk.ToInt32(ms.MinimumScalingStepSize)
+				raw.MaximumScalingStepSize = awssdk.ToInt32(ms.MaximumScalingStepSize)
+			}
+			raws = append(raws, raw)
+		}
+		if out.NextToken == nil {
+			break
+		}
+		input.NextToken = out.NextToken
+	}
+	return raws, nil
+}
+
+// rawEcsService は SDK の Service を RawEcsService に変換する（TaskRoleArn は呼び出し側で設定する）。
+func rawEcsService(svc ecstypes.Service, vpcID string) terraform.RawEcsService {
+	clusterArn := awssdk.ToString(svc.ClusterArn)
+	raw := terraform.RawEcsService{
+		Arn:                           awssdk.ToString(svc.ServiceArn),
+		Name:                          awssdk.ToString(svc.ServiceName),
+		ClusterName:                   clusterArn[strings.LastIndex(clusterArn, "/")+1:],
+		VpcID:                         vpcID,
+		TaskDefinitionArn:             awssdk.ToString(svc.TaskDefinition),
+		DesiredCount:                  svc.DesiredCount,
+		LaunchType:                    string(svc.LaunchType),
+		PlatformVersion:               awssdk.ToString(svc.PlatformVersion),
+		SchedulingStrategy:            string(svc.SchedulingStrategy),
+		CapacityProviderStrategy:      rawEcsStrategy(svc.CapacityProviderStrategy),
+		RoleArn:                       awssdk.ToString(svc.RoleArn),
+		EnableExecuteCommand:          svc.EnableExecuteCommand,
+		EnableEcsManagedTags:          svc.EnableECSManagedTags,
+		PropagateTags:                 string(svc.PropagateTags),
+		HealthCheckGracePeriodSeconds: awssdk.ToInt32(svc.HealthCheckGracePeriodSeconds),
+		Tags:                          ecsTagsToMap(svc.Tags),
+	}
+	// サービスリンクロール（AWSServiceRoleForECS）は iam_role に指定しない
+	if strings.Contains(raw.RoleArn, "/aws-service-role/") {
+		raw.RoleArn = ""
+	}
+	if vpc := svc.NetworkConfiguration.AwsvpcConfiguration; vpc != nil {
+		raw.SubnetIDs = append(raw.SubnetIDs, vpc.Subnets...)
+		raw.SecurityGroupIDs = append(raw.SecurityGroupIDs, vpc.SecurityGroups...)
+		sort.Strings(raw.SubnetIDs)
+		sort.Strings(raw.SecurityGroupIDs)
+		raw.AssignPublicIP = vpc.AssignPublicIp == ecstypes.AssignPublicIpEnabled
+	}
+	if dc := svc.DeploymentConfiguration; dc != nil {
+		raw.DeploymentMaximumPercent = awssdk.ToInt32(dc.MaximumPercent)
+		raw.DeploymentMinimumHealthyPercent = awssdk.ToInt32(dc.MinimumHealthyPercent)
+		if cb := dc.DeploymentCircuitBreaker; cb != nil {
+			raw.CircuitBreakerEnable = cb.Enable
+			raw.CircuitBreakerRollback = cb.Rollback
+		}
+	}
+	for _, lb := range svc.LoadBalancers {
+		// Classic Load Balancer（LoadBalancerName）は対象外
+		if lb.TargetGroupArn == nil {
+			continue
+		}
+		raw.LoadBalancers = append(raw.LoadBalancers, terraform.RawEcsLoadBalancer{
+			TargetGroupArn: awssdk.ToString(lb.TargetGroupArn),
+			ContainerName:  awssdk.ToString(lb.ContainerName),
+			ContainerPort:  awssdk.ToInt32(lb.ContainerPort),
+		})
+	}
+	for _, r := range svc.ServiceRegistries {
+		raw.ServiceRegistries = append(raw.ServiceRegistries, terraform.RawEcsServiceRegistry{
+			RegistryArn:   awssdk.ToString(r.RegistryArn),
+			ContainerName: awssdk.ToString(r.ContainerName),
+			ContainerPort: awssdk.ToInt32(r.ContainerPort),
+			Port:          awssdk.ToInt32(r.Port),
+		})
+	}
+	return raw
+}
+
+// rawEcsTaskDefinition は SDK の TaskDefinition を RawEcsTaskDefinition に変換する。
+func rawEcsTaskDefinition(td *ecstypes.TaskDefinition, tags []ecstypes.Tag, vpcID string) (terraform.RawEcsTaskDefinition, error) {
+	containerDefs, err := ecsContainerDefinitionsJSON(td.ContainerDefinitions)
+	if err != nil {
+		return terraform.RawEcsTaskDefinition{}, fmt.Errorf("container definitions of %s: %w", awssdk.ToString(td.TaskDefinitionArn), err)
+	}
+	raw := terraform.RawEcsTaskDefinition{
+		Arn:                  awssdk.ToString(td.TaskDefinitionArn),
+		Family:               awssdk.ToString(td.Family),
+		Revision:             td.Revision,
+		VpcID:                vpcID,
+		ContainerDefinitions: containerDefs,
+		Cpu:                  awssdk.ToString(td.Cpu),
+		Memory:               awssdk.ToString(td.Memory),
+		NetworkMode:          string(td.NetworkMode),
+		TaskRoleArn:          awssdk.ToString(td.TaskRoleArn),
+		ExecutionRoleArn:     awssdk.ToString(td.ExecutionRoleArn),
+		Tags:                 ecsTagsToMap(tags),
+	}
+	for _, c := range td.RequiresCompatibilities {
+		raw.RequiresCompatibilities = append(raw.RequiresCompatibilities, string(c))
+	}
+	if rp := td.RuntimePlatform; rp != nil {
+		raw.CpuArchitecture = string(rp.CpuArchitecture)
+		raw.OperatingSystemFamily = string(rp.OperatingSystemFamily)
+	}
+	for _, v := range td.Volumes {
+		vol := terraform.RawEcsVolume{Name: awssdk.ToString(v.Name)}
+		if v.Host != nil {
+			vol.HostPath = awssdk.ToString(v.Host.SourcePath)
+		}
+		if efs := v.EfsVolumeConfiguration; efs != nil {
+			vol.EfsFileSystemID = awssdk.ToString(efs.FileSystemId)
+			vol.EfsRootDirectory = awssdk.ToString(efs.RootDirectory)
+			vol.EfsTransitEncryption = string(efs.TransitEncryption)
+			if efs.AuthorizationConfig != nil {
+				vol.EfsAccessPointID = awssdk.ToString(efs.AuthorizationConfig.AccessPointId)
+			}
+		}
+		raw.Volumes = append(raw.Volumes, vol)
+	}
+	for _, c := range td.ContainerDefinitions {
+		container := terraform.RawEcsContainer{
+			Name:  awssdk.ToString(c.Name),
+			Image: awssdk.ToString(c.Image),
+		}
+		if lc := c.LogConfiguration; lc != nil {
+			if lc.LogDriver == ecstypes.LogDriverAwslogs {
+				container.LogGroup = lc.Options["awslogs-group"]
+			}
+			for _, sec := range lc.SecretOptions {
+				container.SecretRefs = append(container.SecretRefs, awssdk.ToString(sec.ValueFrom))
+			}
+		}
+		for _, sec := range c.Secrets {
+			container.SecretRefs = append(container.SecretRefs, awssdk.ToString(sec.ValueFrom))
+		}
+		if c.RepositoryCredentials != nil {
+			container.SecretRefs = append(container.SecretRefs, awssdk.ToString(c.RepositoryCredentials.CredentialsParameter))
+		}
+		raw.Containers = append(raw.Containers, container)
+	}
+	return raw, nil
+}
+
+// rawEcsStrategy は SDK のキャパシティプロバイダー戦略を変換する。
+func rawEcsStrategy(items []ecstypes.CapacityProviderStrategyItem) []terraform.RawEcsCapacityProviderStrategy {
+	var strategy []terraform.RawEcsCapacityProviderStrategy
+	for _, item := range items {
+		strategy = append(strategy, terraform.RawEcsCapacityProviderStrategy{
+			CapacityProvider: awssdk.ToString(item.CapacityProvider),
+			Base:             item.Base,
+			Weight:           item.Weight,
+		})
+	}
+	return strategy
+}
+
+// ecsTagsToMap は ECS のタグを map に変換する。
+func ecsTagsToMap(tags []ecstypes.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, t := range tags {
+		m[awssdk.ToString(t.Key)] = awssdk.ToString(t.Value)
+	}
+	return m
+}
+
+// ecsContainerDefinitionsJSON はコンテナ定義を RegisterTaskDefinition と同じ形式（camelCase のキー）の JSON に変換する。
+// SDK の構造体を encoding/json でそのまま出力するとフィールド名が PascalCase になるため、
+// 構造体のフィールド名のみ先頭を小文字にし（dockerLabels 等の map のキーはそのまま）、未設定・空の値は出力しない。
+func ecsContainerDefinitionsJSON(defs []ecstypes.ContainerDefinition) (string, error) {
+	v := ecsAPIValue(reflect.ValueOf(defs))
+	if v == nil {
+		v = []any{}
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ecsAPIValue は SDK の値を JSON 出力用の値に変換する。未設定（nil / 空文字 / 空のスライス・map）の場合は nil を返す。
+func ecsAPIValue(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return ecsAPIValue(v.Elem())
+	case reflect.Struct:
+		obj := make(map[string]any)
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if val := ecsAPIValue(v.Field(i)); val != nil {
+				r, size := utf8.DecodeRuneInString(f.Name)
+				obj[string(unicode.ToLower(r))+f.Name[size:]] = val
+			}
+		}
+		if len(obj) == 0 {
+			return nil
+		}
+		return obj
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return nil
+		}
+		list := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if val := ecsAPIValue(v.Index(i)); val != nil {
+				list = append(list, val)
+			}
+		}
+		return list
+	case reflect.Map:
+		if v.Len() == 0 {
+			return nil
+		}
+		obj := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			obj[iter.Key().String()] = ecsAPIValue(iter.Value())
+		}
+		return obj
+	case reflect.String:
+		if v.String() == "" {
+			return nil
+		}
+		return v.String()
+	default:
+		return v.Interface()
+	}
+}
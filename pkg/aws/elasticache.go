@@ -0,0 +1,302 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	ectypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// ListElastiCacheClusters は VPC 内の ElastiCache サブネットグループ・レプリケーショングループ・
+// レプリケーショングループに属さないクラスター（Memcached / 単体の Redis）と、それらが参照するパラメータグループを列挙する。
+// レプリケーショングループのメンバークラスターは aws_elasticache_cluster として出力しない。
+// デフォルトのパラメータグループ（"default." で始まるもの）は出力しない。Serverless キャッシュは対象外。
+func (s *awsVpcDiscoveryService) ListElastiCacheClusters(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	subnetGroups, err := s.describeVpcCacheSubnetGroups(ctx, vpcID)
+	if err != nil {
+		return nil, nil, err
+	}
+	inVpc := make(map[string]bool, len(subnetGroups))
+	for _, g := range subnetGroups {
+		inVpc[g.Name] = true
+	}
+
+	// サブネットグループで VPC 内のクラスターを判定し、レプリケーショングループごとに振り分ける
+	var standalone []terraform.RawElastiCacheCluster
+	members := make(map[string]terraform.RawElastiCacheCluster)
+	p := elasticache.NewDescribeCacheClustersPaginator(s.elasticache, &elasticache.DescribeCacheClustersInput{ShowCacheNodeInfo: awssdk.Bool(true)})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DescribeCacheClusters: %w", err)
+		}
+		for _, c := range page.CacheClusters {
+			if !inVpc[awssdk.ToString(c.CacheSubnetGroupName)] {
+				continue
+			}
+			raw := rawElastiCacheCluster(c, vpcID)
+			if rg := awssdk.ToString(c.ReplicationGroupId); rg != "" {
+				// メンバーの設定はレプリケーショングループ内で共通のため、最初のメンバーのみ保持する
+				if _, ok := members[rg]; !ok {
+					members[rg] = raw
+				}
+				continue
+			}
+			standalone = append(standalone, raw)
+		}
+	}
+
+	groups, err := s.describeVpcReplicationGroups(ctx, members)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.fillElastiCacheTags(ctx, groups, standalone); err != nil {
+		return nil, nil, err
+	}
+
+	var paramGroupNames []string
+	for _, g := range groups {
+		paramGroupNames = appendUniqueName(paramGroupNames, g.ParameterGroupName)
+	}
+	for _, c := range standalone {
+		paramGroupNames = appendUniqueName(paramGroupNames, c.ParameterGroupName)
+	}
+	paramGroups, err := s.describeCacheParameterGroups(ctx, paramGroupNames)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	region := ScopeRegion(ctx)
+	resources, relations, err := s.mapper.MapElastiCacheSubnetGroup(subnetGroups, region)
+	if err != nil {
+		return nil, nil, err
+	}
+	paramResources, _, err := s.mapper.MapElastiCacheParameterGroup(paramGroups, region, vpcID)
+	if err != nil {
+		return nil, nil, err
+	}
+	groupResources, groupRelations, err := s.mapper.MapElastiCacheReplicationGroup(groups, region)
+	if err != nil {
+		return nil, nil, err
+	}
+	clusterResources, clusterRelations, err := s.mapper.MapElastiCacheCluster(standalone, region)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resources = append(append(append(resources, paramResources...), groupResources...), clusterResources...)
+	relations = append(append(relations, groupRelations...), clusterRelations...)
+	return resources, relations, nil
+}
+
+// describeVpcCacheSubnetGroups は DescribeCacheSubnetGroups で VPC 内の ElastiCache サブネットグループを取得する（API に VPC フィルタはない）。
+func (s *awsVpcDiscoveryService) describeVpcCacheSubnetGroups(ctx context.Context, vpcID string) ([]terraform.RawElastiCacheSubnetGroup, error) {
+	var raws []terraform.RawElastiCacheSubnetGroup
+
+	p := elasticache.NewDescribeCacheSubnetGroupsPaginator(s.elasticache, &elasticache.DescribeCacheSubnetGroupsInput{})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeCacheSubnetGroups: %w", err)
+		}
+		for _, g := range page.CacheSubnetGroups {
+			if awssdk.ToString(g.VpcId) != vpcID {
+				continue
+			}
+			raw := terraform.RawElastiCacheSubnetGroup{
+				Name:        awssdk.ToString(g.CacheSubnetGroupName),
+				Description: awssdk.ToString(g.CacheSubnetGroupDescription),
+				VpcID:       vpcID,
+			}
+			tags, err := s.listElastiCacheTags(ctx, awssdk.ToString(g.ARN))
+			if err != nil {
+				return nil, err
+			}
+			raw.Tags = tags
+			for _, sn := range g.Subnets {
+				raw.SubnetIDs = append(raw.SubnetIDs, awssdk.ToString(sn.SubnetIdentifier))
+			}
+			sort.Strings(raw.SubnetIDs)
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// rawElastiCacheCluster は SDK の CacheCluster を RawElastiCacheCluster に変換する（Tags は fillElastiCacheTags で埋める）。
+func rawElastiCacheCluster(c ectypes.CacheCluster, vpcID string) terraform.RawElastiCacheCluster {
+	raw := terraform.RawElastiCacheCluster{
+		ID:                     awssdk.ToString(c.CacheClusterId),
+		Arn:                    awssdk.ToString(c.ARN),
+		VpcID:                  vpcID,
+		Engine:                 awssdk.ToString(c.Engine),
+		EngineVersion:          awssdk.ToString(c.EngineVersion),
+		NodeType:               awssdk.ToString(c.CacheNodeType),
+		NumCacheNodes:          awssdk.ToInt32(c.NumCacheNodes),
+		AvailabilityZone:       awssdk.ToString(c.PreferredAvailabilityZone),
+		SubnetGroupName:        awssdk.ToString(c.CacheSubnetGroupName),
+		SnapshotRetentionLimit: awssdk.ToInt32(c.SnapshotRetentionLimit),
+		SnapshotWindow:         awssdk.ToString(c.SnapshotWindow),
+		MaintenanceWindow:      awssdk.ToString(c.PreferredMaintenanceWindow),
+		Tags:                   map[string]string{},
+	}
+	// Memcached は設定エンドポイント、Redis はノードのエンドポイントがポートを持つ
+	if c.ConfigurationEndpoint != nil {
+		raw.Port = awssdk.ToInt32(c.ConfigurationEndpoint.Port)
+	} else if len(c.CacheNodes) > 0 && c.CacheNodes[0].Endpoint != nil {
+		raw.Port = awssdk.ToInt32(c.CacheNodes[0].Endpoint.Port)
+	}
+	// 複数 AZ に分散した Memcached は PreferredAvailabilityZone が "Multiple" になる
+	if raw.AvailabilityZone == "Multiple" {
+		raw.AvailabilityZone = ""
+	}
+	// 既定のパラメータグループは Terraform 側で指定しない（省略時と同じ）ため、属性にも含めない
+	if c.CacheParameterGroup != nil && !isDefaultGroupName(awssdk.ToString(c.CacheParameterGroup.CacheParameterGroupName)) {
+		raw.ParameterGroupName = awssdk.ToString(c.CacheParameterGroup.CacheParameterGroupName)
+	}
+	for _, sg := range c.SecurityGroups {
+		raw.SecurityGroupIDs = append(raw.SecurityGroupIDs, awssdk.ToString(sg.SecurityGroupId))
+	}
+	sort.Strings(raw.SecurityGroupIDs)
+	return raw
+}
+
+// describeVpcReplicationGroups は DescribeReplicationGroups で、members（レプリケーショングループ ID -> 最初のメンバー）に
+// 含まれるレプリケーショングループを取得する。サブネットグループ等はメンバーの値を用いる。
+func (s *awsVpcDiscoveryService) describeVpcReplicationGroups(ctx context.Context, members map[string]terraform.RawElastiCacheCluster) ([]terraform.RawElastiCacheReplicationGroup, error) {
+	if len(members) == 0 {
+		return nil, nil
+	}
+	var raws []terraform.RawElastiCacheReplicationGroup
+
+	p := elasticache.NewDescribeReplicationGroupsPaginator(s.elasticache, &elasticache.DescribeReplicationGroupsInput{})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeReplicationGroups: %w", err)
+		}
+		for _, g := range page.ReplicationGroups {
+			member, ok := members[awssdk.ToString(g.ReplicationGroupId)]
+			if !ok {
+				continue
+			}
+			raw := terraform.RawElastiCacheReplicationGroup{
+				ID:                       awssdk.ToString(g.ReplicationGroupId),
+				Arn:                      awssdk.ToString(g.ARN),
+				VpcID:                    member.VpcID,
+				Description:              awssdk.ToString(g.Description),
+				Engine:                   awssdk.ToString(g.Engine),
+				EngineVersion:            member.EngineVersion,
+				NodeType:                 awssdk.ToString(g.CacheNodeType),
+				ClusterEnabled:           awssdk.ToBool(g.ClusterEnabled),
+				NumCacheClusters:         int32(len(g.MemberClusters)),
+				AutomaticFailover:        g.AutomaticFailover == ectypes.AutomaticFailoverStatusEnabled,
+				MultiAZ:                  g.MultiAZ == ectypes.MultiAZStatusEnabled,
+				SubnetGroupName:          member.SubnetGroupName,
+				ParameterGroupName:       member.ParameterGroupName,
+				SecurityGroupIDs:         member.SecurityGroupIDs,
+				AtRestEncryptionEnabled:  awssdk.ToBool(g.AtRestEncryptionEnabled),
+				TransitEncryptionEnabled: awssdk.ToBool(g.TransitEncryptionEnabled),
+				KmsKeyID:                 awssdk.ToString(g.KmsKeyId),
+				SnapshotRetentionLimit:   awssdk.ToInt32(g.SnapshotRetentionLimit),
+				SnapshotWindow:           awssdk.ToString(g.SnapshotWindow),
+				MaintenanceWindow:        member.MaintenanceWindow,
+				Tags:                     map[string]string{},
+			}
+			if raw.Engine == "" {
+				raw.Engine = member.Engine
+			}
+			// クラスターモード有効の場合は設定エンドポイント、無効の場合はプライマリエンドポイントがポートを持つ
+			if g.ConfigurationEndpoint != nil {
+				raw.Port = awssdk.ToInt32(g.ConfigurationEndpoint.Port)
+			} else if len(g.NodeGroups) > 0 && g.NodeGroups[0].PrimaryEndpoint != nil {
+				raw.Port = awssdk.ToInt32(g.NodeGroups[0].PrimaryEndpoint.Port)
+			}
+			if raw.ClusterEnabled {
+				raw.NumNodeGroups = int32(len(g.NodeGroups))
+				if len(g.NodeGroups) > 0 && len(g.NodeGroups[0].NodeGroupMembers) > 0 {
+					raw.ReplicasPerNodeGroup = int32(len(g.NodeGroups[0].NodeGroupMembers) - 1)
+				}
+			}
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// describeCacheParameterGroups は names の ElastiCache パラメータグループと、ユーザーが変更したパラメータを取得する。
+func (s *awsVpcDiscoveryService) describeCacheParameterGroups(ctx context.Context, names []string) ([]terraform.RawElastiCacheParameterGroup, error) {
+	var raws []terraform.RawElastiCacheParameterGroup
+	for _, name := range names {
+		out, err := s.elasticache.DescribeCacheParameterGroups(ctx, &elasticache.DescribeCacheParameterGroupsInput{CacheParameterGroupName: awssdk.String(name)})
+		if err != nil {
+			return nil, fmt.Errorf("DescribeCacheParameterGroups(%s): %w", name, err)
+		}
+		for _, g := range out.CacheParameterGroups {
+			raw := terraform.RawElastiCacheParameterGroup{
+				Name:        awssdk.ToString(g.CacheParameterGroupName),
+				Family:      awssdk.ToString(g.CacheParameterGroupFamily),
+				Description: awssdk.ToString(g.Description),
+				Parameters:  map[string]string{},
+			}
+			tags, err := s.listElastiCacheTags(ctx, awssdk.ToString(g.ARN))
+			if err != nil {
+				return nil, err
+			}
+			raw.Tags = tags
+			p := elasticache.NewDescribeCacheParametersPaginator(s.elasticache, &elasticache.DescribeCacheParametersInput{
+				CacheParameterGroupName: g.CacheParameterGroupName,
+				Source:                  awssdk.String("user"),
+			})
+			for p.HasMorePages() {
+				page, err := p.NextPage(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("DescribeCacheParameters(%s): %w", name, err)
+				}
+				for _, param := range page.Parameters {
+					raw.Parameters[awssdk.ToString(param.ParameterName)] = awssdk.ToString(param.ParameterValue)
+				}
+			}
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// fillElastiCacheTags は ListTagsForResource でレプリケーショングループとクラスターのタグを埋める。
+// ElastiCache の Describe 系 API はタグを返さないため、リソースごとに取得する。
+func (s *awsVpcDiscoveryService) fillElastiCacheTags(ctx context.Context, groups []terraform.RawElastiCacheReplicationGroup, clusters []terraform.RawElastiCacheCluster) error {
+	for i := range groups {
+		tags, err := s.listElastiCacheTags(ctx, groups[i].Arn)
+		if err != nil {
+			return err
+		}
+		groups[i].Tags = tags
+	}
+	for i := range clusters {
+		tags, err := s.listElastiCacheTags(ctx, clusters[i].Arn)
+		if err != nil {
+			return err
+		}
+		clusters[i].Tags = tags
+	}
+	return nil
+}
+
+// listElastiCacheTags は arn のタグを map で返す。
+func (s *awsVpcDiscoveryService) listElastiCacheTags(ctx context.Context, arn string) (map[string]string, error) {
+	out, err := s.elasticache.ListTagsForResource(ctx, &elasticache.ListTagsForResourceInput{ResourceName: awssdk.String(arn)})
+	if err != nil {
+		return nil, fmt.Errorf("ListTagsForResource(%s): %w", arn, err)
+	}
+	tags := make(map[string]string, len(out.TagList))
+	for _, t := range out.TagList {
+		tags[awssdk.ToString(t.Key)] = awssdk.ToString(t.Value)
+	}
+	return tags, nil
+}
@@ -0,0 +1,392 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// elbDescribeTagsChunk は DescribeTags に一度に指定できる ARN の上限。
+const elbDescribeTagsChunk = 20
+
+// ListLoadBalancers は VPC 内の ALB / NLB と、ターゲットグループ・リスナー・リスナールール・
+// ターゲットグループへの登録済みターゲットを列挙する。Gateway Load Balancer は対象外。
+func (s *awsVpcDiscoveryService) ListLoadBalancers(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	lbs, err := s.describeVpcLoadBalancers(ctx, vpcID)
+	if err != nil {
+		return nil, nil, err
+	}
+	groups, err := s.describeVpcTargetGroups(ctx, vpcID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var listeners []terraform.RawLbListener
+	for _, lb := range lbs {
+		ls, err := s.describeLbListeners(ctx, lb.Arn)
+		if err != nil {
+			return nil, nil, err
+		}
+		listeners = append(listeners, ls...)
+	}
+
+	if err := s.fillLbTags(ctx, lbs, groups, listeners); err != nil {
+		return nil, nil, err
+	}
+	if err := s.fillLbCertificates(ctx, listeners); err != nil {
+		return nil, nil, err
+	}
+
+	region := ScopeRegion(ctx)
+	resources, relations, err := s.mapper.MapLoadBalancer(lbs, region)
+	if err != nil {
+		return nil, nil, err
+	}
+	tgResources, tgRelations, err := s.mapper.MapLbTargetGroup(groups, region)
+	if err != nil {
+		return nil, nil, err
+	}
+	listenerResources, listenerRelations, err := s.mapper.MapLbListener(listeners, lbs, region)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resources = append(append(resources, tgResources...), listenerResources...)
+	relations = append(append(relations, tgRelations...), listenerRelations...)
+	return resources, relations, nil
+}
+
+// describeVpcLoadBalancers は DescribeLoadBalancers で VPC 内の ALB / NLB を取得する（API に VPC フィルタはない）。
+func (s *awsVpcDiscoveryService) describeVpcLoadBalancers(ctx context.Context, vpcID string) ([]terraform.RawLoadBalancer, error) {
+	var raws []terraform.RawLoadBalancer
+
+	p := elbv2.NewDescribeLoadBalancersPaginator(s.elb, &elbv2.DescribeLoadBalancersInput{})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeLoadBalancers: %w", err)
+		}
+		for _, lb := range page.LoadBalancers {
+			if awssdk.ToString(lb.VpcId) != vpcID {
+				continue
+			}
+			if lb.Type != elbtypes.LoadBalancerTypeEnumApplication && lb.Type != elbtypes.LoadBalancerTypeEnumNetwork {
+				continue
+			}
+			raw := terraform.RawLoadBalancer{
+				Arn:              awssdk.ToString(lb.LoadBalancerArn),
+				Name:             awssdk.ToString(lb.LoadBalancerName),
+				Type:             string(lb.Type),
+				Internal:         lb.Scheme == elbtypes.LoadBalancerSchemeEnumInternal,
+				VpcID:            vpcID,
+				IpAddressType:    string(lb.IpAddressType),
+				SecurityGroupIDs: lb.SecurityGroups,
+			}
+			for _, az := range lb.AvailabilityZones {
+				raw.SubnetIDs = append(raw.SubnetIDs, awssdk.ToString(az.SubnetId))
+			}
+			sort.Strings(raw.SubnetIDs)
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// describeVpcTargetGroups は DescribeTargetGroups で VPC 内のターゲットグループを取得し、
+// DescribeTargetHealth で登録済みターゲットを埋める。Lambda ターゲットグループ（VPC を持たない）は対象外。
+func (s *awsVpcDiscoveryService) describeVpcTargetGroups(ctx context.Context, vpcID string) ([]terraform.RawLbTargetGroup, error) {
+	var raws []terraform.RawLbTargetGroup
+
+	p := elbv2.NewDescribeTargetGroupsPaginator(s.elb, &elbv2.DescribeTargetGroupsInput{})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeTargetGroups: %w", err)
+		}
+		for _, tg := range page.TargetGroups {
+			if awssdk.ToString(tg.VpcId) != vpcID {
+				continue
+			}
+			raw := terraform.RawLbTargetGroup{
+				Arn:             awssdk.ToString(tg.TargetGroupArn),
+				Name:            awssdk.ToString(tg.TargetGroupName),
+				VpcID:           vpcID,
+				Port:            awssdk.ToInt32(tg.Port),
+				Protocol:        string(tg.Protocol),
+				ProtocolVersion: awssdk.ToString(tg.ProtocolVersion),
+				TargetType:      string(tg.TargetType),
+				HealthCheck: terraform.RawLbHealthCheck{
+					Enabled:            awssdk.ToBool(tg.HealthCheckEnabled),
+					Path:               awssdk.ToString(tg.HealthCheckPath),
+					Port:               awssdk.ToString(tg.HealthCheckPort),
+					Protocol:           string(tg.HealthCheckProtocol),
+					Interval:           awssdk.ToInt32(tg.HealthCheckIntervalSeconds),
+					Timeout:            awssdk.ToInt32(tg.HealthCheckTimeoutSeconds),
+					HealthyThreshold:   awssdk.ToInt32(tg.HealthyThresholdCount),
+					UnhealthyThreshold: awssdk.ToInt32(tg.UnhealthyThresholdCount),
+				},
+			}
+			if tg.Matcher != nil {
+				raw.HealthCheck.Matcher = awssdk.ToString(tg.Matcher.HttpCode)
+				if raw.HealthCheck.Matcher == "" {
+					raw.HealthCheck.Matcher = awssdk.ToString(tg.Matcher.GrpcCode)
+				}
+			}
+
+			targets, err := s.describeLbTargets(ctx, raw.Arn)
+			if err != nil {
+				return nil, err
+			}
+			raw.Targets = targets
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// describeLbTargets は DescribeTargetHealth でターゲットグループの登録済みターゲットを取得する。
+// 登録解除中（draining）のターゲットは対象外。
+func (s *awsVpcDiscoveryService) describeLbTargets(ctx context.Context, targetGroupArn string) ([]terraform.RawLbTarget, error) {
+	out, err := s.elb.DescribeTargetHealth(ctx, &elbv2.DescribeTargetHealthInput{TargetGroupArn: awssdk.String(targetGroupArn)})
+	if err != nil {
+		return nil, fmt.Errorf("DescribeTargetHealth: %w", err)
+	}
+
+	var targets []terraform.RawLbTarget
+	for _, d := range out.TargetHealthDescriptions {
+		if d.Target == nil {
+			continue
+		}
+		if d.TargetHealth != nil && d.TargetHealth.State == elbtypes.TargetHealthStateEnumDraining {
+			continue
+		}
+		targets = append(targets, terraform.RawLbTarget{
+			ID:               awssdk.ToString(d.Target.Id),
+			Port:             awssdk.ToInt32(d.Target.Port),
+			AvailabilityZone: awssdk.ToString(d.Target.AvailabilityZone),
+		})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].ID != targets[j].ID {
+			return targets[i].ID < targets[j].ID
+		}
+		return targets[i].Port < targets[j].Port
+	})
+	return targets, nil
+}
+
+// describeLbListeners は DescribeListeners / DescribeRules でロードバランサーのリスナーとルールを取得する。
+func (s *awsVpcDiscoveryService) describeLbListeners(ctx context.Context, lbArn string) ([]terraform.RawLbListener, error) {
+	var raws []terraform.RawLbListener
+
+	p := elbv2.NewDescribeListenersPaginator(s.elb, &elbv2.DescribeListenersInput{LoadBalancerArn: awssdk.String(lbArn)})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeListeners: %w", err)
+		}
+		for _, l := range page.Listeners {
+			raw := terraform.RawLbListener{
+				Arn:             awssdk.ToString(l.ListenerArn),
+				LoadBalancerArn: lbArn,
+				Port:            awssdk.ToInt32(l.Port),
+				Protocol:        string(l.Protocol),
+				SslPolicy:       awssdk.ToString(l.SslPolicy),
+				DefaultActions:  rawLbActions(l.DefaultActions),
+			}
+			// DescribeListeners はデフォルト証明書のみを返す
+			if len(l.Certificates) > 0 {
+				raw.CertificateArn = awssdk.ToString(l.Certificates[0].CertificateArn)
+			}
+
+			rules, err := s.describeLbListenerRules(ctx, raw.Arn)
+			if err != nil {
+				return nil, err
+			}
+			raw.Rules = rules
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// describeLbListenerRules は DescribeRules でリスナーのルールを取得する。デフォルトルールはリスナーの default_action で表現するため対象外。
+func (s *awsVpcDiscoveryService) describeLbListenerRules(ctx context.Context, listenerArn string) ([]terraform.RawLbListenerRule, error) {
+	var raws []terraform.RawLbListenerRule
+
+	p := elbv2.NewDescribeRulesPaginator(s.elb, &elbv2.DescribeRulesInput{ListenerArn: awssdk.String(listenerArn)})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeRules: %w", err)
+		}
+		for _, r := range page.Rules {
+			if awssdk.ToBool(r.IsDefault) {
+				continue
+			}
+			raw := terraform.RawLbListenerRule{
+				Arn:      awssdk.ToString(r.RuleArn),
+				Priority: awssdk.ToString(r.Priority),
+				Actions:  rawLbActions(r.Actions),
+			}
+			for _, c := range r.Conditions {
+				raw.Conditions = append(raw.Conditions, rawLbRuleCondition(c))
+			}
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// rawLbActions はリスナー / ルールのアクションを RawLbAction に変換する。
+func rawLbActions(actions []elbtypes.Action) []terraform.RawLbAction {
+	raws := make([]terraform.RawLbAction, 0, len(actions))
+	for _, a := range actions {
+		raw := terraform.RawLbAction{
+			Type:  string(a.Type),
+			Order: awssdk.ToInt32(a.Order),
+		}
+		switch {
+		case a.ForwardConfig != nil && len(a.ForwardConfig.TargetGroups) > 0:
+			for _, tg := range a.ForwardConfig.TargetGroups {
+				raw.ForwardTargetGroups = append(raw.ForwardTargetGroups, terraform.RawLbWeightedTargetGroup{
+					Arn:    awssdk.ToString(tg.TargetGroupArn),
+					Weight: awssdk.ToInt32(tg.Weight),
+				})
+			}
+		case a.TargetGroupArn != nil:
+			raw.ForwardTargetGroups = []terraform.RawLbWeightedTargetGroup{{Arn: awssdk.ToString(a.TargetGroupArn)}}
+		case a.RedirectConfig != nil:
+			raw.Redirect = map[string]string{
+				"status_code": string(a.RedirectConfig.StatusCode),
+				"host":        awssdk.ToString(a.RedirectConfig.Host),
+				"path":        awssdk.ToString(a.RedirectConfig.Path),
+				"port":        awssdk.ToString(a.RedirectConfig.Port),
+				"protocol":    awssdk.ToString(a.RedirectConfig.Protocol),
+				"query":       awssdk.ToString(a.RedirectConfig.Query),
+			}
+		case a.FixedResponseConfig != nil:
+			raw.FixedResponse = map[string]string{
+				"status_code":  awssdk.ToString(a.FixedResponseConfig.StatusCode),
+				"content_type": awssdk.ToString(a.FixedResponseConfig.ContentType),
+				"message_body": awssdk.ToString(a.FixedResponseConfig.MessageBody),
+			}
+		}
+		raws = append(raws, raw)
+	}
+	return raws
+}
+
+// rawLbRuleCondition はルールの条件を RawLbRuleCondition に変換する。
+func rawLbRuleCondition(c elbtypes.RuleCondition) terraform.RawLbRuleCondition {
+	raw := terraform.RawLbRuleCondition{Field: awssdk.ToString(c.Field), Values: c.Values}
+	switch {
+	case c.HostHeaderConfig != nil:
+		raw.Values = c.HostHeaderConfig.Values
+	case c.PathPatternConfig != nil:
+		raw.Values = c.PathPatternConfig.Values
+	case c.HttpRequestMethodConfig != nil:
+		raw.Values = c.HttpRequestMethodConfig.Values
+	case c.SourceIpConfig != nil:
+		raw.Values = c.SourceIpConfig.Values
+	case c.HttpHeaderConfig != nil:
+		raw.HttpHeaderName = awssdk.ToString(c.HttpHeaderConfig.HttpHeaderName)
+		raw.Values = c.HttpHeaderConfig.Values
+	case c.QueryStringConfig != nil:
+		raw.Values = nil
+		for _, kv := range c.QueryStringConfig.Values {
+			raw.Values = append(raw.Values, awssdk.ToString(kv.Key)+"="+awssdk.ToString(kv.Value))
+		}
+	}
+	return raw
+}
+
+// fillLbCertificates は ACM DescribeCertificate でリスナーのデフォルト証明書のドメイン名とステータスを埋める。
+// ACM のクライアントがない場合・権限がない場合・証明書が存在しない場合は空のままにする（certificate_arn は ARN のまま出力される）。
+func (s *awsVpcDiscoveryService) fillLbCertificates(ctx context.Context, listeners []terraform.RawLbListener) error {
+	if s.acm == nil {
+		return nil
+	}
+	type certificate struct{ domain, status string }
+	certs := make(map[string]certificate)
+	for i := range listeners {
+		arn := listeners[i].CertificateArn
+		if arn == "" {
+			continue
+		}
+		cert, ok := certs[arn]
+		if !ok {
+			out, err := s.acm.DescribeCertificate(ctx, &acm.DescribeCertificateInput{CertificateArn: awssdk.String(arn)})
+			switch {
+			case isNotFound(err):
+				s.logger.Warnf("ACM certificate %s referenced by %s does not exist", arn, listeners[i].Arn)
+			case isAccessDenied(err):
+				s.logger.Warnf("cannot describe ACM certificate %s, certificate_arn is left as a literal ARN: %v", arn, err)
+			case err != nil:
+				return fmt.Errorf("DescribeCertificate %s: %w", arn, err)
+			case out.Certificate != nil:
+				cert = certificate{domain: awssdk.ToString(out.Certificate.DomainName), status: string(out.Certificate.Status)}
+			}
+			certs[arn] = cert
+		}
+		listeners[i].CertificateDomain = cert.domain
+		listeners[i].CertificateStatus = cert.status
+	}
+	return nil
+}
+
+// fillLbTags は DescribeTags でロードバランサー・ターゲットグループ・リスナー・ルールのタグを埋める。
+func (s *awsVpcDiscoveryService) fillLbTags(ctx context.Context, lbs []terraform.RawLoadBalancer, groups []terraform.RawLbTargetGroup, listeners []terraform.RawLbListener) error {
+	tags := make(map[string]*map[string]string)
+	for i := range lbs {
+		tags[lbs[i].Arn] = &lbs[i].Tags
+	}
+	for i := range groups {
+		tags[groups[i].Arn] = &groups[i].Tags
+	}
+	for i := range listeners {
+		tags[listeners[i].Arn] = &listeners[i].Tags
+		for j := range listeners[i].Rules {
+			tags[listeners[i].Rules[j].Arn] = &listeners[i].Rules[j].Tags
+		}
+	}
+
+	arns := make([]string, 0, len(tags))
+	for arn := range tags {
+		arns = append(arns, arn)
+	}
+	sort.Strings(arns)
+
+	for start := 0; start < len(arns); start += elbDescribeTagsChunk {
+		end := min(start+elbDescribeTagsChunk, len(arns))
+		out, err := s.elb.DescribeTags(ctx, &elbv2.DescribeTagsInput{ResourceArns: arns[start:end]})
+		if err != nil {
+			return fmt.Errorf("DescribeTags: %w", err)
+		}
+		for _, d := range out.TagDescriptions {
+			dst, ok := tags[awssdk.ToString(d.ResourceArn)]
+			if !ok {
+				continue
+			}
+			m := make(map[string]string, len(d.Tags))
+			for _, t := range d.Tags {
+				m[awssdk.ToString(t.Key)] = awssdk.ToString(t.Value)
+			}
+			*dst = m
+		}
+	}
+
+	// タグを持たないリソースも空の map にそろえる（HCL の tags = {} を安定させるため）
+	for _, dst := range tags {
+		if *dst == nil {
+			*dst = map[string]string{}
+		}
+	}
+	return nil
+}
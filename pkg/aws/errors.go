@@ -0,0 +1,43 @@
+package aws
+
+import (
+	"errors"
+
+	"github.com/aws/smithy-go"
+)
+
+// accessDeniedCodes は権限不足を表す AWS API のエラーコード。
+// サービスによって表記が異なるため、代表的なものを列挙している。
+var accessDeniedCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+	"UnauthorizedOperation": true,
+	"AuthorizationError":    true,
+	"UnauthorizedException": true,
+}
+
+// isAccessDenied は err が IAM 権限不足による API エラーかどうかを判定する。
+func isAccessDenied(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return accessDeniedCodes[apiErr.ErrorCode()]
+	}
+	return false
+}
+
+// notFoundCodes は参照先のリソースが存在しないことを表す AWS API のエラーコード。
+// 他のリソースから参照されているが削除済みのもの（IAM ロール・KMS キー・ACM 証明書等）を読み飛ばすために利用する。
+var notFoundCodes = map[string]bool{
+	"NoSuchEntity":              true,
+	"NotFoundException":         true,
+	"ResourceNotFoundException": true,
+}
+
+// isNotFound は err が参照先のリソースが存在しないことによる API エラーかどうかを判定する。
+func isNotFound(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return notFoundCodes[apiErr.ErrorCode()]
+	}
+	return false
+}
@@ -0,0 +1,558 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/codebuild"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// APIGuard で管理するサービス名（TokenBucket の単位）。
+const (
+	serviceEc2            = "ec2"
+	serviceElb            = "elasticloadbalancing"
+	serviceAcm            = "acm"
+	serviceRds            = "rds"
+	serviceElastiCache    = "elasticache"
+	serviceEcs            = "ecs"
+	serviceLambda         = "lambda"
+	serviceCodeBuild      = "codebuild"
+	serviceIam            = "iam"
+	serviceKms            = "kms"
+	serviceCloudWatchLogs = "logs"
+	serviceCloudWatch     = "monitoring"
+	serviceSts            = "sts"
+)
+
+// guardedEc2API は Ec2API の各呼び出しに APIGuard を適用するデコレータ。
+type guardedEc2API struct {
+	inner Ec2API
+	guard *APIGuard
+}
+
+// NewGuardedEc2API は inner の各呼び出しに guard のレート制限・リトライ・呼び出し上限を適用した Ec2API を返す。
+func NewGuardedEc2API(inner Ec2API, guard *APIGuard) Ec2API {
+	return &guardedEc2API{inner: inner, guard: guard}
+}
+
+func (c *guardedEc2API) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeVpcsOutput, error) {
+		return c.inner.DescribeVpcs(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeSubnetsOutput, error) {
+		return c.inner.DescribeSubnets(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeRouteTables(ctx context.Context, params *ec2.DescribeRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeRouteTablesOutput, error) {
+		return c.inner.DescribeRouteTables(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeSecurityGroupsOutput, error) {
+		return c.inner.DescribeSecurityGroups(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeInternetGatewaysOutput, error) {
+		return c.inner.DescribeInternetGateways(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeNatGateways(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeNatGatewaysOutput, error) {
+		return c.inner.DescribeNatGateways(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeVpcEndpoints(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeVpcEndpointsOutput, error) {
+		return c.inner.DescribeVpcEndpoints(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeNetworkAcls(ctx context.Context, params *ec2.DescribeNetworkAclsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkAclsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeNetworkAclsOutput, error) {
+		return c.inner.DescribeNetworkAcls(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeVpcPeeringConnections(ctx context.Context, params *ec2.DescribeVpcPeeringConnectionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcPeeringConnectionsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeVpcPeeringConnectionsOutput, error) {
+		return c.inner.DescribeVpcPeeringConnections(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeTransitGatewayVpcAttachments(ctx context.Context, params *ec2.DescribeTransitGatewayVpcAttachmentsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeTransitGatewayVpcAttachmentsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeTransitGatewayVpcAttachmentsOutput, error) {
+		return c.inner.DescribeTransitGatewayVpcAttachments(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeNetworkInterfacesOutput, error) {
+		return c.inner.DescribeNetworkInterfaces(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeAddressesOutput, error) {
+		return c.inner.DescribeAddresses(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeSecurityGroupRules(ctx context.Context, params *ec2.DescribeSecurityGroupRulesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupRulesOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeSecurityGroupRulesOutput, error) {
+		return c.inner.DescribeSecurityGroupRules(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeFlowLogs(ctx context.Context, params *ec2.DescribeFlowLogsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeFlowLogsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeFlowLogsOutput, error) {
+		return c.inner.DescribeFlowLogs(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEc2API) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEc2, func(ctx context.Context) (*ec2.DescribeInstancesOutput, error) {
+		return c.inner.DescribeInstances(ctx, params, optFns...)
+	})
+}
+
+// guardedElbAPI は ElbAPI 向けのデコレータ。
+// ElbAPI にメソッドを追加した際は、guardedEc2API と同様に guardedCall でラップする。
+type guardedElbAPI struct {
+	inner ElbAPI
+	guard *APIGuard
+}
+
+// NewGuardedElbAPI は inner の各呼び出しに guard を適用した ElbAPI を返す。
+func NewGuardedElbAPI(inner ElbAPI, guard *APIGuard) ElbAPI {
+	return &guardedElbAPI{inner: inner, guard: guard}
+}
+
+func (c *guardedElbAPI) DescribeLoadBalancers(ctx context.Context, params *elbv2.DescribeLoadBalancersInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeLoadBalancersOutput, error) {
+	return guardedCall(ctx, c.guard, serviceElb, func(ctx context.Context) (*elbv2.DescribeLoadBalancersOutput, error) {
+		return c.inner.DescribeLoadBalancers(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedElbAPI) DescribeTargetGroups(ctx context.Context, params *elbv2.DescribeTargetGroupsInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTargetGroupsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceElb, func(ctx context.Context) (*elbv2.DescribeTargetGroupsOutput, error) {
+		return c.inner.DescribeTargetGroups(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedElbAPI) DescribeTargetHealth(ctx context.Context, params *elbv2.DescribeTargetHealthInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTargetHealthOutput, error) {
+	return guardedCall(ctx, c.guard, serviceElb, func(ctx context.Context) (*elbv2.DescribeTargetHealthOutput, error) {
+		return c.inner.DescribeTargetHealth(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedElbAPI) DescribeListeners(ctx context.Context, params *elbv2.DescribeListenersInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeListenersOutput, error) {
+	return guardedCall(ctx, c.guard, serviceElb, func(ctx context.Context) (*elbv2.DescribeListenersOutput, error) {
+		return c.inner.DescribeListeners(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedElbAPI) DescribeRules(ctx context.Context, params *elbv2.DescribeRulesInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeRulesOutput, error) {
+	return guardedCall(ctx, c.guard, serviceElb, func(ctx context.Context) (*elbv2.DescribeRulesOutput, error) {
+		return c.inner.DescribeRules(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedElbAPI) DescribeTags(ctx context.Context, params *elbv2.DescribeTagsInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTagsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceElb, func(ctx context.Context) (*elbv2.DescribeTagsOutput, error) {
+		return c.inner.DescribeTags(ctx, params, optFns...)
+	})
+}
+
+// guardedAcmAPI は AcmAPI の各呼び出しに APIGuard を適用するデコレータ。
+type guardedAcmAPI struct {
+	inner AcmAPI
+	guard *APIGuard
+}
+
+// NewGuardedAcmAPI は inner の各呼び出しに guard を適用した AcmAPI を返す。
+func NewGuardedAcmAPI(inner AcmAPI, guard *APIGuard) AcmAPI {
+	return &guardedAcmAPI{inner: inner, guard: guard}
+}
+
+func (c *guardedAcmAPI) DescribeCertificate(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+	return guardedCall(ctx, c.guard, serviceAcm, func(ctx context.Context) (*acm.DescribeCertificateOutput, error) {
+		return c.inner.DescribeCertificate(ctx, params, optFns...)
+	})
+}
+
+// guardedRdsAPI は RdsAPI の各呼び出しに APIGuard を適用するデコレータ。
+type guardedRdsAPI struct {
+	inner RdsAPI
+	guard *APIGuard
+}
+
+// NewGuardedRdsAPI は inner の各呼び出しに guard を適用した RdsAPI を返す。
+func NewGuardedRdsAPI(inner RdsAPI, guard *APIGuard) RdsAPI {
+	return &guardedRdsAPI{inner: inner, guard: guard}
+}
+
+func (c *guardedRdsAPI) DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	return guardedCall(ctx, c.guard, serviceRds, func(ctx context.Context) (*rds.DescribeDBInstancesOutput, error) {
+		return c.inner.DescribeDBInstances(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedRdsAPI) DescribeDBClusters(ctx context.Context, params *rds.DescribeDBClustersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	return guardedCall(ctx, c.guard, serviceRds, func(ctx context.Context) (*rds.DescribeDBClustersOutput, error) {
+		return c.inner.DescribeDBClusters(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedRdsAPI) DescribeDBSubnetGroups(ctx context.Context, params *rds.DescribeDBSubnetGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBSubnetGroupsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceRds, func(ctx context.Context) (*rds.DescribeDBSubnetGroupsOutput, error) {
+		return c.inner.DescribeDBSubnetGroups(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedRdsAPI) DescribeDBParameterGroups(ctx context.Context, params *rds.DescribeDBParameterGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBParameterGroupsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceRds, func(ctx context.Context) (*rds.DescribeDBParameterGroupsOutput, error) {
+		return c.inner.DescribeDBParameterGroups(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedRdsAPI) DescribeDBParameters(ctx context.Context, params *rds.DescribeDBParametersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBParametersOutput, error) {
+	return guardedCall(ctx, c.guard, serviceRds, func(ctx context.Context) (*rds.DescribeDBParametersOutput, error) {
+		return c.inner.DescribeDBParameters(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedRdsAPI) DescribeDBClusterParameterGroups(ctx context.Context, params *rds.DescribeDBClusterParameterGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClusterParameterGroupsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceRds, func(ctx context.Context) (*rds.DescribeDBClusterParameterGroupsOutput, error) {
+		return c.inner.DescribeDBClusterParameterGroups(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedRdsAPI) DescribeDBClusterParameters(ctx context.Context, params *rds.DescribeDBClusterParametersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClusterParametersOutput, error) {
+	return guardedCall(ctx, c.guard, serviceRds, func(ctx context.Context) (*rds.DescribeDBClusterParametersOutput, error) {
+		return c.inner.DescribeDBClusterParameters(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedRdsAPI) DescribeOptionGroups(ctx context.Context, params *rds.DescribeOptionGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeOptionGroupsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceRds, func(ctx context.Context) (*rds.DescribeOptionGroupsOutput, error) {
+		return c.inner.DescribeOptionGroups(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedRdsAPI) ListTagsForResource(ctx context.Context, params *rds.ListTagsForResourceInput, optFns ...func(*rds.Options)) (*rds.ListTagsForResourceOutput, error) {
+	return guardedCall(ctx, c.guard, serviceRds, func(ctx context.Context) (*rds.ListTagsForResourceOutput, error) {
+		return c.inner.ListTagsForResource(ctx, params, optFns...)
+	})
+}
+
+// guardedElastiCacheAPI は ElastiCacheAPI の各呼び出しに APIGuard を適用するデコレータ。
+type guardedElastiCacheAPI struct {
+	inner ElastiCacheAPI
+	guard *APIGuard
+}
+
+// NewGuardedElastiCacheAPI は inner の各呼び出しに guard を適用した ElastiCacheAPI を返す。
+func NewGuardedElastiCacheAPI(inner ElastiCacheAPI, guard *APIGuard) ElastiCacheAPI {
+	return &guardedElastiCacheAPI{inner: inner, guard: guard}
+}
+
+func (c *guardedElastiCacheAPI) DescribeCacheClusters(ctx context.Context, params *elasticache.DescribeCacheClustersInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
+	return guardedCall(ctx, c.guard, serviceElastiCache, func(ctx context.Context) (*elasticache.DescribeCacheClustersOutput, error) {
+		return c.inner.DescribeCacheClusters(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedElastiCacheAPI) DescribeReplicationGroups(ctx context.Context, params *elasticache.DescribeReplicationGroupsInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceElastiCache, func(ctx context.Context) (*elasticache.DescribeReplicationGroupsOutput, error) {
+		return c.inner.DescribeReplicationGroups(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedElastiCacheAPI) DescribeCacheSubnetGroups(ctx context.Context, params *elasticache.DescribeCacheSubnetGroupsInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheSubnetGroupsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceElastiCache, func(ctx context.Context) (*elasticache.DescribeCacheSubnetGroupsOutput, error) {
+		return c.inner.DescribeCacheSubnetGroups(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedElastiCacheAPI) DescribeCacheParameterGroups(ctx context.Context, params *elasticache.DescribeCacheParameterGroupsInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheParameterGroupsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceElastiCache, func(ctx context.Context) (*elasticache.DescribeCacheParameterGroupsOutput, error) {
+		return c.inner.DescribeCacheParameterGroups(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedElastiCacheAPI) DescribeCacheParameters(ctx context.Context, params *elasticache.DescribeCacheParametersInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheParametersOutput, error) {
+	return guardedCall(ctx, c.guard, serviceElastiCache, func(ctx context.Context) (*elasticache.DescribeCacheParametersOutput, error) {
+		return c.inner.DescribeCacheParameters(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedElastiCacheAPI) ListTagsForResource(ctx context.Context, params *elasticache.ListTagsForResourceInput, optFns ...func(*elasticache.Options)) (*elasticache.ListTagsForResourceOutput, error) {
+	return guardedCall(ctx, c.guard, serviceElastiCache, func(ctx context.Context) (*elasticache.ListTagsForResourceOutput, error) {
+		return c.inner.ListTagsForResource(ctx, params, optFns...)
+	})
+}
+
+// guardedEcsAPI は EcsAPI の各呼び出しに APIGuard を適用するデコレータ。
+type guardedEcsAPI struct {
+	inner EcsAPI
+	guard *APIGuard
+}
+
+// NewGuardedEcsAPI は inner の各呼び出しに guard を適用した EcsAPI を返す。
+func NewGuardedEcsAPI(inner EcsAPI, guard *APIGuard) EcsAPI {
+	return &guardedEcsAPI{inner: inner, guard: guard}
+}
+
+func (c *guardedEcsAPI) ListClusters(ctx context.Context, params *ecs.ListClustersInput, optFns ...func(*ecs.Options)) (*ecs.ListClustersOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEcs, func(ctx context.Context) (*ecs.ListClustersOutput, error) {
+		return c.inner.ListClusters(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEcsAPI) DescribeClusters(ctx context.Context, params *ecs.DescribeClustersInput, optFns ...func(*ecs.Options)) (*ecs.DescribeClustersOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEcs, func(ctx context.Context) (*ecs.DescribeClustersOutput, error) {
+		return c.inner.DescribeClusters(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEcsAPI) ListServices(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEcs, func(ctx context.Context) (*ecs.ListServicesOutput, error) {
+		return c.inner.ListServices(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEcsAPI) DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEcs, func(ctx context.Context) (*ecs.DescribeServicesOutput, error) {
+		return c.inner.DescribeServices(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEcsAPI) DescribeTaskDefinition(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEcs, func(ctx context.Context) (*ecs.DescribeTaskDefinitionOutput, error) {
+		return c.inner.DescribeTaskDefinition(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedEcsAPI) DescribeCapacityProviders(ctx context.Context, params *ecs.DescribeCapacityProvidersInput, optFns ...func(*ecs.Options)) (*ecs.DescribeCapacityProvidersOutput, error) {
+	return guardedCall(ctx, c.guard, serviceEcs, func(ctx context.Context) (*ecs.DescribeCapacityProvidersOutput, error) {
+		return c.inner.DescribeCapacityProviders(ctx, params, optFns...)
+	})
+}
+
+// guardedLambdaAPI は LambdaAPI の各呼び出しに APIGuard を適用するデコレータ。
+type guardedLambdaAPI struct {
+	inner LambdaAPI
+	guard *APIGuard
+}
+
+// NewGuardedLambdaAPI は inner の各呼び出しに guard を適用した LambdaAPI を返す。
+func NewGuardedLambdaAPI(inner LambdaAPI, guard *APIGuard) LambdaAPI {
+	return &guardedLambdaAPI{inner: inner, guard: guard}
+}
+
+func (c *guardedLambdaAPI) ListFunctions(ctx context.Context, params *lambda.ListFunctionsInput, optFns ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceLambda, func(ctx context.Context) (*lambda.ListFunctionsOutput, error) {
+		return c.inner.ListFunctions(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedLambdaAPI) GetFunction(ctx context.Context, params *lambda.GetFunctionInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error) {
+	return guardedCall(ctx, c.guard, serviceLambda, func(ctx context.Context) (*lambda.GetFunctionOutput, error) {
+		return c.inner.GetFunction(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedLambdaAPI) ListEventSourceMappings(ctx context.Context, params *lambda.ListEventSourceMappingsInput, optFns ...func(*lambda.Options)) (*lambda.ListEventSourceMappingsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceLambda, func(ctx context.Context) (*lambda.ListEventSourceMappingsOutput, error) {
+		return c.inner.ListEventSourceMappings(ctx, params, optFns...)
+	})
+}
+
+// guardedCodeBuildAPI は CodeBuildAPI の各呼び出しに APIGuard を適用するデコレータ。
+type guardedCodeBuildAPI struct {
+	inner CodeBuildAPI
+	guard *APIGuard
+}
+
+// NewGuardedCodeBuildAPI は inner の各呼び出しに guard を適用した CodeBuildAPI を返す。
+func NewGuardedCodeBuildAPI(inner CodeBuildAPI, guard *APIGuard) CodeBuildAPI {
+	return &guardedCodeBuildAPI{inner: inner, guard: guard}
+}
+
+func (c *guardedCodeBuildAPI) ListProjects(ctx context.Context, params *codebuild.ListProjectsInput, optFns ...func(*codebuild.Options)) (*codebuild.ListProjectsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceCodeBuild, func(ctx context.Context) (*codebuild.ListProjectsOutput, error) {
+		return c.inner.ListProjects(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedCodeBuildAPI) BatchGetProjects(ctx context.Context, params *codebuild.BatchGetProjectsInput, optFns ...func(*codebuild.Options)) (*codebuild.BatchGetProjectsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceCodeBuild, func(ctx context.Context) (*codebuild.BatchGetProjectsOutput, error) {
+		return c.inner.BatchGetProjects(ctx, params, optFns...)
+	})
+}
+
+// guardedIamAPI は IamAPI の各呼び出しに APIGuard を適用するデコレータ。
+type guardedIamAPI struct {
+	inner IamAPI
+	guard *APIGuard
+}
+
+// NewGuardedIamAPI は inner の各呼び出しに guard を適用した IamAPI を返す。
+func NewGuardedIamAPI(inner IamAPI, guard *APIGuard) IamAPI {
+	return &guardedIamAPI{inner: inner, guard: guard}
+}
+
+func (c *guardedIamAPI) GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+	return guardedCall(ctx, c.guard, serviceIam, func(ctx context.Context) (*iam.GetRoleOutput, error) {
+		return c.inner.GetRole(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedIamAPI) GetInstanceProfile(ctx context.Context, params *iam.GetInstanceProfileInput, optFns ...func(*iam.Options)) (*iam.GetInstanceProfileOutput, error) {
+	return guardedCall(ctx, c.guard, serviceIam, func(ctx context.Context) (*iam.GetInstanceProfileOutput, error) {
+		return c.inner.GetInstanceProfile(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedIamAPI) ListRolePolicies(ctx context.Context, params *iam.ListRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error) {
+	return guardedCall(ctx, c.guard, serviceIam, func(ctx context.Context) (*iam.ListRolePoliciesOutput, error) {
+		return c.inner.ListRolePolicies(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedIamAPI) GetRolePolicy(ctx context.Context, params *iam.GetRolePolicyInput, optFns ...func(*iam.Options)) (*iam.GetRolePolicyOutput, error) {
+	return guardedCall(ctx, c.guard, serviceIam, func(ctx context.Context) (*iam.GetRolePolicyOutput, error) {
+		return c.inner.GetRolePolicy(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedIamAPI) ListAttachedRolePolicies(ctx context.Context, params *iam.ListAttachedRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error) {
+	return guardedCall(ctx, c.guard, serviceIam, func(ctx context.Context) (*iam.ListAttachedRolePoliciesOutput, error) {
+		return c.inner.ListAttachedRolePolicies(ctx, params, optFns...)
+	})
+}
+
+// guardedKmsAPI は KmsAPI の各呼び出しに APIGuard を適用するデコレータ。
+type guardedKmsAPI struct {
+	inner KmsAPI
+	guard *APIGuard
+}
+
+// NewGuardedKmsAPI は inner の各呼び出しに guard を適用した KmsAPI を返す。
+func NewGuardedKmsAPI(inner KmsAPI, guard *APIGuard) KmsAPI {
+	return &guardedKmsAPI{inner: inner, guard: guard}
+}
+
+func (c *guardedKmsAPI) DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error) {
+	return guardedCall(ctx, c.guard, serviceKms, func(ctx context.Context) (*kms.DescribeKeyOutput, error) {
+		return c.inner.DescribeKey(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedKmsAPI) ListAliases(ctx context.Context, params *kms.ListAliasesInput, optFns ...func(*kms.Options)) (*kms.ListAliasesOutput, error) {
+	return guardedCall(ctx, c.guard, serviceKms, func(ctx context.Context) (*kms.ListAliasesOutput, error) {
+		return c.inner.ListAliases(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedKmsAPI) GetKeyPolicy(ctx context.Context, params *kms.GetKeyPolicyInput, optFns ...func(*kms.Options)) (*kms.GetKeyPolicyOutput, error) {
+	return guardedCall(ctx, c.guard, serviceKms, func(ctx context.Context) (*kms.GetKeyPolicyOutput, error) {
+		return c.inner.GetKeyPolicy(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedKmsAPI) GetKeyRotationStatus(ctx context.Context, params *kms.GetKeyRotationStatusInput, optFns ...func(*kms.Options)) (*kms.GetKeyRotationStatusOutput, error) {
+	return guardedCall(ctx, c.guard, serviceKms, func(ctx context.Context) (*kms.GetKeyRotationStatusOutput, error) {
+		return c.inner.GetKeyRotationStatus(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedKmsAPI) ListResourceTags(ctx context.Context, params *kms.ListResourceTagsInput, optFns ...func(*kms.Options)) (*kms.ListResourceTagsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceKms, func(ctx context.Context) (*kms.ListResourceTagsOutput, error) {
+		return c.inner.ListResourceTags(ctx, params, optFns...)
+	})
+}
+
+// guardedCloudWatchLogsAPI は CloudWatchLogsAPI の各呼び出しに APIGuard を適用するデコレータ。
+type guardedCloudWatchLogsAPI struct {
+	inner CloudWatchLogsAPI
+	guard *APIGuard
+}
+
+// NewGuardedCloudWatchLogsAPI は inner の各呼び出しに guard を適用した CloudWatchLogsAPI を返す。
+func NewGuardedCloudWatchLogsAPI(inner CloudWatchLogsAPI, guard *APIGuard) CloudWatchLogsAPI {
+	return &guardedCloudWatchLogsAPI{inner: inner, guard: guard}
+}
+
+func (c *guardedCloudWatchLogsAPI) DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceCloudWatchLogs, func(ctx context.Context) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+		return c.inner.DescribeLogGroups(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedCloudWatchLogsAPI) ListTagsForResource(ctx context.Context, params *cloudwatchlogs.ListTagsForResourceInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.ListTagsForResourceOutput, error) {
+	return guardedCall(ctx, c.guard, serviceCloudWatchLogs, func(ctx context.Context) (*cloudwatchlogs.ListTagsForResourceOutput, error) {
+		return c.inner.ListTagsForResource(ctx, params, optFns...)
+	})
+}
+
+// guardedCloudWatchAPI は CloudWatchAPI の各呼び出しに APIGuard を適用するデコレータ。
+type guardedCloudWatchAPI struct {
+	inner CloudWatchAPI
+	guard *APIGuard
+}
+
+// NewGuardedCloudWatchAPI は inner の各呼び出しに guard を適用した CloudWatchAPI を返す。
+func NewGuardedCloudWatchAPI(inner CloudWatchAPI, guard *APIGuard) CloudWatchAPI {
+	return &guardedCloudWatchAPI{inner: inner, guard: guard}
+}
+
+func (c *guardedCloudWatchAPI) DescribeAlarms(ctx context.Context, params *cloudwatch.DescribeAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error) {
+	return guardedCall(ctx, c.guard, serviceCloudWatch, func(ctx context.Context) (*cloudwatch.DescribeAlarmsOutput, error) {
+		return c.inner.DescribeAlarms(ctx, params, optFns...)
+	})
+}
+
+func (c *guardedCloudWatchAPI) ListTagsForResource(ctx context.Context, params *cloudwatch.ListTagsForResourceInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListTagsForResourceOutput, error) {
+	return guardedCall(ctx, c.guard, serviceCloudWatch, func(ctx context.Context) (*cloudwatch.ListTagsForResourceOutput, error) {
+		return c.inner.ListTagsForResource(ctx, params, optFns...)
+	})
+}
+
+// guardedStsAPI は StsAPI の各呼び出しに APIGuard を適用するデコレータ。
+type guardedStsAPI struct {
+	inner StsAPI
+	guard *APIGuard
+}
+
+// NewGuardedStsAPI は inner の各呼び出しに guard を適用した StsAPI を返す。
+func NewGuardedStsAPI(inner StsAPI, guard *APIGuard) StsAPI {
+	return &guardedStsAPI{inner: inner, guard: guard}
+}
+
+func (c *guardedStsAPI) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	return guardedCall(ctx, c.guard, serviceSts, func(ctx context.Context) (*sts.GetCallerIdentityOutput, error) {
+		return c.inner.GetCallerIdentity(ctx, params, optFns...)
+	})
+}
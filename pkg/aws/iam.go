@@ -0,0 +1,162 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// iamWorkloadListers は IAM ロール / インスタンスプロファイルを参照するワークロードの lister。
+var iamWorkloadListers = []string{"aws_instance", "aws_ecs_service", "aws_lambda_function", "aws_codebuild_project", "aws_flow_log"}
+
+// iamRoleAttributes はワークロードの Resource でロール ARN を保持する属性名。
+var iamRoleAttributes = []string{"role", "service_role", "iam_role", "task_role_arn", "execution_role_arn", "iam_role_arn"}
+
+// ListIamRoles は VPC 内のワークロード（EC2 インスタンス・ECS・Lambda・CodeBuild・フローログ）が参照する IAM ロールと、
+// そのインラインポリシー・アタッチされたマネージドポリシー、インスタンスプロファイルを列挙する。
+// ロールはワークロードの lister の列挙結果から取得するため、アカウント内の全ロールは列挙しない。
+// サービスリンクロール（/aws-service-role/）は対象外。削除済みのロールを参照している場合は WARN ログを出して続行する。
+func (s *awsVpcDiscoveryService) ListIamRoles(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var roleNames, profileNames []string
+	for _, typeName := range iamWorkloadListers {
+		for _, r := range UpstreamResources(ctx, typeName) {
+			for _, key := range iamRoleAttributes {
+				if arn, ok := r.Attributes[key].(string); ok {
+					roleNames = appendUniqueName(roleNames, iamRoleNameFromArn(arn))
+				}
+			}
+			if name, ok := r.Attributes["iam_instance_profile"].(string); ok {
+				profileNames = appendUniqueName(profileNames, name)
+			}
+		}
+	}
+
+	var profiles []terraform.RawIamInstanceProfile
+	for _, name := range profileNames {
+		out, err := s.iam.GetInstanceProfile(ctx, &iam.GetInstanceProfileInput{InstanceProfileName: awssdk.String(name)})
+		if isNotFound(err) {
+			s.logger.Warnf("instance profile %s is referenced but does not exist", name)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("GetInstanceProfile %s: %w", name, err)
+		}
+		p := out.InstanceProfile
+		raw := terraform.RawIamInstanceProfile{
+			Name: awssdk.ToString(p.InstanceProfileName),
+			Arn:  awssdk.ToString(p.Arn),
+			Path: awssdk.ToString(p.Path),
+			Tags: iamTagsToMap(p.Tags),
+		}
+		// インスタンスプロファイルに関連付けられるロールは 1 つまで
+		if len(p.Roles) > 0 {
+			raw.RoleName = awssdk.ToString(p.Roles[0].RoleName)
+			roleNames = appendUniqueName(roleNames, raw.RoleName)
+		}
+		profiles = append(profiles, raw)
+	}
+
+	sort.Strings(roleNames)
+	var roles []terraform.RawIamRole
+	for _, name := range roleNames {
+		raw, ok, err := s.describeIamRole(ctx, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			roles = append(roles, raw)
+		}
+	}
+
+	return s.mapper.MapIamRole(roles, profiles, ScopeRegion(ctx), vpcID)
+}
+
+// describeIamRole はロールと、そのインラインポリシー・アタッチされたマネージドポリシーを取得する。
+// ロールが存在しない場合とサービスリンクロールの場合は ok = false を返す。
+func (s *awsVpcDiscoveryService) describeIamRole(ctx context.Context, name string) (terraform.RawIamRole, bool, error) {
+	out, err := s.iam.GetRole(ctx, &iam.GetRoleInput{RoleName: awssdk.String(name)})
+	if isNotFound(err) {
+		s.logger.Warnf("IAM role %s is referenced but does not exist", name)
+		return terraform.RawIamRole{}, false, nil
+	}
+	if err != nil {
+		return terraform.RawIamRole{}, false, fmt.Errorf("GetRole %s: %w", name, err)
+	}
+	role := out.Role
+	if strings.HasPrefix(awssdk.ToString(role.Path), "/aws-service-role/") {
+		return terraform.RawIamRole{}, false, nil
+	}
+
+	trust, err := url.QueryUnescape(awssdk.ToString(role.AssumeRolePolicyDocument))
+	if err != nil {
+		return terraform.RawIamRole{}, false, fmt.Errorf("assume role policy of %s: %w", name, err)
+	}
+	raw := terraform.RawIamRole{
+		Name:               name,
+		Arn:                awssdk.ToString(role.Arn),
+		Path:               awssdk.ToString(role.Path),
+		Description:        awssdk.ToString(role.Description),
+		AssumeRolePolicy:   trust,
+		MaxSessionDuration: awssdk.ToInt32(role.MaxSessionDuration),
+		Tags:               iamTagsToMap(role.Tags),
+	}
+	if role.PermissionsBoundary != nil {
+		raw.PermissionsBoundary = awssdk.ToString(role.PermissionsBoundary.PermissionsBoundaryArn)
+	}
+
+	inline := iam.NewListRolePoliciesPaginator(s.iam, &iam.ListRolePoliciesInput{RoleName: awssdk.String(name)})
+	for inline.HasMorePages() {
+		page, err := inline.NextPage(ctx)
+		if err != nil {
+			return terraform.RawIamRole{}, false, fmt.Errorf("ListRolePolicies %s: %w", name, err)
+		}
+		for _, policyName := range page.PolicyNames {
+			policy, err := s.iam.GetRolePolicy(ctx, &iam.GetRolePolicyInput{RoleName: awssdk.String(name), PolicyName: awssdk.String(policyName)})
+			if err != nil {
+				return terraform.RawIamRole{}, false, fmt.Errorf("GetRolePolicy %s/%s: %w", name, policyName, err)
+			}
+			doc, err := url.QueryUnescape(awssdk.ToString(policy.PolicyDocument))
+			if err != nil {
+				return terraform.RawIamRole{}, false, fmt.Errorf("policy document of %s/%s: %w", name, policyName, err)
+			}
+			raw.InlinePolicies = append(raw.InlinePolicies, terraform.RawIamRolePolicy{Name: policyName, Policy: doc})
+		}
+	}
+
+	attached := iam.NewListAttachedRolePoliciesPaginator(s.iam, &iam.ListAttachedRolePoliciesInput{RoleName: awssdk.String(name)})
+	for attached.HasMorePages() {
+		page, err := attached.NextPage(ctx)
+		if err != nil {
+			return terraform.RawIamRole{}, false, fmt.Errorf("ListAttachedRolePolicies %s: %w", name, err)
+		}
+		for _, p := range page.AttachedPolicies {
+			raw.AttachedPolicyArns = append(raw.AttachedPolicyArns, awssdk.ToString(p.PolicyArn))
+		}
+	}
+	return raw, true, nil
+}
+
+// iamRoleNameFromArn はロール ARN（arn:aws:iam::<account>:role/<path>/<name>）からロール名を返す。ロール ARN でない場合は空文字を返す。
+func iamRoleNameFromArn(arn string) string {
+	if !strings.Contains(arn, ":role/") {
+		return ""
+	}
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+// iamTagsToMap は IAM のタグを map に変換する。
+func iamTagsToMap(tags []iamtypes.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, t := range tags {
+		m[awssdk.ToString(t.Key)] = awssdk.ToString(t.Value)
+	}
+	return m
+}
@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// kmsEncryptedListers は KMS キーで暗号化されたリソースの lister。
+var kmsEncryptedListers = []string{"aws_db_instance", "aws_elasticache_cluster", "aws_lambda_function", "aws_codebuild_project", "aws_cloudwatch_log_group"}
+
+// kmsKeyAttributes は暗号化されたリソースの Resource で KMS キー（ARN / キー ID / エイリアス）を保持する属性名。
+var kmsKeyAttributes = []string{"kms_key_id", "kms_key_arn", "encryption_key"}
+
+// ListKmsKeys は VPC 内のリソース（RDS・ElastiCache・Lambda・CodeBuild・CloudWatch Logs）が暗号化に利用する KMS キーとそのエイリアスを列挙する。
+// キーは暗号化されたリソースの lister の列挙結果から取得するため、アカウント内の全キーは列挙しない。
+// EBS ボリューム・Secrets Manager のシークレットはこれらの lister がまだないため対象外。
+// AWS 管理キー（alias/aws/*）は import せず data "aws_kms_alias" として出力する。
+// 存在しないキーと削除待ちのキーは WARN ログを出して対象外にする。
+func (s *awsVpcDiscoveryService) ListKmsKeys(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var order []string
+	keys := make(map[string]*terraform.RawKmsKey)
+	// resolved は参照（ARN / キー ID / エイリアス）ごとのキー ID。対象外のキーは空文字。
+	resolved := make(map[string]string)
+	for _, typeName := range kmsEncryptedListers {
+		for _, r := range UpstreamResources(ctx, typeName) {
+			for _, attr := range kmsKeyAttributes {
+				ref, ok := r.Attributes[attr].(string)
+				if !ok || ref == "" {
+					continue
+				}
+				keyID, ok := resolved[ref]
+				if !ok {
+					key, err := s.describeKmsKeyRef(ctx, ref, r.ID)
+					if err != nil {
+						return nil, nil, err
+					}
+					if key != nil {
+						keyID = key.KeyID
+						if _, dup := keys[keyID]; !dup {
+							keys[keyID] = key
+							order = append(order, keyID)
+						}
+					}
+					resolved[ref] = keyID
+				}
+				if keyID == "" {
+					continue
+				}
+				referrer := terraform.RawKmsReferrer{ResourceID: r.ID}
+				if strings.HasPrefix(ref, "alias/") {
+					referrer.Alias = ref
+				} else if _, alias, ok := strings.Cut(ref, ":alias/"); ok {
+					referrer.Alias = "alias/" + alias
+				}
+				keys[keyID].Referrers = append(keys[keyID].Referrers, referrer)
+			}
+		}
+	}
+
+	var raws []terraform.RawKmsKey
+	for _, keyID := range order {
+		key := keys[keyID]
+		if err := s.describeKmsKey(ctx, key); err != nil {
+			return nil, nil, err
+		}
+		raws = append(raws, *key)
+	}
+
+	return s.mapper.MapKmsKey(raws, ScopeRegion(ctx), vpcID)
+}
+
+// describeKmsKeyRef は ref（ARN / キー ID / エイリアス）が指すキーを DescribeKey で取得する。
+// 存在しないキーと削除待ちのキーは WARN ログを出して nil を返す。referrer はログ出力用の参照元 Resource ID。
+func (s *awsVpcDiscoveryService) describeKmsKeyRef(ctx context.Context, ref string, referrer string) (*terraform.RawKmsKey, error) {
+	out, err := s.kms.DescribeKey(ctx, &kms.DescribeKeyInput{KeyId: awssdk.String(ref)})
+	if isNotFound(err) {
+		s.logger.Warnf("KMS key %s referenced by %s does not exist", ref, referrer)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("DescribeKey %s: %w", ref, err)
+	}
+	meta := out.KeyMetadata
+	if meta.KeyState == kmstypes.KeyStatePendingDeletion {
+		s.logger.Warnf("KMS key %s referenced by %s is pending deletion", ref, referrer)
+		return nil, nil
+	}
+	return &terraform.RawKmsKey{
+		KeyID:       awssdk.ToString(meta.KeyId),
+		Arn:         awssdk.ToString(meta.Arn),
+		Description: awssdk.ToString(meta.Description),
+		AwsManaged:  meta.KeyManager == kmstypes.KeyManagerTypeAws,
+		Enabled:     meta.Enabled,
+		KeyUsage:    string(meta.KeyUsage),
+		KeySpec:     string(meta.KeySpec),
+		MultiRegion: awssdk.ToBool(meta.MultiRegion),
+	}, nil
+}
+
+// describeKmsKey はキーのエイリアスを取得し、カスタマー管理キーの場合はキーポリシー・ローテーション設定・タグも取得する。
+func (s *awsVpcDiscoveryService) describeKmsKey(ctx context.Context, key *terraform.RawKmsKey) error {
+	aliases := kms.NewListAliasesPaginator(s.kms, &kms.ListAliasesInput{KeyId: awssdk.String(key.KeyID)})
+	for aliases.HasMorePages() {
+		page, err := aliases.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("ListAliases %s: %w", key.KeyID, err)
+		}
+		for _, a := range page.Aliases {
+			key.Aliases = append(key.Aliases, terraform.RawKmsAlias{Name: awssdk.ToString(a.AliasName), Arn: awssdk.ToString(a.AliasArn)})
+		}
+	}
+	if key.AwsManaged {
+		return nil
+	}
+
+	policy, err := s.kms.GetKeyPolicy(ctx, &kms.GetKeyPolicyInput{KeyId: awssdk.String(key.KeyID), PolicyName: awssdk.String("default")})
+	if err != nil {
+		return fmt.Errorf("GetKeyPolicy %s: %w", key.KeyID, err)
+	}
+	key.Policy = awssdk.ToString(policy.Policy)
+
+	// 自動ローテーションは対称暗号化キーのみ対応しており、それ以外では GetKeyRotationStatus がエラーになる
+	if key.KeySpec == string(kmstypes.KeySpecSymmetricDefault) {
+		rotation, err := s.kms.GetKeyRotationStatus(ctx, &kms.GetKeyRotationStatusInput{KeyId: awssdk.String(key.KeyID)})
+		if err != nil {
+			return fmt.Errorf("GetKeyRotationStatus %s: %w", key.KeyID, err)
+		}
+		key.RotationEnabled = rotation.KeyRotationEnabled
+		key.RotationPeriodDays = awssdk.ToInt32(rotation.RotationPeriodInDays)
+	}
+
+	key.Tags = map[string]string{}
+	tags := kms.NewListResourceTagsPaginator(s.kms, &kms.ListResourceTagsInput{KeyId: awssdk.String(key.KeyID)})
+	for tags.HasMorePages() {
+		page, err := tags.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("ListResourceTags %s: %w", key.KeyID, err)
+		}
+		for _, t := range page.Tags {
+			key.Tags[awssdk.ToString(t.TagKey)] = awssdk.ToString(t.TagValue)
+		}
+	}
+	return nil
+}
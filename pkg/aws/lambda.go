@@ -0,0 +1,138 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// ListLambdaFunctions は vpc_config で VPC に接続された Lambda 関数（$LATEST）と、
+// その SQS / DynamoDB Streams のイベントソースマッピングを列挙する。
+// ListFunctions に VPC フィルタはないため、VpcConfig.VpcId で絞り込む。デプロイパッケージ（コード）は取得しない。
+func (s *awsVpcDiscoveryService) ListLambdaFunctions(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	var names []string
+	p := lambda.NewListFunctionsPaginator(s.lambda, &lambda.ListFunctionsInput{})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ListFunctions: %w", err)
+		}
+		for _, f := range page.Functions {
+			if f.VpcConfig != nil && awssdk.ToString(f.VpcConfig.VpcId) == vpcID {
+				names = append(names, awssdk.ToString(f.FunctionName))
+			}
+		}
+	}
+
+	var functions []terraform.RawLambdaFunction
+	var mappings []terraform.RawLambdaEventSourceMapping
+	for _, name := range names {
+		// タグとコンテナイメージの URI は GetFunction でのみ取得できる
+		out, err := s.lambda.GetFunction(ctx, &lambda.GetFunctionInput{FunctionName: awssdk.String(name)})
+		if err != nil {
+			return nil, nil, fmt.Errorf("GetFunction %s: %w", name, err)
+		}
+		raw := rawLambdaFunction(out.Configuration, vpcID)
+		raw.Tags = out.Tags
+		if raw.Tags == nil {
+			raw.Tags = map[string]string{}
+		}
+		if out.Code != nil {
+			raw.ImageURI = awssdk.ToString(out.Code.ImageUri)
+		}
+		functions = append(functions, raw)
+
+		found, err := s.listLambdaEventSourceMappings(ctx, name, vpcID)
+		if err != nil {
+			return nil, nil, err
+		}
+		mappings = append(mappings, found...)
+	}
+
+	return s.mapper.MapLambdaFunction(functions, mappings, ScopeRegion(ctx))
+}
+
+// listLambdaEventSourceMappings は関数の SQS / DynamoDB Streams のイベントソースマッピングを返す。
+func (s *awsVpcDiscoveryService) listLambdaEventSourceMappings(ctx context.Context, functionName string, vpcID string) ([]terraform.RawLambdaEventSourceMapping, error) {
+	var raws []terraform.RawLambdaEventSourceMapping
+
+	p := lambda.NewListEventSourceMappingsPaginator(s.lambda, &lambda.ListEventSourceMappingsInput{FunctionName: awssdk.String(functionName)})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ListEventSourceMappings %s: %w", functionName, err)
+		}
+		for _, e := range page.EventSourceMappings {
+			source := awssdk.ToString(e.EventSourceArn)
+			if !strings.Contains(source, ":sqs:") && !strings.Contains(source, ":dynamodb:") {
+				continue
+			}
+			state := awssdk.ToString(e.State)
+			raws = append(raws, terraform.RawLambdaEventSourceMapping{
+				UUID:                           awssdk.ToString(e.UUID),
+				FunctionName:                   functionName,
+				VpcID:                          vpcID,
+				EventSourceArn:                 source,
+				Enabled:                        state != "Disabled" && state != "Disabling",
+				BatchSize:                      awssdk.ToInt32(e.BatchSize),
+				MaximumBatchingWindowInSeconds: awssdk.ToInt32(e.MaximumBatchingWindowInSeconds),
+				StartingPosition:               string(e.StartingPosition),
+			})
+		}
+	}
+	return raws, nil
+}
+
+// rawLambdaFunction は SDK の FunctionConfiguration を RawLambdaFunction に変換する（Tags / ImageURI は呼び出し側で設定する）。
+func rawLambdaFunction(f *lambdatypes.FunctionConfiguration, vpcID string) terraform.RawLambdaFunction {
+	raw := terraform.RawLambdaFunction{
+		Name:        awssdk.ToString(f.FunctionName),
+		Arn:         awssdk.ToString(f.FunctionArn),
+		VpcID:       vpcID,
+		Description: awssdk.ToString(f.Description),
+		Role:        awssdk.ToString(f.Role),
+		PackageType: string(f.PackageType),
+		Runtime:     string(f.Runtime),
+		Handler:     awssdk.ToString(f.Handler),
+		MemorySize:  awssdk.ToInt32(f.MemorySize),
+		Timeout:     awssdk.ToInt32(f.Timeout),
+		KmsKeyArn:   awssdk.ToString(f.KMSKeyArn),
+	}
+	for _, a := range f.Architectures {
+		raw.Architectures = append(raw.Architectures, string(a))
+	}
+	for _, l := range f.Layers {
+		raw.Layers = append(raw.Layers, awssdk.ToString(l.Arn))
+	}
+	if f.Environment != nil {
+		raw.Environment = f.Environment.Variables
+	}
+	// 既定の PassThrough は出力しない
+	if f.TracingConfig != nil && f.TracingConfig.Mode == lambdatypes.TracingModeActive {
+		raw.TracingMode = string(f.TracingConfig.Mode)
+	}
+	if f.EphemeralStorage != nil {
+		raw.EphemeralStorageSize = awssdk.ToInt32(f.EphemeralStorage.Size)
+	}
+	if f.DeadLetterConfig != nil {
+		raw.DeadLetterTargetArn = awssdk.ToString(f.DeadLetterConfig.TargetArn)
+	}
+	if f.LoggingConfig != nil {
+		raw.LogGroup = awssdk.ToString(f.LoggingConfig.LogGroup)
+		raw.LogFormat = string(f.LoggingConfig.LogFormat)
+	}
+	if f.VpcConfig != nil {
+		raw.SubnetIDs = append(raw.SubnetIDs, f.VpcConfig.SubnetIds...)
+		raw.SecurityGroupIDs = append(raw.SecurityGroupIDs, f.VpcConfig.SecurityGroupIds...)
+		sort.Strings(raw.SubnetIDs)
+		sort.Strings(raw.SecurityGroupIDs)
+	}
+	return raw
+}
@@ -0,0 +1,259 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// ResourceLister は 1 種類（または関連する数種類）の AWS リソースを列挙するコンポーネント。
+// ListerRegistry に登録すると、awsVpcDiscoveryService.ListResources から依存関係順に呼び出される。
+// 新しいリソースタイプは、AwsVpcDiscoveryService を変更せずに別ファイル・別パッケージから追加できる。
+type ResourceLister interface {
+	// TypeName は lister の識別名。主に出力するリソースタイプ名 (例: "aws_subnet") とする。
+	TypeName() string
+	// Dependencies は先に完了している必要がある lister の TypeName 一覧。
+	// 依存先の結果は UpstreamResources で参照できる。
+	Dependencies() []string
+	// List はスコープ（単一 VPC / リージョン）内のリソースを列挙する。
+	List(ctx context.Context, scope terraform.DiscoveryScope) ([]terraform.Resource, []terraform.Relation, error)
+}
+
+// ResourceTypesLister は TypeName 以外のリソースタイプも出力する ResourceLister が実装する。
+// --resource-filters の type= で lister をスキップするかの判定に利用する。
+type ResourceTypesLister interface {
+	ResourceTypes() []string
+}
+
+// ListerResourceTypes は l が出力するリソースタイプ一覧を返す。
+func ListerResourceTypes(l ResourceLister) []string {
+	if tl, ok := l.(ResourceTypesLister); ok {
+		return tl.ResourceTypes()
+	}
+	return []string{l.TypeName()}
+}
+
+// vpcListerName は VPC 存在確認を行う lister の TypeName。
+// この lister の失敗はディスカバリ全体の致命的エラーとして扱う。
+const vpcListerName = "aws_vpc"
+
+// funcLister は VPC ID を受け取る ListXXX 関数を ResourceLister に適合させるアダプタ。
+type funcLister struct {
+	name  string
+	types []string
+	deps  []string
+	fn    listerFunc
+}
+
+// NewFuncLister は VPC ID を受け取る列挙関数から ResourceLister を生成する。
+func NewFuncLister(typeName string, dependencies []string, fn func(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)) ResourceLister {
+	return &funcLister{name: typeName, deps: dependencies, fn: fn}
+}
+
+// NewFuncListerWithTypes は NewFuncLister と同じだが、TypeName 以外に出力するリソースタイプ
+// （resourceTypes）を ResourceTypesLister として公開する。
+func NewFuncListerWithTypes(typeName string, resourceTypes []string, dependencies []string, fn func(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)) ResourceLister {
+	return &funcLister{name: typeName, types: resourceTypes, deps: dependencies, fn: fn}
+}
+
+func (l *funcLister) TypeName() string       { return l.name }
+func (l *funcLister) Dependencies() []string { return l.deps }
+
+func (l *funcLister) ResourceTypes() []string {
+	if len(l.types) == 0 {
+		return []string{l.name}
+	}
+	return l.types
+}
+
+func (l *funcLister) List(ctx context.Context, scope terraform.DiscoveryScope) ([]terraform.Resource, []terraform.Relation, error) {
+	return l.fn(withScopeRegion(ctx, scope.Region), scope.VpcID)
+}
+
+// ErrClientNotConfigured は lister が利用するサービスクライアントが設定されていないことを表す。
+var ErrClientNotConfigured = errors.New("AWS client is not configured")
+
+// unconfiguredLister はサービスクライアントが設定されていない lister の代わりに登録する ResourceLister。
+// 列挙は行わずに ErrClientNotConfigured を返す。結果は skipped として扱い、依存元の lister は実行する。
+// 依存先の結果は使わないため、依存関係は持たない（依存先だけのために lister を実行しない）。
+type unconfiguredLister struct {
+	ResourceLister
+	service string
+}
+
+func (l *unconfiguredLister) Dependencies() []string { return nil }
+
+func (l *unconfiguredLister) ResourceTypes() []string {
+	return ListerResourceTypes(l.ResourceLister)
+}
+
+func (l *unconfiguredLister) List(ctx context.Context, scope terraform.DiscoveryScope) ([]terraform.Resource, []terraform.Relation, error) {
+	return nil, nil, fmt.Errorf("%w: %s", ErrClientNotConfigured, l.service)
+}
+
+// ListerRegistry は ResourceLister の登録先。登録順が集約結果の順序になる。
+// 登録はディスカバリ開始前に行うこと（複数 goroutine からの同時登録は想定しない）。
+type ListerRegistry struct {
+	listers []ResourceLister
+	byName  map[string]int
+}
+
+// NewListerRegistry は空の ListerRegistry を生成する。
+func NewListerRegistry() *ListerRegistry {
+	return &ListerRegistry{byName: make(map[string]int)}
+}
+
+// Register は l を登録する。同じ TypeName の lister が登録済みの場合はエラーを返す。
+func (r *ListerRegistry) Register(l ResourceLister) error {
+	name := l.TypeName()
+	if name == "" {
+		return fmt.Errorf("lister type name is empty")
+	}
+	if _, ok := r.byName[name]; ok {
+		return fmt.Errorf("lister %s is already registered", name)
+	}
+	r.byName[name] = len(r.listers)
+	r.listers = append(r.listers, l)
+	return nil
+}
+
+// MustRegister は Register と同じだが、エラーの場合は panic する。
+func (r *ListerRegistry) MustRegister(l ResourceLister) {
+	if err := r.Register(l); err != nil {
+		panic(err)
+	}
+}
+
+// Listers は登録済みの lister を登録順に返す。
+func (r *ListerRegistry) Listers() []ResourceLister {
+	return append([]ResourceLister(nil), r.listers...)
+}
+
+// Plan は filters で除外されない lister と、その依存先の lister を登録順に返す。
+// 除外した lister の TypeName も返す。未登録の依存先・循環依存がある場合はエラーを返す。
+func (r *ListerRegistry) Plan(filters []terraform.ResourceFilter) ([]ResourceLister, []string, error) {
+	if err := r.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	needed := make(map[string]bool)
+	var mark func(name string)
+	mark = func(name string) {
+		if needed[name] {
+			return
+		}
+		needed[name] = true
+		for _, dep := range r.listers[r.byName[name]].Dependencies() {
+			mark(dep)
+		}
+	}
+	for _, l := range r.listers {
+		if l.TypeName() == vpcListerName || listerMatchesFilters(l, filters) {
+			mark(l.TypeName())
+		}
+	}
+
+	var planned []ResourceLister
+	var excluded []string
+	for _, l := range r.listers {
+		if needed[l.TypeName()] {
+			planned = append(planned, l)
+		} else {
+			excluded = append(excluded, l.TypeName())
+		}
+	}
+	return planned, excluded, nil
+}
+
+// validate は依存先がすべて登録済みで、循環依存がないことを確認する。
+func (r *ListerRegistry) validate() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("circular lister dependency: %s", strings.Join(append(path, name), " -> "))
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, dep := range r.listers[r.byName[name]].Dependencies() {
+			if _, ok := r.byName[dep]; !ok {
+				return fmt.Errorf("lister %s depends on unregistered lister %s", name, dep)
+			}
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+
+	names := make([]string, 0, len(r.byName))
+	for name := range r.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listerMatchesFilters は l の出力するリソースタイプのいずれかが filters の type 条件に合うかを判定する。
+// type を指定しないフィルタ（タグのみ等）はすべての lister にマッチする。
+func listerMatchesFilters(l ResourceLister, filters []terraform.ResourceFilter) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, f := range filters {
+		if f.Type == "" {
+			return true
+		}
+		for _, t := range ListerResourceTypes(l) {
+			if t == f.Type {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type upstreamKey struct{}
+
+// withUpstream は依存先 lister の列挙結果を ctx に設定する。
+func withUpstream(ctx context.Context, upstream map[string][]terraform.Resource) context.Context {
+	return context.WithValue(ctx, upstreamKey{}, upstream)
+}
+
+// UpstreamResources は ResourceLister.List の中で、依存先 lister（typeName）の列挙結果を返す。
+// Dependencies に含まれない lister や、失敗した lister の結果は返さない。
+func UpstreamResources(ctx context.Context, typeName string) []terraform.Resource {
+	upstream, _ := ctx.Value(upstreamKey{}).(map[string][]terraform.Resource)
+	return upstream[typeName]
+}
+
+type scopeRegionKey struct{}
+
+// withScopeRegion は列挙中のスコープのリージョンを ctx に設定する。
+func withScopeRegion(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, scopeRegionKey{}, region)
+}
+
+// ScopeRegion は ResourceLister.List の中で、列挙中のスコープのリージョンを返す。
+// 1 つの AwsVpcDiscoveryService で複数のスコープを同時に列挙できるよう、リージョンはサービスではなく ctx で受け渡す。
+func ScopeRegion(ctx context.Context) string {
+	region, _ := ctx.Value(scopeRegionKey{}).(string)
+	return region
+}
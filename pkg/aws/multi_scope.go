@@ -0,0 +1,186 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// RegionalDiscovery は 1 リージョン分の列挙に利用するクライアントの組。
+type RegionalDiscovery struct {
+	// Discovery は単一 VPC を列挙する CloudDiscovery（awsVpcDiscoveryService やキャッシュ付きのもの）。
+	Discovery CloudDiscovery
+	// Ec2 は対象 VPC の解決（--all-vpcs や複数リージョン指定時）に利用する。
+	Ec2 Ec2API
+}
+
+// RegionalDiscoveryFactory はリージョンごとの RegionalDiscovery を生成する。
+type RegionalDiscoveryFactory func(ctx context.Context, region string) (RegionalDiscovery, error)
+
+// MultiScopeDiscovery は複数 VPC / 複数リージョンのスコープを
+// (リージョン, VPC) の組ごとに分割して列挙し、結果を集約する CloudDiscovery 実装。
+//
+// 複数リージョンを対象にする場合は、Resource.ID / Name をリージョンで修飾して衝突を避ける
+// （terraform.QualifyByRegion を参照）。
+type MultiScopeDiscovery struct {
+	factory RegionalDiscoveryFactory
+	logger  Logger
+}
+
+// NewMultiScopeDiscovery は MultiScopeDiscovery を生成する。
+func NewMultiScopeDiscovery(factory RegionalDiscoveryFactory, logger Logger) *MultiScopeDiscovery {
+	return &MultiScopeDiscovery{factory: factory, logger: logger}
+}
+
+// ListResources は scope に含まれる (リージョン, VPC) の組ごとに列挙を行う。
+//
+// 1 つの VPC の列挙に失敗しても他の VPC の列挙は継続し、失敗は DiscoveryReport に記録する。
+// ctx のキャンセル、対象 VPC の解決失敗、およびすべての VPC の列挙に失敗した場合は致命的エラーとする。
+func (d *MultiScopeDiscovery) ListResources(ctx context.Context, scope terraform.DiscoveryScope) ([]terraform.Resource, []terraform.Relation, terraform.DiscoveryReport, error) {
+	var report terraform.DiscoveryReport
+
+	regions := scope.RegionList()
+	if len(regions) == 0 {
+		return nil, nil, report, fmt.Errorf("no region in discovery scope")
+	}
+	requested := scope.VpcIDList()
+	if !scope.AllVpcs && len(requested) == 0 {
+		return nil, nil, report, fmt.Errorf("no VPC in discovery scope")
+	}
+
+	var allResources []terraform.Resource
+	var allRelations []terraform.Relation
+	seen := make(map[string]bool) // 集約済みの Resource.ID
+	found := make(map[string]bool)
+	targets, failed := 0, 0
+	var lastErr error
+
+	for _, region := range regions {
+		rd, err := d.factory(ctx, region)
+		if err != nil {
+			return nil, nil, report, fmt.Errorf("failed to initialize discovery for region %s: %w", region, err)
+		}
+
+		vpcIDs := requested
+		// 単一リージョンで VPC を明示した場合は、各 VPC の存在確認を discoverVpc に任せる
+		if scope.AllVpcs || len(regions) > 1 {
+			vpcIDs, err = resolveVpcIDs(ctx, rd.Ec2, scope.AllVpcs, requested)
+			if err != nil {
+				return nil, nil, report, fmt.Errorf("failed to resolve VPCs in region %s: %w", region, err)
+			}
+			d.logger.Infof("Resolved %d VPC(s) in region %s", len(vpcIDs), region)
+		}
+
+		for _, vpcID := range vpcIDs {
+			found[vpcID] = true
+			targets++
+
+			sub := terraform.DiscoveryScope{
+				VpcID:           vpcID,
+				Region:          region,
+				Profile:         scope.Profile,
+				AssumeRoles:     scope.AssumeRoles,
+				ResourceFilters: scope.ResourceFilters,
+			}
+			rs, rels, subReport, err := rd.Discovery.ListResources(ctx, sub)
+			for _, lr := range subReport.Listers {
+				lr.Region = region
+				lr.VpcID = vpcID
+				report.Listers = append(report.Listers, lr)
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, nil, report, ctxErr
+			}
+			if err != nil {
+				d.logger.Warnf("failed to discover VPC %s in region %s, its resources will not be imported: %v", vpcID, region, err)
+				failed++
+				lastErr = err
+				continue
+			}
+			if len(regions) > 1 {
+				rs, rels = terraform.QualifyByRegion(region, rs, rels)
+			}
+			allResources = appendNewResources(allResources, seen, rs)
+			allRelations = append(allRelations, rels...)
+		}
+	}
+
+	if !scope.AllVpcs {
+		var missing []string
+		for _, id := range requested {
+			if !found[id] {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, nil, report, fmt.Errorf("vpc %s not found in regions %s", strings.Join(missing, ", "), strings.Join(regions, ", "))
+		}
+	}
+	if targets > 0 && failed == targets {
+		if targets == 1 {
+			return nil, nil, report, lastErr
+		}
+		return nil, nil, report, fmt.Errorf("discovery failed for all %d VPC(s): %w", targets, lastErr)
+	}
+
+	terraform.EnsureUniqueNames(allResources)
+
+	return allResources, allRelations, report, nil
+}
+
+// appendNewResources は rs のうち、ID がまだ集約されていない Resource のみを dst に追加する。
+// 複数 VPC から同じリソース（共有 Transit Gateway の data ソースなど）が列挙された場合は先に現れたものを残す。
+func appendNewResources(dst []terraform.Resource, seen map[string]bool, rs []terraform.Resource) []terraform.Resource {
+	for _, r := range rs {
+		if seen[r.ID] {
+			continue
+		}
+		seen[r.ID] = true
+		dst = append(dst, r)
+	}
+	return dst
+}
+
+// resolveVpcIDs はリージョン内の対象 VPC ID を返す。
+// all が true の場合はリージョン内のすべての VPC、そうでなければ requested のうち存在するものを返す。
+// DescribeVpcs の VpcIds は存在しない ID を含むとエラーになるため、vpc-id フィルタで絞り込む。
+func resolveVpcIDs(ctx context.Context, client Ec2API, all bool, requested []string) ([]string, error) {
+	input := &ec2.DescribeVpcsInput{MaxResults: awssdk.Int32(ec2MaxResults)}
+	if !all {
+		input.Filters = ec2Filter("vpc-id", requested...)
+	}
+
+	exists := make(map[string]bool)
+	var ids []string
+	p := ec2.NewDescribeVpcsPaginator(client, input)
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeVpcs: %w", err)
+		}
+		for _, v := range page.Vpcs {
+			id := awssdk.ToString(v.VpcId)
+			exists[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	// 出力順を安定させるため、明示指定時は指定順、--all-vpcs 時は ID 順に並べる
+	if all {
+		sort.Strings(ids)
+		return ids, nil
+	}
+	var ordered []string
+	for _, id := range requested {
+		if exists[id] {
+			ordered = append(ordered, id)
+		}
+	}
+	return ordered, nil
+}
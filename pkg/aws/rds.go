@@ -0,0 +1,431 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// rdsExcludedEnginePrefixes は RDS API で列挙されるエンジンのうち、RDS として扱わないもの（DocumentDB / Neptune）を除外するための接頭辞。
+var rdsExcludedEnginePrefixes = []string{"docdb", "neptune"}
+
+// ListRdsInstances は VPC 内の DB サブネットグループ・DB インスタンス・Aurora クラスターと、
+// それらが参照するパラメータグループ・オプショングループを列挙する。
+// デフォルトのパラメータグループ / オプショングループ（"default." / "default:" で始まるもの）は出力しない。
+func (s *awsVpcDiscoveryService) ListRdsInstances(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error) {
+	subnetGroups, err := s.describeVpcDbSubnetGroups(ctx, vpcID)
+	if err != nil {
+		return nil, nil, err
+	}
+	inVpc := make(map[string]bool, len(subnetGroups))
+	for _, g := range subnetGroups {
+		inVpc[g.Name] = true
+	}
+
+	instances, err := s.describeVpcDbInstances(ctx, vpcID)
+	if err != nil {
+		return nil, nil, err
+	}
+	clusters, err := s.describeVpcRdsClusters(ctx, vpcID, inVpc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var paramGroupNames, clusterParamGroupNames, optionGroupNames []string
+	for _, inst := range instances {
+		paramGroupNames = appendUniqueName(paramGroupNames, inst.ParameterGroupName)
+		optionGroupNames = appendUniqueName(optionGroupNames, inst.OptionGroupName)
+	}
+	for _, c := range clusters {
+		clusterParamGroupNames = appendUniqueName(clusterParamGroupNames, c.ParameterGroupName)
+	}
+
+	paramGroups, err := s.describeDbParameterGroups(ctx, paramGroupNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	clusterParamGroups, err := s.describeDbClusterParameterGroups(ctx, clusterParamGroupNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	optionGroups, err := s.describeDbOptionGroups(ctx, optionGroupNames)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	region := ScopeRegion(ctx)
+	resources, relations, err := s.mapper.MapDbSubnetGroup(subnetGroups, region)
+	if err != nil {
+		return nil, nil, err
+	}
+	paramResources, _, err := s.mapper.MapDbParameterGroup(append(paramGroups, clusterParamGroups...), region, vpcID)
+	if err != nil {
+		return nil, nil, err
+	}
+	optionResources, optionRelations, err := s.mapper.MapDbOptionGroup(optionGroups, region, vpcID)
+	if err != nil {
+		return nil, nil, err
+	}
+	clusterResources, clusterRelations, err := s.mapper.MapRdsCluster(clusters, region)
+	if err != nil {
+		return nil, nil, err
+	}
+	instanceResources, instanceRelations, err := s.mapper.MapDbInstance(instances, region)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resources = append(append(append(append(resources, paramResources...), optionResources...), clusterResources...), instanceResources...)
+	relations = append(append(append(relations, optionRelations...), clusterRelations...), instanceRelations...)
+	return resources, relations, nil
+}
+
+// describeVpcDbSubnetGroups は DescribeDBSubnetGroups で VPC 内の DB サブネットグループを取得する（API に VPC フィルタはない）。
+func (s *awsVpcDiscoveryService) describeVpcDbSubnetGroups(ctx context.Context, vpcID string) ([]terraform.RawDbSubnetGroup, error) {
+	var raws []terraform.RawDbSubnetGroup
+
+	p := rds.NewDescribeDBSubnetGroupsPaginator(s.rds, &rds.DescribeDBSubnetGroupsInput{})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeDBSubnetGroups: %w", err)
+		}
+		for _, g := range page.DBSubnetGroups {
+			if awssdk.ToString(g.VpcId) != vpcID {
+				continue
+			}
+			raw := rawDbSubnetGroup(g)
+			tags, err := s.listRdsTags(ctx, awssdk.ToString(g.DBSubnetGroupArn))
+			if err != nil {
+				return nil, err
+			}
+			raw.Tags = tags
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// rawDbSubnetGroup は SDK の DBSubnetGroup を RawDbSubnetGroup に変換する。
+func rawDbSubnetGroup(g rdstypes.DBSubnetGroup) terraform.RawDbSubnetGroup {
+	raw := terraform.RawDbSubnetGroup{
+		Name:        awssdk.ToString(g.DBSubnetGroupName),
+		Description: awssdk.ToString(g.DBSubnetGroupDescription),
+		VpcID:       awssdk.ToString(g.VpcId),
+	}
+	for _, sn := range g.Subnets {
+		raw.SubnetIDs = append(raw.SubnetIDs, awssdk.ToString(sn.SubnetIdentifier))
+	}
+	sort.Strings(raw.SubnetIDs)
+	return raw
+}
+
+// describeVpcDbInstances は DescribeDBInstances で VPC 内の DB インスタンス（Aurora クラスターのメンバーを含む）を取得する。
+func (s *awsVpcDiscoveryService) describeVpcDbInstances(ctx context.Context, vpcID string) ([]terraform.RawDbInstance, error) {
+	var raws []terraform.RawDbInstance
+
+	p := rds.NewDescribeDBInstancesPaginator(s.rds, &rds.DescribeDBInstancesInput{})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeDBInstances: %w", err)
+		}
+		for _, inst := range page.DBInstances {
+			if inst.DBSubnetGroup == nil || awssdk.ToString(inst.DBSubnetGroup.VpcId) != vpcID {
+				continue
+			}
+			if isExcludedRdsEngine(awssdk.ToString(inst.Engine)) {
+				continue
+			}
+			raw := terraform.RawDbInstance{
+				Identifier:                   awssdk.ToString(inst.DBInstanceIdentifier),
+				ClusterIdentifier:            awssdk.ToString(inst.DBClusterIdentifier),
+				VpcID:                        vpcID,
+				Engine:                       awssdk.ToString(inst.Engine),
+				EngineVersion:                awssdk.ToString(inst.EngineVersion),
+				InstanceClass:                awssdk.ToString(inst.DBInstanceClass),
+				AllocatedStorage:             awssdk.ToInt32(inst.AllocatedStorage),
+				MaxAllocatedStorage:          awssdk.ToInt32(inst.MaxAllocatedStorage),
+				StorageType:                  awssdk.ToString(inst.StorageType),
+				Iops:                         awssdk.ToInt32(inst.Iops),
+				DBName:                       awssdk.ToString(inst.DBName),
+				MasterUsername:               awssdk.ToString(inst.MasterUsername),
+				ManageMasterUserPassword:     inst.MasterUserSecret != nil,
+				ReplicateSourceDB:            awssdk.ToString(inst.ReadReplicaSourceDBInstanceIdentifier),
+				SubnetGroupName:              awssdk.ToString(inst.DBSubnetGroup.DBSubnetGroupName),
+				SecurityGroupIDs:             rdsSecurityGroupIDs(inst.VpcSecurityGroups),
+				AvailabilityZone:             awssdk.ToString(inst.AvailabilityZone),
+				MultiAZ:                      awssdk.ToBool(inst.MultiAZ),
+				PubliclyAccessible:           awssdk.ToBool(inst.PubliclyAccessible),
+				Port:                         awssdk.ToInt32(inst.DbInstancePort),
+				StorageEncrypted:             awssdk.ToBool(inst.StorageEncrypted),
+				KmsKeyID:                     awssdk.ToString(inst.KmsKeyId),
+				BackupRetentionPeriod:        awssdk.ToInt32(inst.BackupRetentionPeriod),
+				PreferredBackupWindow:        awssdk.ToString(inst.PreferredBackupWindow),
+				PreferredMaintenanceWindow:   awssdk.ToString(inst.PreferredMaintenanceWindow),
+				DeletionProtection:           awssdk.ToBool(inst.DeletionProtection),
+				EnabledCloudwatchLogsExports: inst.EnabledCloudwatchLogsExports,
+				Tags:                         rdsTagsToMap(inst.TagList),
+			}
+			// DbInstancePort は 0 の場合があるため、エンドポイントのポートで補う
+			if raw.Port == 0 && inst.Endpoint != nil {
+				raw.Port = awssdk.ToInt32(inst.Endpoint.Port)
+			}
+			if len(inst.DBParameterGroups) > 0 {
+				raw.ParameterGroupName = awssdk.ToString(inst.DBParameterGroups[0].DBParameterGroupName)
+			}
+			if len(inst.OptionGroupMemberships) > 0 {
+				raw.OptionGroupName = awssdk.ToString(inst.OptionGroupMemberships[0].OptionGroupName)
+			}
+			// 既定のグループは Terraform 側で指定しない（省略時と同じ）ため、属性にも含めない
+			if isDefaultGroupName(raw.ParameterGroupName) {
+				raw.ParameterGroupName = ""
+			}
+			if isDefaultGroupName(raw.OptionGroupName) {
+				raw.OptionGroupName = ""
+			}
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// describeVpcRdsClusters は DescribeDBClusters で、subnetGroups に含まれる DB サブネットグループを使うクラスターを取得する。
+func (s *awsVpcDiscoveryService) describeVpcRdsClusters(ctx context.Context, vpcID string, subnetGroups map[string]bool) ([]terraform.RawRdsCluster, error) {
+	var raws []terraform.RawRdsCluster
+
+	p := rds.NewDescribeDBClustersPaginator(s.rds, &rds.DescribeDBClustersInput{})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeDBClusters: %w", err)
+		}
+		for _, c := range page.DBClusters {
+			if !subnetGroups[awssdk.ToString(c.DBSubnetGroup)] || isExcludedRdsEngine(awssdk.ToString(c.Engine)) {
+				continue
+			}
+			raw := terraform.RawRdsCluster{
+				Identifier:                   awssdk.ToString(c.DBClusterIdentifier),
+				VpcID:                        vpcID,
+				Engine:                       awssdk.ToString(c.Engine),
+				EngineVersion:                awssdk.ToString(c.EngineVersion),
+				EngineMode:                   awssdk.ToString(c.EngineMode),
+				DatabaseName:                 awssdk.ToString(c.DatabaseName),
+				MasterUsername:               awssdk.ToString(c.MasterUsername),
+				ManageMasterUserPassword:     c.MasterUserSecret != nil,
+				ReplicationSourceIdentifier:  awssdk.ToString(c.ReplicationSourceIdentifier),
+				SubnetGroupName:              awssdk.ToString(c.DBSubnetGroup),
+				ParameterGroupName:           awssdk.ToString(c.DBClusterParameterGroup),
+				SecurityGroupIDs:             rdsSecurityGroupIDs(c.VpcSecurityGroups),
+				Port:                         awssdk.ToInt32(c.Port),
+				StorageEncrypted:             awssdk.ToBool(c.StorageEncrypted),
+				KmsKeyID:                     awssdk.ToString(c.KmsKeyId),
+				BackupRetentionPeriod:        awssdk.ToInt32(c.BackupRetentionPeriod),
+				PreferredBackupWindow:        awssdk.ToString(c.PreferredBackupWindow),
+				PreferredMaintenanceWindow:   awssdk.ToString(c.PreferredMaintenanceWindow),
+				DeletionProtection:           awssdk.ToBool(c.DeletionProtection),
+				EnabledCloudwatchLogsExports: c.EnabledCloudwatchLogsExports,
+				Tags:                         rdsTagsToMap(c.TagList),
+			}
+			if isDefaultGroupName(raw.ParameterGroupName) {
+				raw.ParameterGroupName = ""
+			}
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// describeDbParameterGroups は names の DB パラメータグループと、ユーザーが変更したパラメータを取得する。
+func (s *awsVpcDiscoveryService) describeDbParameterGroups(ctx context.Context, names []string) ([]terraform.RawDbParameterGroup, error) {
+	var raws []terraform.RawDbParameterGroup
+	for _, name := range names {
+		out, err := s.rds.DescribeDBParameterGroups(ctx, &rds.DescribeDBParameterGroupsInput{DBParameterGroupName: awssdk.String(name)})
+		if err != nil {
+			return nil, fmt.Errorf("DescribeDBParameterGroups(%s): %w", name, err)
+		}
+		for _, g := range out.DBParameterGroups {
+			raw := terraform.RawDbParameterGroup{
+				Name:        awssdk.ToString(g.DBParameterGroupName),
+				Family:      awssdk.ToString(g.DBParameterGroupFamily),
+				Description: awssdk.ToString(g.Description),
+			}
+			tags, err := s.listRdsTags(ctx, awssdk.ToString(g.DBParameterGroupArn))
+			if err != nil {
+				return nil, err
+			}
+			raw.Tags = tags
+			p := rds.NewDescribeDBParametersPaginator(s.rds, &rds.DescribeDBParametersInput{
+				DBParameterGroupName: g.DBParameterGroupName,
+				Source:               awssdk.String("user"),
+			})
+			for p.HasMorePages() {
+				page, err := p.NextPage(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("DescribeDBParameters(%s): %w", name, err)
+				}
+				raw.Parameters = append(raw.Parameters, rawDbParameters(page.Parameters)...)
+			}
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// describeDbClusterParameterGroups は names の DB クラスターパラメータグループと、ユーザーが変更したパラメータを取得する。
+func (s *awsVpcDiscoveryService) describeDbClusterParameterGroups(ctx context.Context, names []string) ([]terraform.RawDbParameterGroup, error) {
+	var raws []terraform.RawDbParameterGroup
+	for _, name := range names {
+		out, err := s.rds.DescribeDBClusterParameterGroups(ctx, &rds.DescribeDBClusterParameterGroupsInput{DBClusterParameterGroupName: awssdk.String(name)})
+		if err != nil {
+			return nil, fmt.Errorf("DescribeDBClusterParameterGroups(%s): %w", name, err)
+		}
+		for _, g := range out.DBClusterParameterGroups {
+			raw := terraform.RawDbParameterGroup{
+				Name:        awssdk.ToString(g.DBClusterParameterGroupName),
+				Family:      awssdk.ToString(g.DBParameterGroupFamily),
+				Description: awssdk.ToString(g.Description),
+				Cluster:     true,
+			}
+			tags, err := s.listRdsTags(ctx, awssdk.ToString(g.DBClusterParameterGroupArn))
+			if err != nil {
+				return nil, err
+			}
+			raw.Tags = tags
+			p := rds.NewDescribeDBClusterParametersPaginator(s.rds, &rds.DescribeDBClusterParametersInput{
+				DBClusterParameterGroupName: g.DBClusterParameterGroupName,
+				Source:                      awssdk.String("user"),
+			})
+			for p.HasMorePages() {
+				page, err := p.NextPage(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("DescribeDBClusterParameters(%s): %w", name, err)
+				}
+				raw.Parameters = append(raw.Parameters, rawDbParameters(page.Parameters)...)
+			}
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// rawDbParameters は SDK の Parameter 一覧を RawDbParameter に変換する。
+func rawDbParameters(params []rdstypes.Parameter) []terraform.RawDbParameter {
+	raws := make([]terraform.RawDbParameter, 0, len(params))
+	for _, p := range params {
+		raws = append(raws, terraform.RawDbParameter{
+			Name:        awssdk.ToString(p.ParameterName),
+			Value:       awssdk.ToString(p.ParameterValue),
+			ApplyMethod: string(p.ApplyMethod),
+		})
+	}
+	return raws
+}
+
+// describeDbOptionGroups は names の DB オプショングループを取得する。
+// オプション設定はデフォルト値から変更されたもののみを含める。
+func (s *awsVpcDiscoveryService) describeDbOptionGroups(ctx context.Context, names []string) ([]terraform.RawDbOptionGroup, error) {
+	var raws []terraform.RawDbOptionGroup
+	for _, name := range names {
+		out, err := s.rds.DescribeOptionGroups(ctx, &rds.DescribeOptionGroupsInput{OptionGroupName: awssdk.String(name)})
+		if err != nil {
+			return nil, fmt.Errorf("DescribeOptionGroups(%s): %w", name, err)
+		}
+		for _, g := range out.OptionGroupsList {
+			raw := terraform.RawDbOptionGroup{
+				Name:               awssdk.ToString(g.OptionGroupName),
+				EngineName:         awssdk.ToString(g.EngineName),
+				MajorEngineVersion: awssdk.ToString(g.MajorEngineVersion),
+				Description:        awssdk.ToString(g.OptionGroupDescription),
+			}
+			tags, err := s.listRdsTags(ctx, awssdk.ToString(g.OptionGroupArn))
+			if err != nil {
+				return nil, err
+			}
+			raw.Tags = tags
+			for _, o := range g.Options {
+				opt := terraform.RawDbOption{
+					Name:             awssdk.ToString(o.OptionName),
+					Version:          awssdk.ToString(o.OptionVersion),
+					Port:             awssdk.ToInt32(o.Port),
+					SecurityGroupIDs: rdsSecurityGroupIDs(o.VpcSecurityGroupMemberships),
+					Settings:         map[string]string{},
+				}
+				for _, st := range o.OptionSettings {
+					v := awssdk.ToString(st.Value)
+					if v == "" || v == awssdk.ToString(st.DefaultValue) {
+						continue
+					}
+					opt.Settings[awssdk.ToString(st.Name)] = v
+				}
+				raw.Options = append(raw.Options, opt)
+			}
+			raws = append(raws, raw)
+		}
+	}
+	return raws, nil
+}
+
+// rdsSecurityGroupIDs は VPC セキュリティグループのメンバーシップからセキュリティグループ ID を返す。
+func rdsSecurityGroupIDs(memberships []rdstypes.VpcSecurityGroupMembership) []string {
+	var ids []string
+	for _, m := range memberships {
+		ids = append(ids, awssdk.ToString(m.VpcSecurityGroupId))
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// listRdsTags は ListTagsForResource で arn のタグを map で返す。
+// サブネット / パラメータ / オプショングループの Describe API はタグを返さないため、個別に取得する。
+func (s *awsVpcDiscoveryService) listRdsTags(ctx context.Context, arn string) (map[string]string, error) {
+	out, err := s.rds.ListTagsForResource(ctx, &rds.ListTagsForResourceInput{ResourceName: awssdk.String(arn)})
+	if err != nil {
+		return nil, fmt.Errorf("ListTagsForResource(%s): %w", arn, err)
+	}
+	return rdsTagsToMap(out.TagList), nil
+}
+
+// rdsTagsToMap は RDS のタグ一覧を map に変換する。
+func rdsTagsToMap(tags []rdstypes.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, t := range tags {
+		m[awssdk.ToString(t.Key)] = awssdk.ToString(t.Value)
+	}
+	return m
+}
+
+// isDefaultGroupName は AWS が用意する既定のパラメータグループ / オプショングループ（RDS / ElastiCache）かどうかを判定する。
+func isDefaultGroupName(name string) bool {
+	return strings.HasPrefix(name, "default.") || strings.HasPrefix(name, "default:")
+}
+
+// isExcludedRdsEngine は RDS として扱わないエンジン（DocumentDB / Neptune）かどうかを判定する。
+func isExcludedRdsEngine(engine string) bool {
+	for _, prefix := range rdsExcludedEnginePrefixes {
+		if strings.HasPrefix(engine, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendUniqueName は name が空でも既出でもない場合に names へ追加する。
+func appendUniqueName(names []string, name string) []string {
+	if name == "" {
+		return names
+	}
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
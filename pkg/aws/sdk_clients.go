@@ -0,0 +1,186 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/codebuild"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// SDK v2 のクライアントが各 API インターフェースを満たすことをコンパイル時に保証する。
+var (
+	_ Ec2API            = (*ec2.Client)(nil)
+	_ ElbAPI            = (*elbv2.Client)(nil)
+	_ AcmAPI            = (*acm.Client)(nil)
+	_ RdsAPI            = (*rds.Client)(nil)
+	_ ElastiCacheAPI    = (*elasticache.Client)(nil)
+	_ CodeBuildAPI      = (*codebuild.Client)(nil)
+	_ LambdaAPI         = (*lambda.Client)(nil)
+	_ EcsAPI            = (*ecs.Client)(nil)
+	_ IamAPI            = (*iam.Client)(nil)
+	_ KmsAPI            = (*kms.Client)(nil)
+	_ CloudWatchLogsAPI = (*cloudwatchlogs.Client)(nil)
+	_ CloudWatchAPI     = (*cloudwatch.Client)(nil)
+	_ StsAPI            = (*sts.Client)(nil)
+)
+
+// AwsClients は discovery で利用する AWS SDK v2 クライアントの束。
+type AwsClients struct {
+	Ec2            Ec2API
+	Elb            ElbAPI
+	Acm            AcmAPI
+	Rds            RdsAPI
+	ElastiCache    ElastiCacheAPI
+	Ecs            EcsAPI
+	Lambda         LambdaAPI
+	CodeBuild      CodeBuildAPI
+	Iam            IamAPI
+	Kms            KmsAPI
+	CloudWatchLogs CloudWatchLogsAPI
+	CloudWatch     CloudWatchAPI
+	Sts            StsAPI
+}
+
+// AwsClientConfig は NewAwsClients の設定。
+type AwsClientConfig struct {
+	Region string
+	// Profile が空の場合はデフォルトプロファイル（または環境変数）を利用する。
+	Profile string
+	// AssumeRoles は基底のクレデンシャルから順に引き受けるロールチェーン。空の場合は引き受けない。
+	AssumeRoles []terraform.AssumeRole
+	// EndpointURL は全サービス共通のエンドポイント URL（LocalStack / moto 等）。空の場合は AWS の標準エンドポイント。
+	EndpointURL string
+	// ServiceEndpoints はサービス名（"ec2", "sts" など）ごとのエンドポイント URL。EndpointURL より優先する。
+	ServiceEndpoints map[string]string
+	// StaticCredentials を指定した場合は標準のクレデンシャルプロバイダチェーンの代わりに利用する。
+	StaticCredentials *terraform.StaticCredentials
+	// Guard が nil でない場合は SDK 標準のリトライを無効にし、各クライアントを Guard でラップする。
+	Guard *APIGuard
+}
+
+// serviceEndpoint は service に対応するエンドポイントを上書きするクライアントオプションの値を返す。
+// 上書きしない場合は nil を返す（EndpointURL は awssdk.Config.BaseEndpoint で全サービスに適用される）。
+func (c AwsClientConfig) serviceEndpoint(service string) *string {
+	if u, ok := c.ServiceEndpoints[service]; ok && u != "" {
+		return awssdk.String(u)
+	}
+	return nil
+}
+
+// NewAwsClients は AWS SDK v2 の標準クレデンシャルプロバイダチェーン
+// （および cfg.AssumeRoles のロールチェーン）を用いて各サービスのクライアントを生成する。
+func NewAwsClients(ctx context.Context, cfg AwsClientConfig) (*AwsClients, error) {
+	awsCfg, err := loadAwsConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	guard := cfg.Guard
+
+	clients := &AwsClients{
+		Ec2: ec2.NewFromConfig(awsCfg, func(o *ec2.Options) {
+			if u := cfg.serviceEndpoint(serviceEc2); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+		Elb: elbv2.NewFromConfig(awsCfg, func(o *elbv2.Options) {
+			if u := cfg.serviceEndpoint(serviceElb); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+		Acm: acm.NewFromConfig(awsCfg, func(o *acm.Options) {
+			if u := cfg.serviceEndpoint(serviceAcm); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+		Rds: rds.NewFromConfig(awsCfg, func(o *rds.Options) {
+			if u := cfg.serviceEndpoint(serviceRds); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+		ElastiCache: elasticache.NewFromConfig(awsCfg, func(o *elasticache.Options) {
+			if u := cfg.serviceEndpoint(serviceElastiCache); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+		Ecs: ecs.NewFromConfig(awsCfg, func(o *ecs.Options) {
+			if u := cfg.serviceEndpoint(serviceEcs); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+		Lambda: lambda.NewFromConfig(awsCfg, func(o *lambda.Options) {
+			if u := cfg.serviceEndpoint(serviceLambda); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+		CodeBuild: codebuild.NewFromConfig(awsCfg, func(o *codebuild.Options) {
+			if u := cfg.serviceEndpoint(serviceCodeBuild); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+		Iam: iam.NewFromConfig(awsCfg, func(o *iam.Options) {
+			if u := cfg.serviceEndpoint(serviceIam); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+		Kms: kms.NewFromConfig(awsCfg, func(o *kms.Options) {
+			if u := cfg.serviceEndpoint(serviceKms); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+		CloudWatchLogs: cloudwatchlogs.NewFromConfig(awsCfg, func(o *cloudwatchlogs.Options) {
+			if u := cfg.serviceEndpoint(serviceCloudWatchLogs); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+		CloudWatch: cloudwatch.NewFromConfig(awsCfg, func(o *cloudwatch.Options) {
+			if u := cfg.serviceEndpoint(serviceCloudWatch); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+		Sts: sts.NewFromConfig(awsCfg, func(o *sts.Options) {
+			if u := cfg.serviceEndpoint(serviceSts); u != nil {
+				o.BaseEndpoint = u
+			}
+		}),
+	}
+	if guard != nil {
+		clients.Ec2 = NewGuardedEc2API(clients.Ec2, guard)
+		clients.Elb = NewGuardedElbAPI(clients.Elb, guard)
+		clients.Acm = NewGuardedAcmAPI(clients.Acm, guard)
+		clients.Rds = NewGuardedRdsAPI(clients.Rds, guard)
+		clients.ElastiCache = NewGuardedElastiCacheAPI(clients.ElastiCache, guard)
+		clients.Ecs = NewGuardedEcsAPI(clients.Ecs, guard)
+		clients.Lambda = NewGuardedLambdaAPI(clients.Lambda, guard)
+		clients.CodeBuild = NewGuardedCodeBuildAPI(clients.CodeBuild, guard)
+		clients.Iam = NewGuardedIamAPI(clients.Iam, guard)
+		clients.Kms = NewGuardedKmsAPI(clients.Kms, guard)
+		clients.CloudWatchLogs = NewGuardedCloudWatchLogsAPI(clients.CloudWatchLogs, guard)
+		clients.CloudWatch = NewGuardedCloudWatchAPI(clients.CloudWatch, guard)
+		clients.Sts = NewGuardedStsAPI(clients.Sts, guard)
+	}
+	return clients, nil
+}
+
+// AccountID は STS GetCallerIdentity で現在のクレデンシャルの AWS アカウント ID を返す。
+func (c *AwsClients) AccountID(ctx context.Context) (string, error) {
+	out, err := c.Sts.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	return awssdk.ToString(out.Account), nil
+}
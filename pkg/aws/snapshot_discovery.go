@@ -0,0 +1,63 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// SnapshotDiscovery は保存済みの DiscoverySnapshot を返す CloudDiscovery 実装。
+// AWS API を呼び出さないため、認証情報なしで HCL / import スクリプトを再生成できる。
+type SnapshotDiscovery struct {
+	snapshot terraform.DiscoverySnapshot
+	logger   Logger
+}
+
+// NewSnapshotDiscovery は snapshot を返す SnapshotDiscovery を生成する。
+func NewSnapshotDiscovery(snapshot terraform.DiscoverySnapshot, logger Logger) *SnapshotDiscovery {
+	return &SnapshotDiscovery{snapshot: snapshot, logger: logger}
+}
+
+// ListResources はスナップショットに記録されたリソース・関係・実行結果を返す。
+// scope の VPC ID / リージョンが指定されている場合は、スナップショットのものと（順序を問わず）一致するかを検証する。
+func (d *SnapshotDiscovery) ListResources(ctx context.Context, scope terraform.DiscoveryScope) ([]terraform.Resource, []terraform.Relation, terraform.DiscoveryReport, error) {
+	snap := d.snapshot
+	if err := ctx.Err(); err != nil {
+		return nil, nil, terraform.DiscoveryReport{}, err
+	}
+	if want := scope.VpcIDList(); len(want) > 0 && !sameStrings(want, snap.Scope.VpcIDList()) {
+		return nil, nil, terraform.DiscoveryReport{}, fmt.Errorf("snapshot is for VPC %s, not %s",
+			strings.Join(snap.Scope.VpcIDList(), ","), strings.Join(want, ","))
+	}
+	if want := scope.RegionList(); len(want) > 0 && !sameStrings(want, snap.Scope.RegionList()) {
+		return nil, nil, terraform.DiscoveryReport{}, fmt.Errorf("snapshot is for region %s, not %s",
+			strings.Join(snap.Scope.RegionList(), ","), strings.Join(want, ","))
+	}
+
+	d.logger.Infof("Loaded discovery snapshot: vpc=%s region=%s created=%s resources=%d relations=%d",
+		strings.Join(snap.Scope.VpcIDList(), ","), strings.Join(snap.Scope.RegionList(), ","), snap.Metadata.CreatedAt.Format(time.RFC3339),
+		len(snap.Resources), len(snap.Relations))
+
+	return snap.Resources, snap.Relations, snap.Report, nil
+}
+
+// sameStrings は a と b が順序を問わず同じ要素を持つかどうかを返す。
+func sameStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x := append([]string(nil), a...)
+	y := append([]string(nil), b...)
+	sort.Strings(x)
+	sort.Strings(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,310 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// ErrAPICallBudgetExceeded は APIGuard に設定した API 呼び出し上限に達したことを表す。
+var ErrAPICallBudgetExceeded = errors.New("AWS API call budget exceeded")
+
+// RetryPolicy は AWS API 呼び出しのリトライ方針（指数バックオフ + フルジッタ）。
+type RetryPolicy struct {
+	MaxAttempts int           // 初回を含む最大試行回数
+	BaseDelay   time.Duration // 1 回目のリトライ待ちの上限
+	MaxDelay    time.Duration // リトライ待ちの上限
+}
+
+// DefaultRetryPolicy は大規模アカウントでのスロットリングを想定したデフォルトのリトライ方針を返す。
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 8,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    20 * time.Second,
+	}
+}
+
+// backoff は attempt 回目（0 始まり）の失敗後に待つ時間を返す。
+// min(MaxDelay, BaseDelay * 2^attempt) を上限とする一様乱数（フルジッタ）。
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	d := p.BaseDelay
+	for i := 0; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return time.Duration(rand.Int64N(int64(d) + 1))
+}
+
+// TokenBucket はサービス単位の適応型トークンバケット。
+// スロットリングを受けるとレートを半減し、成功が続くと上限まで徐々に戻す（AIMD）。
+// rate が 0 以下の場合は流量制限を行わない。
+type TokenBucket struct {
+	mu      sync.Mutex
+	rate    float64 // 現在のレート（トークン / 秒）
+	maxRate float64
+	minRate float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+}
+
+// NewTokenBucket は ratePerSecond / burst を上限とする TokenBucket を生成する。
+func NewTokenBucket(ratePerSecond float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:    ratePerSecond,
+		maxRate: ratePerSecond,
+		minRate: ratePerSecond / 16,
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		last:    time.Now(),
+	}
+}
+
+// Wait はトークンを 1 つ取得できるまで待つ。ctx がキャンセルされた場合はそのエラーを返す。
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	d := b.reserve()
+	if d <= 0 {
+		return nil
+	}
+	return sleepContext(ctx, d)
+}
+
+// reserve はトークンを 1 つ予約し、利用可能になるまでの待ち時間を返す。
+func (b *TokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rate <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Rate は現在のレート（トークン / 秒）を返す。
+func (b *TokenBucket) Rate() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rate
+}
+
+// OnThrottle はスロットリングを受けた際にレートを半減する。
+func (b *TokenBucket) OnThrottle() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.rate <= 0 {
+		return
+	}
+	b.rate /= 2
+	if b.rate < b.minRate {
+		b.rate = b.minRate
+	}
+}
+
+// OnSuccess は呼び出し成功時にレートを上限まで少しずつ戻す。
+func (b *TokenBucket) OnSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.rate <= 0 || b.rate >= b.maxRate {
+		return
+	}
+	b.rate += b.maxRate / 20
+	if b.rate > b.maxRate {
+		b.rate = b.maxRate
+	}
+}
+
+// ThrottleConfig は APIGuard の設定。
+type ThrottleConfig struct {
+	// RatePerSecond はサービスごとの API 呼び出しレート上限。0 以下なら制限しない。
+	RatePerSecond float64
+	// Burst はサービスごとのバースト許容量。
+	Burst int
+	// MaxAPICalls は 1 回の実行で許可する API 呼び出し総数（リトライを含む）。0 以下なら無制限。
+	MaxAPICalls int64
+	Retry       RetryPolicy
+}
+
+// DefaultThrottleConfig はデフォルトの ThrottleConfig を返す。
+func DefaultThrottleConfig() ThrottleConfig {
+	return ThrottleConfig{
+		RatePerSecond: 10,
+		Burst:         10,
+		Retry:         DefaultRetryPolicy(),
+	}
+}
+
+// APIGuard は AWS API 呼び出しに対して、サービス単位のレート制限・
+// スロットリング / 一時エラー時のリトライ・呼び出し総数の上限を適用する。
+// 複数 goroutine から同時に利用してよい。
+type APIGuard struct {
+	cfg ThrottleConfig
+
+	mu       sync.Mutex
+	limiters map[string]*TokenBucket
+
+	calls     atomic.Int64
+	retries   atomic.Int64
+	throttled atomic.Int64
+}
+
+// NewAPIGuard は APIGuard を生成する。
+func NewAPIGuard(cfg ThrottleConfig) *APIGuard {
+	if cfg.Retry.MaxAttempts < 1 {
+		cfg.Retry.MaxAttempts = 1
+	}
+	return &APIGuard{
+		cfg:      cfg,
+		limiters: make(map[string]*TokenBucket),
+	}
+}
+
+// Limiter はサービス名（"ec2" など）に対応する TokenBucket を返す。
+func (g *APIGuard) Limiter(service string) *TokenBucket {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, ok := g.limiters[service]
+	if !ok {
+		b = NewTokenBucket(g.cfg.RatePerSecond, g.cfg.Burst)
+		g.limiters[service] = b
+	}
+	return b
+}
+
+// Calls / Retries / Throttled は APIGuard 経由の累計値を返す。
+func (g *APIGuard) Calls() int64     { return g.calls.Load() }
+func (g *APIGuard) Retries() int64   { return g.retries.Load() }
+func (g *APIGuard) Throttled() int64 { return g.throttled.Load() }
+
+// consumeBudget は API 呼び出し 1 回分の予算を消費する。
+func (g *APIGuard) consumeBudget() error {
+	n := g.calls.Add(1)
+	if g.cfg.MaxAPICalls > 0 && n > g.cfg.MaxAPICalls {
+		return ErrAPICallBudgetExceeded
+	}
+	return nil
+}
+
+var (
+	throttleChecks  = retry.IsErrorThrottles(retry.DefaultThrottles)
+	retryableChecks = retry.IsErrorRetryables(retry.DefaultRetryables)
+)
+
+// isThrottle は err が AWS API のスロットリングエラーかどうかを判定する。
+func isThrottle(err error) bool {
+	return throttleChecks.IsErrorThrottle(err) == awssdk.TrueTernary
+}
+
+// isRetryable は err が一時的なエラー（5xx、接続エラー等）でリトライ可能かを判定する。
+func isRetryable(err error) bool {
+	return retryableChecks.IsErrorRetryable(err) == awssdk.TrueTernary
+}
+
+// guardedCall は fn を APIGuard の制御下で呼び出す。
+// ctx に callStats が設定されていれば、lister 単位の呼び出し回数・リトライ回数も記録する。
+func guardedCall[T any](ctx context.Context, g *APIGuard, service string, fn func(context.Context) (T, error)) (T, error) {
+	var zero T
+	stats := callStatsFromContext(ctx)
+	limiter := g.Limiter(service)
+
+	for attempt := 0; ; attempt++ {
+		if err := g.consumeBudget(); err != nil {
+			return zero, err
+		}
+		if err := limiter.Wait(ctx); err != nil {
+			return zero, err
+		}
+		stats.calls.Add(1)
+
+		out, err := fn(ctx)
+		if err == nil {
+			limiter.OnSuccess()
+			return out, nil
+		}
+
+		throttled := isThrottle(err)
+		if throttled {
+			limiter.OnThrottle()
+			g.throttled.Add(1)
+			stats.throttled.Add(1)
+		}
+		if ctx.Err() != nil || attempt+1 >= g.cfg.Retry.MaxAttempts || !(throttled || isRetryable(err)) {
+			return zero, err
+		}
+
+		g.retries.Add(1)
+		stats.retries.Add(1)
+		if err := sleepContext(ctx, g.cfg.Retry.backoff(attempt)); err != nil {
+			return zero, err
+		}
+	}
+}
+
+// sleepContext は d だけ待つ。待機中に ctx がキャンセルされた場合はそのエラーを返す。
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// callStats は lister 単位の API 呼び出し統計。
+// runListers が lister ごとに ctx へ設定し、guardedCall（キャッシュヒットは NewCachedLister の cachedLister）が記録する。
+type callStats struct {
+	calls     atomic.Int64
+	retries   atomic.Int64
+	throttled atomic.Int64
+	cacheHit  atomic.Bool
+}
+
+type callStatsKey struct{}
+
+// discardStats は ctx に callStats が設定されていない場合の加算先。
+var discardStats = &callStats{}
+
+// withCallStats は新しい callStats を設定した ctx を返す。
+func withCallStats(ctx context.Context) (context.Context, *callStats) {
+	st := &callStats{}
+	return context.WithValue(ctx, callStatsKey{}, st), st
+}
+
+// callStatsFromContext は ctx に設定された callStats を返す。
+func callStatsFromContext(ctx context.Context) *callStats {
+	if st, ok := ctx.Value(callStatsKey{}).(*callStats); ok {
+		return st
+	}
+	return discardStats
+}
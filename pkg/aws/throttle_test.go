@@ -0,0 +1,260 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+var (
+	errThrottling   = &smithy.GenericAPIError{Code: "Throttling", Message: "Rate exceeded"}
+	errAccessDenied = &smithy.GenericAPIError{Code: "AccessDenied", Message: "not authorized"}
+)
+
+// throttlingEc2 は fakeEc2 の DescribeSubnets の前に、failures 回だけ err を返す Ec2API。
+type throttlingEc2 struct {
+	*fakeEc2
+	err      error
+	failures int
+}
+
+func (f *throttlingEc2) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	if f.failures > 0 {
+		f.failures--
+		f.called("DescribeSubnets")
+		return nil, f.err
+	}
+	return f.fakeEc2.DescribeSubnets(ctx, params, optFns...)
+}
+
+// testThrottleConfig はテストが待たずに済むよう、待ち時間を短くした ThrottleConfig を返す。
+func testThrottleConfig(maxAttempts int) ThrottleConfig {
+	return ThrottleConfig{
+		RatePerSecond: 1000,
+		Burst:         1000,
+		Retry:         RetryPolicy{MaxAttempts: maxAttempts, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond},
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 8, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 0, max: 100 * time.Millisecond},
+		{attempt: 1, max: 200 * time.Millisecond},
+		{attempt: 3, max: 800 * time.Millisecond},
+		{attempt: 4, max: time.Second},
+		{attempt: 20, max: time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if d := p.backoff(tt.attempt); d < 0 || d > tt.max {
+				t.Fatalf("backoff(%d) = %v, want within [0, %v]", tt.attempt, d, tt.max)
+			}
+		}
+	}
+
+	if d := (RetryPolicy{MaxAttempts: 3}).backoff(5); d != 0 {
+		t.Errorf("backoff without BaseDelay = %v, want 0", d)
+	}
+}
+
+func TestGuardedCallRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		failures    int
+		maxAttempts int
+
+		wantErr       error
+		wantCalls     int64
+		wantRetries   int64
+		wantThrottled int64
+	}{
+		{name: "success", failures: 0, maxAttempts: 3, wantCalls: 1},
+		{name: "throttled then success", err: errThrottling, failures: 2, maxAttempts: 3, wantCalls: 3, wantRetries: 2, wantThrottled: 2},
+		{name: "throttled until max attempts", err: errThrottling, failures: 5, maxAttempts: 3, wantErr: errThrottling, wantCalls: 3, wantRetries: 2, wantThrottled: 3},
+		{name: "non-retryable error", err: errAccessDenied, failures: 1, maxAttempts: 3, wantErr: errAccessDenied, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewAPIGuard(testThrottleConfig(tt.maxAttempts))
+			ctx, stats := withCallStats(context.Background())
+
+			failures := tt.failures
+			invoked := 0
+			out, err := guardedCall(ctx, g, serviceEc2, func(ctx context.Context) (string, error) {
+				invoked++
+				if failures > 0 {
+					failures--
+					return "", tt.err
+				}
+				return "ok", nil
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && out != "ok" {
+				t.Errorf("out = %q, want ok", out)
+			}
+			if int64(invoked) != tt.wantCalls {
+				t.Errorf("fn invoked %d time(s), want %d", invoked, tt.wantCalls)
+			}
+			for _, c := range []struct {
+				name      string
+				got, want int64
+			}{
+				{"guard calls", g.Calls(), tt.wantCalls},
+				{"guard retries", g.Retries(), tt.wantRetries},
+				{"guard throttled", g.Throttled(), tt.wantThrottled},
+				{"lister calls", stats.calls.Load(), tt.wantCalls},
+				{"lister retries", stats.retries.Load(), tt.wantRetries},
+				{"lister throttled", stats.throttled.Load(), tt.wantThrottled},
+			} {
+				if c.got != c.want {
+					t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+				}
+			}
+			// スロットリングを受けるとサービスのレートを下げる
+			if rate := g.Limiter(serviceEc2).Rate(); (tt.wantThrottled > 0) != (rate < 1000) {
+				t.Errorf("rate = %v after %d throttle(s)", rate, tt.wantThrottled)
+			}
+		})
+	}
+}
+
+func TestGuardedCallBudget(t *testing.T) {
+	cfg := testThrottleConfig(3)
+	cfg.MaxAPICalls = 2
+	g := NewAPIGuard(cfg)
+
+	invoked := 0
+	call := func() error {
+		_, err := guardedCall(context.Background(), g, serviceEc2, func(ctx context.Context) (struct{}, error) {
+			invoked++
+			return struct{}{}, nil
+		})
+		return err
+	}
+	for i := 0; i < 2; i++ {
+		if err := call(); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i+1, err)
+		}
+	}
+	if err := call(); !errors.Is(err, ErrAPICallBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrAPICallBudgetExceeded", err)
+	}
+	if invoked != 2 {
+		t.Errorf("fn invoked %d time(s), want 2", invoked)
+	}
+
+	// リトライも呼び出し上限に数える
+	g = NewAPIGuard(cfg)
+	_, err := guardedCall(context.Background(), g, serviceEc2, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, errThrottling
+	})
+	if !errors.Is(err, ErrAPICallBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrAPICallBudgetExceeded", err)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	b := NewTokenBucket(100, 2)
+	// バースト分は待たずに取得できる
+	for i := 0; i < 2; i++ {
+		if d := b.reserve(); d != 0 {
+			t.Fatalf("reserve %d = %v, want 0 within burst", i+1, d)
+		}
+	}
+	// バーストを超えると 1 トークン分（1/100 秒）程度待つ
+	if d := b.reserve(); d <= 0 || d > 10*time.Millisecond {
+		t.Errorf("reserve after burst = %v, want within (0, 10ms]", d)
+	}
+
+	b.OnThrottle()
+	if got := b.Rate(); got != 50 {
+		t.Errorf("rate after throttle = %v, want 50", got)
+	}
+	for i := 0; i < 10; i++ {
+		b.OnThrottle()
+	}
+	if got := b.Rate(); got != 100.0/16 {
+		t.Errorf("rate after repeated throttles = %v, want minimum %v", got, 100.0/16)
+	}
+	for i := 0; i < 40; i++ {
+		b.OnSuccess()
+	}
+	if got := b.Rate(); got != 100 {
+		t.Errorf("rate after successes = %v, want 100", got)
+	}
+
+	unlimited := NewTokenBucket(0, 1)
+	for i := 0; i < 10; i++ {
+		if d := unlimited.reserve(); d != 0 {
+			t.Fatalf("reserve without rate limit = %v, want 0", d)
+		}
+	}
+}
+
+func TestTokenBucketWaitCanceled(t *testing.T) {
+	b := NewTokenBucket(0.001, 1)
+	b.reserve()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestListResourcesReportsAPICallStats(t *testing.T) {
+	fake := &throttlingEc2{fakeEc2: newFakeEc2(0), err: errThrottling, failures: 2}
+	guard := NewAPIGuard(testThrottleConfig(5))
+	s := NewAwsVpcDiscoveryService(NewGuardedEc2API(fake, guard), nil, nil, nopLogger{})
+
+	_, _, report, err := s.ListResources(context.Background(), terraform.DiscoveryScope{
+		VpcID:           "vpc-1",
+		Region:          "ap-northeast-1",
+		ResourceFilters: []terraform.ResourceFilter{{Type: "aws_subnet"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]terraform.ListerReport{
+		vpcListerName: {APICalls: 1},
+		"aws_subnet":  {APICalls: 3, Retries: 2, Throttled: 2, ResourceCount: 3},
+	}
+	if len(report.Listers) != len(want) {
+		t.Fatalf("report has %d lister(s), want %d", len(report.Listers), len(want))
+	}
+	for _, lr := range report.Listers {
+		w, ok := want[lr.Name]
+		if !ok {
+			t.Errorf("unexpected lister %s in report", lr.Name)
+			continue
+		}
+		if lr.Status != terraform.ListerStatusOK {
+			t.Errorf("%s status = %s, want ok", lr.Name, lr.Status)
+		}
+		if lr.APICalls != w.APICalls || lr.Retries != w.Retries || lr.Throttled != w.Throttled {
+			t.Errorf("%s calls/retries/throttled = %d/%d/%d, want %d/%d/%d",
+				lr.Name, lr.APICalls, lr.Retries, lr.Throttled, w.APICalls, w.Retries, w.Throttled)
+		}
+		if w.ResourceCount != 0 && lr.ResourceCount != w.ResourceCount {
+			t.Errorf("%s resources = %d, want %d", lr.Name, lr.ResourceCount, w.ResourceCount)
+		}
+	}
+	if guard.Calls() != 4 || guard.Retries() != 2 || guard.Throttled() != 2 {
+		t.Errorf("guard calls/retries/throttled = %d/%d/%d, want 4/2/2", guard.Calls(), guard.Retries(), guard.Throttled())
+	}
+}
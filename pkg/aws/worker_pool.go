@@ -0,0 +1,211 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+// DefaultDiscoveryConcurrency は ListXXX を並列実行するワーカー数のデフォルト値。
+// AWS API のスロットリングを避けるため控えめな値にしている。
+const DefaultDiscoveryConcurrency = 4
+
+// listerFunc は AwsVpcDiscoveryService の各 ListXXX（VPC ID を受け取るもの）のシグネチャ。
+type listerFunc func(ctx context.Context, vpcID string) ([]terraform.Resource, []terraform.Relation, error)
+
+// listerResult は 1 つの ResourceLister の実行結果。
+type listerResult struct {
+	resources []terraform.Resource
+	relations []terraform.Relation
+	err       error
+	duration  time.Duration
+
+	// APIGuard 経由の API 呼び出し統計
+	apiCalls  int64
+	retries   int64
+	throttled int64
+	// cacheHit はディスカバリキャッシュから結果を取得したかどうか
+	cacheHit bool
+}
+
+// report は listerResult を DiscoveryReport 用の ListerReport に変換する。
+// 権限不足・クライアント未設定によるエラーは failed ではなく skipped として扱う。
+func (r listerResult) report(name string) terraform.ListerReport {
+	lr := terraform.ListerReport{
+		Name:          name,
+		Status:        terraform.ListerStatusOK,
+		Duration:      r.duration,
+		ResourceCount: len(r.resources),
+		RelationCount: len(r.relations),
+		APICalls:      r.apiCalls,
+		Retries:       r.retries,
+		Throttled:     r.throttled,
+		CacheHit:      r.cacheHit,
+	}
+	if r.err != nil {
+		lr.Status = terraform.ListerStatusFailed
+		if r.skipped() {
+			lr.Status = terraform.ListerStatusSkipped
+		}
+		lr.Err = r.err
+		lr.Error = r.err.Error()
+	}
+	return lr
+}
+
+// skipped は権限不足またはクライアント未設定により、列挙を行わなかった結果かどうかを返す。
+// 失敗ではないため、依存する lister は結果が空のものとして実行する。
+func (r listerResult) skipped() bool {
+	return isAccessDenied(r.err) || errors.Is(r.err, ErrClientNotConfigured)
+}
+
+// runListers は listers を依存関係順に、最大 concurrency 並列で実行し、listers と同じ順序の結果を返す。
+// 各 lister は Dependencies がすべて完了した時点で実行を開始する。
+// 1 つの lister の失敗は、それに依存しない lister に影響しない（部分失敗を許容する）。
+// 依存先が失敗した lister は実行せず、依存先の失敗を表すエラーを結果に入れる。
+// 権限不足やクライアント未設定で skipped になった依存先は失敗とみなさず、結果が空のものとして扱う。
+// ctx がキャンセルされた場合、未着手の lister の結果には ctx のエラーが入る。
+//
+// listers の依存先はすべて listers に含まれている必要がある（ListerRegistry.Plan の結果を渡す）。
+func runListers(ctx context.Context, listers []ResourceLister, scope terraform.DiscoveryScope, concurrency int) []listerResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	index := make(map[string]int, len(listers))
+	for i, l := range listers {
+		index[l.TypeName()] = i
+	}
+	pendingDeps := make([]int, len(listers))
+	dependents := make([][]int, len(listers))
+	for i, l := range listers {
+		for _, dep := range l.Dependencies() {
+			d := index[dep]
+			pendingDeps[i]++
+			dependents[d] = append(dependents[d], i)
+		}
+	}
+
+	// results[i] はワーカーが書き込んだ後に done で完了を通知するため、
+	// 完了通知を受けた後の読み出し（依存先の結果参照）は安全に行える。
+	results := make([]listerResult, len(listers))
+	jobs := make(chan int, len(listers))
+	done := make(chan int, len(listers))
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					results[i] = listerResult{err: err}
+				} else {
+					results[i] = runLister(upstreamContext(ctx, listers[i], results, index), listers[i], scope)
+				}
+				done <- i
+			}
+		}()
+	}
+
+	var ready []int
+	for i := range listers {
+		if pendingDeps[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	remaining := len(listers)
+	for remaining > 0 {
+		// 実行可能になった lister を投入する。依存先が失敗した lister は実行せずに完了扱いにする。
+		for len(ready) > 0 {
+			i := ready[0]
+			ready = ready[1:]
+			if failed := failedDependency(listers[i], results, index); failed != "" {
+				results[i] = listerResult{err: fmt.Errorf("skipped because dependency %s failed", failed)}
+				remaining--
+				ready = append(ready, release(i, pendingDeps, dependents)...)
+				continue
+			}
+			jobs <- i
+		}
+		if remaining == 0 {
+			break
+		}
+		i := <-done
+		remaining--
+		ready = append(ready, release(i, pendingDeps, dependents)...)
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// release は i の完了により、依存先がすべて完了した lister の一覧を返す。
+func release(i int, pendingDeps []int, dependents [][]int) []int {
+	var ready []int
+	for _, d := range dependents[i] {
+		pendingDeps[d]--
+		if pendingDeps[d] == 0 {
+			ready = append(ready, d)
+		}
+	}
+	return ready
+}
+
+// failedDependency は l の依存先のうち失敗したものの TypeName を返す。
+// すべて成功しているか skipped の場合は空文字を返す。
+func failedDependency(l ResourceLister, results []listerResult, index map[string]int) string {
+	for _, dep := range l.Dependencies() {
+		if r := results[index[dep]]; r.err != nil && !r.skipped() {
+			return dep
+		}
+	}
+	return ""
+}
+
+// upstreamContext は l の依存先の列挙結果を設定した ctx を返す。
+func upstreamContext(ctx context.Context, l ResourceLister, results []listerResult, index map[string]int) context.Context {
+	deps := l.Dependencies()
+	if len(deps) == 0 {
+		return ctx
+	}
+	upstream := make(map[string][]terraform.Resource, len(deps))
+	for _, dep := range deps {
+		upstream[dep] = results[index[dep]].resources
+	}
+	return withUpstream(ctx, upstream)
+}
+
+// runLister は 1 つの lister を実行し、所要時間と API 呼び出し統計を含む結果を返す。
+// lister の panic はプロセスを止めずに、その lister の失敗として結果に入れる。
+func runLister(ctx context.Context, l ResourceLister, scope terraform.DiscoveryScope) (res listerResult) {
+	ctx, stats := withCallStats(ctx)
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			res = listerResult{
+				err:       fmt.Errorf("lister %s panicked: %v", l.TypeName(), r),
+				duration:  time.Since(start),
+				apiCalls:  stats.calls.Load(),
+				retries:   stats.retries.Load(),
+				throttled: stats.throttled.Load(),
+			}
+		}
+	}()
+	rs, rels, err := l.List(ctx, scope)
+	return listerResult{
+		resources: rs,
+		relations: rels,
+		err:       err,
+		duration:  time.Since(start),
+		apiCalls:  stats.calls.Load(),
+		retries:   stats.retries.Load(),
+		throttled: stats.throttled.Load(),
+		cacheHit:  stats.cacheHit.Load(),
+	}
+}
@@ -142,8 +142,13 @@ func detectProviderFromType(resourceType string) string {
 }
 
 // FilterConflicted は import 対象 Resource 一覧を、既存構成との競合有無で分類する。
+// data ソースは resource ブロックと競合しないため、常に importable に含める。
 func (a *ExistingConfigAnalyzer) FilterConflicted(resources []terraform.Resource, index ExistingConfigIndex) (importable []terraform.Resource, conflicted []ConflictedResource) {
 	for _, r := range resources {
+		if r.IsDataSource() {
+			importable = append(importable, r)
+			continue
+		}
 		key := ResourceKey{
 			Provider: r.Provider,
 			Type:     r.Type,
@@ -1,6 +1,7 @@
 package importer
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -28,6 +29,13 @@ type HclGenerationConfig struct {
 	TfDir         string
 	OutputDir     string
 	SplitStrategy SplitStrategy
+	// ProviderAliasPerRegion が true の場合、リソースのリージョン（Labels["aws_region"]）ごとに
+	// aws provider の alias を定義した providers.tf を生成し、各リソースに provider = aws.<alias> を付与する。
+	// 複数リージョンを対象にした場合や、別アカウントのロールを引き受ける場合に利用する。
+	ProviderAliasPerRegion bool
+	// AssumeRoles は providers.tf の各 provider ブロックに出力する assume_role（ロールチェーン順）。
+	// ProviderAliasPerRegion が true の場合のみ利用する。
+	AssumeRoles []terraform.AssumeRole
 }
 
 // HclGenerationResult は HCL 生成結果のメタ情報。
@@ -66,14 +74,14 @@ func (g *HclGenerator) Generate(resources []terraform.Resource, relations []terr
 
 	switch cfg.SplitStrategy {
 	case SplitByType:
-		return g.generateByType(resources, relations, outputRoot)
+		return g.generateByType(resources, relations, outputRoot, cfg)
 	default:
 		return HclGenerationResult{}, fmt.Errorf("split strategy %q is not supported yet", cfg.SplitStrategy)
 	}
 }
 
 // generateByType はリソース Type ごとに 1 ファイルを生成する実装。
-func (g *HclGenerator) generateByType(resources []terraform.Resource, relations []terraform.Relation, outputRoot string) (HclGenerationResult, error) {
+func (g *HclGenerator) generateByType(resources []terraform.Resource, relations []terraform.Relation, outputRoot string, cfg HclGenerationConfig) (HclGenerationResult, error) {
 	// Type ごとにグルーピング
 	typeGrouped := make(map[string][]terraform.Resource)
 	for _, r := range resources {
@@ -89,6 +97,7 @@ func (g *HclGenerator) generateByType(resources []terraform.Resource, relations
 
 	// Relation と Resource のインデックス
 	relsByFrom := groupRelationsByFrom(relations)
+	relsByTo := groupRelationsByTo(relations)
 	resByID := indexResourcesByID(resources)
 
 	var generatedFiles []string
@@ -115,7 +124,13 @@ func (g *HclGenerator) generateByType(resources []terraform.Resource, relations
 
 		var b strings.Builder
 		for _, r := range rs {
-			block := buildResourceBlock(r, relsByFrom, resByID)
+			providerAlias := ""
+			if cfg.ProviderAliasPerRegion {
+				if region := terraform.RegionOf(r); region != "" {
+					providerAlias = terraform.RegionProviderAlias(region)
+				}
+			}
+			block := buildResourceBlock(r, providerAlias, relsByFrom, relsByTo, resByID)
 			b.WriteString(block)
 			b.WriteString("\n\n")
 			resourceCounts[t]++
@@ -132,6 +147,22 @@ func (g *HclGenerator) generateByType(resources []terraform.Resource, relations
 		generatedFiles = append(generatedFiles, path)
 	}
 
+	if path, err := writeVariables(resources, outputRoot); err != nil {
+		return HclGenerationResult{}, err
+	} else if path != "" {
+		generatedFiles = append(generatedFiles, path)
+	}
+
+	if cfg.ProviderAliasPerRegion {
+		path, err := writeRegionProviders(resources, cfg.AssumeRoles, outputRoot)
+		if err != nil {
+			return HclGenerationResult{}, err
+		}
+		if path != "" {
+			generatedFiles = append(generatedFiles, path)
+		}
+	}
+
 	return HclGenerationResult{
 		OutputDir:      outputRoot,
 		GeneratedFiles: generatedFiles,
@@ -139,6 +170,264 @@ func (g *HclGenerator) generateByType(resources []terraform.Resource, relations
 	}, nil
 }
 
+// writeRegionProviders はリソースに含まれるリージョンごとに aws provider の alias を定義した
+// providers.tf を生成し、そのパスを返す。リージョンを持つリソースがない場合は何もしない。
+// assumeRoles を指定した場合は各 provider に assume_role ブロックをチェーン順に出力する。
+func writeRegionProviders(resources []terraform.Resource, assumeRoles []terraform.AssumeRole, outputRoot string) (string, error) {
+	seen := make(map[string]bool)
+	var regions []string
+	for _, r := range resources {
+		region := terraform.RegionOf(r)
+		if region == "" || seen[region] {
+			continue
+		}
+		seen[region] = true
+		regions = append(regions, region)
+	}
+	if len(regions) == 0 {
+		return "", nil
+	}
+	sort.Strings(regions)
+
+	var b strings.Builder
+	for _, region := range regions {
+		b.WriteString("provider \"aws\" {\n")
+		fmt.Fprintf(&b, "  alias  = %q\n", terraform.RegionProviderAlias(region))
+		fmt.Fprintf(&b, "  region = %q\n", region)
+		for _, role := range assumeRoles {
+			b.WriteString("\n  assume_role {\n")
+			fmt.Fprintf(&b, "    role_arn     = %q\n", role.RoleArn)
+			fmt.Fprintf(&b, "    session_name = %q\n", role.SessionNameOrDefault())
+			if role.ExternalID != "" {
+				fmt.Fprintf(&b, "    external_id  = %q\n", role.ExternalID)
+			}
+			b.WriteString("  }\n")
+		}
+		b.WriteString("}\n\n")
+	}
+
+	path := filepath.Join(outputRoot, "providers.tf")
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(b.String())+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write HCL file %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// variableAttribute は API から値を取得できない属性（マスターパスワード・デプロイパッケージなど）の定義。
+type variableAttribute struct {
+	Name string
+	// UnlessSet のいずれかの属性を持つ場合は不要（Secrets Manager 管理・リードレプリカ・コンテナイメージなど）とみなす。
+	UnlessSet []string
+	// Sensitive の場合は sensitive な変数として定義する。
+	Sensitive bool
+}
+
+// variableAttributes は API から値を取得できない属性を持つリソース Type ごとの定義。
+// 該当する属性には値を出力せず、variables.tf に定義した変数への参照（var.<name>）を出力する。
+var variableAttributes = map[string]variableAttribute{
+	"aws_db_instance":     {Name: "password", UnlessSet: []string{"manage_master_user_password", "replicate_source_db", "snapshot_identifier"}, Sensitive: true},
+	"aws_rds_cluster":     {Name: "master_password", UnlessSet: []string{"manage_master_user_password", "replication_source_identifier", "snapshot_identifier"}, Sensitive: true},
+	"aws_lambda_function": {Name: "filename", UnlessSet: []string{"image_uri", "s3_bucket"}},
+}
+
+// attributeVariable は r の変数で受け取る属性名と、その変数名を返す。変数が不要な場合は空文字を返す。
+func attributeVariable(r terraform.Resource) (attr string, variable string) {
+	def, ok := variableAttributes[r.Type]
+	if !ok || r.IsDataSource() {
+		return "", ""
+	}
+	for _, key := range def.UnlessSet {
+		if _, ok := r.Attributes[key]; ok {
+			return "", ""
+		}
+	}
+	return def.Name, fmt.Sprintf("%s_%s_%s", strings.TrimPrefix(r.Type, "aws_"), r.Name, def.Name)
+}
+
+// writeVariables は attributeVariable の変数を定義した variables.tf を生成し、そのパスを返す。
+// 該当するリソースがない場合は何もしない。
+func writeVariables(resources []terraform.Resource, outputRoot string) (string, error) {
+	sensitive := make(map[string]bool)
+	var variables []string
+	for _, r := range resources {
+		if _, v := attributeVariable(r); v != "" {
+			variables = append(variables, v)
+			sensitive[v] = variableAttributes[r.Type].Sensitive
+		}
+	}
+	if len(variables) == 0 {
+		return "", nil
+	}
+	sort.Strings(variables)
+
+	var b strings.Builder
+	for _, v := range variables {
+		fmt.Fprintf(&b, "variable %q {\n", v)
+		if sensitive[v] {
+			b.WriteString("  type      = string\n")
+			b.WriteString("  sensitive = true\n")
+		} else {
+			b.WriteString("  type = string\n")
+		}
+		b.WriteString("}\n\n")
+	}
+
+	path := filepath.Join(outputRoot, "variables.tf")
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(b.String())+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write HCL file %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// jsonAttributes は JSON 文字列を保持するリソース Type ごとの属性名。
+// エスケープされた文字列リテラルの代わりに jsonencode() の式として出力する。
+var jsonAttributes = map[string][]string{
+	"aws_ecs_task_definition": {"container_definitions"},
+	"aws_iam_role":            {"assume_role_policy"},
+	"aws_iam_role_policy":     {"policy"},
+	"aws_kms_key":             {"policy"},
+}
+
+// jsonencodeExpression は JSON 文字列 s を jsonencode(<HCL の値>) の式に変換する。
+// indent は属性の出力位置のインデントで、2 行目以降の行頭に付与する。s が JSON として不正な場合は false を返す。
+func jsonencodeExpression(s string, indent string) (terraform.HCLExpression, bool) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return "", false
+	}
+	var b strings.Builder
+	b.WriteString("jsonencode(")
+	writeHCLValue(&b, v, indent)
+	b.WriteString(")")
+	return terraform.HCLExpression(b.String()), true
+}
+
+// writeHCLValue は JSON をデコードした値を HCL の値として b に出力する（オブジェクトのキーはソートする）。
+func writeHCLValue(b *strings.Builder, v any, indent string) {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			b.WriteString("{}")
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("{\n")
+		for _, k := range keys {
+			fmt.Fprintf(b, "%s  %s = ", indent, hclObjectKey(k))
+			writeHCLValue(b, v[k], indent+"  ")
+			b.WriteString("\n")
+		}
+		b.WriteString(indent + "}")
+	case []any:
+		if len(v) == 0 {
+			b.WriteString("[]")
+			return
+		}
+		b.WriteString("[\n")
+		for _, elem := range v {
+			b.WriteString(indent + "  ")
+			writeHCLValue(b, elem, indent+"  ")
+			b.WriteString(",\n")
+		}
+		b.WriteString(indent + "]")
+	case string:
+		b.WriteString(hclStringLiteral(v))
+	case json.Number:
+		b.WriteString(v.String())
+	case bool:
+		fmt.Fprintf(b, "%t", v)
+	default:
+		b.WriteString("null")
+	}
+}
+
+// heredocAttributes は複数行の文字列（インラインの buildspec など）を保持しうるリソース Type ごとの属性名。
+// ネストしたブロック内の属性も対象とし、複数行の値はヒアドキュメントとして出力する。
+var heredocAttributes = map[string][]string{
+	"aws_codebuild_project": {"buildspec"},
+}
+
+// replaceHeredocValues は attrs（ネストしたブロックを含む）の keys の属性のうち、複数行の文字列をヒアドキュメントの式に置き換える。
+// ブロックは Resource.Attributes と共有しているため、置き換えたブロックはコピーする。
+func replaceHeredocValues(attrs map[string]any, keys []string) bool {
+	replacedAny := false
+	for name, val := range attrs {
+		if v, ok := val.(string); ok {
+			for _, key := range keys {
+				if name == key && strings.Contains(v, "\n") {
+					attrs[name] = heredocExpression(v)
+					replacedAny = true
+				}
+			}
+			continue
+		}
+		blocks, ok := val.([]terraform.HCLBlock)
+		if !ok {
+			continue
+		}
+		var replaced []terraform.HCLBlock
+		for i, block := range blocks {
+			copied := make(terraform.HCLBlock, len(block))
+			for k, v := range block {
+				copied[k] = v
+			}
+			if !replaceHeredocValues(copied, keys) {
+				continue
+			}
+			if replaced == nil {
+				replaced = append([]terraform.HCLBlock(nil), blocks...)
+			}
+			replaced[i] = copied
+		}
+		if replaced != nil {
+			attrs[name] = replaced
+			replacedAny = true
+		}
+	}
+	return replacedAny
+}
+
+// heredocExpression は s をヒアドキュメント（<<EOT ... EOT）の式にする。
+// ヒアドキュメントはテンプレートとして解釈されるため "${" / "%{" はエスケープし、
+// 末尾に改行がない値は chomp() で囲んで値を変えないようにする。
+func heredocExpression(s string) terraform.HCLExpression {
+	marker := "EOT"
+	for strings.HasPrefix(s, marker) || strings.Contains(s, "\n"+marker) {
+		marker += "_"
+	}
+	body := strings.ReplaceAll(strings.ReplaceAll(s, "${", "$${"), "%{", "%%{")
+	if strings.HasSuffix(body, "\n") {
+		return terraform.HCLExpression("<<" + marker + "\n" + body + marker)
+	}
+	return terraform.HCLExpression("chomp(<<" + marker + "\n" + body + "\n" + marker + "\n)")
+}
+
+// hclObjectKey は HCL のオブジェクトのキーを返す。識別子として書けないキーは文字列リテラルにする。
+func hclObjectKey(k string) string {
+	if k == "" || k == "null" || k == "true" || k == "false" {
+		return hclStringLiteral(k)
+	}
+	for i, c := range k {
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if !isLetter && (i == 0 || !(c == '-' || (c >= '0' && c <= '9'))) {
+			return hclStringLiteral(k)
+		}
+	}
+	return k
+}
+
+// hclStringLiteral は s を HCL の文字列リテラルにする。テンプレートとして解釈される "${" / "%{" はエスケープする。
+func hclStringLiteral(s string) string {
+	out := strings.ReplaceAll(fmt.Sprintf("%q", s), "${", "$${")
+	return strings.ReplaceAll(out, "%{", "%%{")
+}
+
 // groupRelationsByFrom は From ID ごとの Relation 一覧を作る。
 func groupRelationsByFrom(relations []terraform.Relation) map[string][]terraform.Relation {
 	m := make(map[string][]terraform.Relation)
@@ -148,6 +437,15 @@ func groupRelationsByFrom(relations []terraform.Relation) map[string][]terraform
 	return m
 }
 
+// groupRelationsByTo は To ID ごとの Relation 一覧を作る。
+func groupRelationsByTo(relations []terraform.Relation) map[string][]terraform.Relation {
+	m := make(map[string][]terraform.Relation)
+	for _, rel := range relations {
+		m[rel.To] = append(m[rel.To], rel)
+	}
+	return m
+}
+
 // indexResourcesByID は Resource.ID から Resource へのマップを作る。
 func indexResourcesByID(resources []terraform.Resource) map[string]terraform.Resource {
 	m := make(map[string]terraform.Resource)
@@ -157,8 +455,9 @@ func indexResourcesByID(resources []terraform.Resource) map[string]terraform.Res
 	return m
 }
 
-// buildResourceBlock は 1 つの Resource から HCL の resource ブロック文字列を生成する。
-func buildResourceBlock(r terraform.Resource, relsByFrom map[string][]terraform.Relation, resByID map[string]terraform.Resource) string {
+// buildResourceBlock は 1 つの Resource から HCL の resource ブロック（data ソースの場合は data ブロック）文字列を生成する。
+// providerAlias が空でない場合は provider = aws.<providerAlias> を先頭に出力する。
+func buildResourceBlock(r terraform.Resource, providerAlias string, relsByFrom, relsByTo map[string][]terraform.Relation, resByID map[string]terraform.Resource) string {
 	// Attributes をコピーしてから Relation に応じた参照解決を行う
 	attrs := make(map[string]any, len(r.Attributes))
 	for k, v := range r.Attributes {
@@ -166,53 +465,189 @@ func buildResourceBlock(r terraform.Resource, relsByFrom map[string][]terraform.
 	}
 
 	applyRelationsToAttributes(r, attrs, relsByFrom, resByID)
+	applyReverseRelationsToAttributes(r, attrs, relsByTo, resByID)
+	for _, key := range matchOnlyAttributes[r.Type] {
+		delete(attrs, key)
+	}
+	if key, v := attributeVariable(r); v != "" {
+		attrs[key] = terraform.HCLExpression("var." + v)
+	}
+	if keys := heredocAttributes[r.Type]; len(keys) > 0 {
+		replaceHeredocValues(attrs, keys)
+	}
+	for _, key := range jsonAttributes[r.Type] {
+		if s, ok := attrs[key].(string); ok {
+			if expr, ok := jsonencodeExpression(s, "  "); ok {
+				attrs[key] = expr
+			}
+		}
+	}
 
 	var b strings.Builder
-	fmt.Fprintf(&b, "resource %q %q {\n", r.Type, r.Name)
+	blockType := "resource"
+	if r.IsDataSource() {
+		blockType = "data"
+	}
+	fmt.Fprintf(&b, "%s %q %q {\n", blockType, r.Type, r.Name)
+	if providerAlias != "" {
+		fmt.Fprintf(&b, "  provider = %s.%s\n\n", r.Provider, providerAlias)
+	}
+
+	writeHCLAttributes(&b, attrs, "  ")
+
+	b.WriteString("}")
+	return b.String()
+}
 
+// writeHCLAttributes は attrs を indent 付きで b に出力する。
+// 通常の属性をキー順に出力した後、[]terraform.HCLBlock の値をネストしたブロックとしてキー順に出力する。
+func writeHCLAttributes(b *strings.Builder, attrs map[string]any, indent string) {
 	// キー順で安定させる
 	var keys []string
+	var blockKeys []string
 	for k := range attrs {
 		// nil 値は出力しない
 		if attrs[k] == nil {
 			continue
 		}
+		if _, ok := attrs[k].([]terraform.HCLBlock); ok {
+			blockKeys = append(blockKeys, k)
+			continue
+		}
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
+	sort.Strings(blockKeys)
 
 	for _, key := range keys {
 		val := attrs[key]
-		// tags は特別扱い（map[string]string）
-		if key == "tags" {
-			if tags, ok := val.(map[string]string); ok {
-				b.WriteString("  tags = {\n")
-
-				// タグキーもソートしておく
-				var tkeys []string
-				for tk := range tags {
-					tkeys = append(tkeys, tk)
-				}
-				sort.Strings(tkeys)
-				for _, tk := range tkeys {
-					tv := tags[tk]
-					fmt.Fprintf(&b, "    %q = %q\n", tk, tv)
-				}
-				b.WriteString("  }\n")
-				continue
+		// tags や environment.variables などの map[string]string は複数行の map として出力する
+		if m, ok := val.(map[string]string); ok {
+			fmt.Fprintf(b, "%s%s = {\n", indent, key)
+
+			// キーもソートしておく
+			var mkeys []string
+			for mk := range m {
+				mkeys = append(mkeys, mk)
+			}
+			sort.Strings(mkeys)
+			for _, mk := range mkeys {
+				fmt.Fprintf(b, "%s  %s = %s\n", indent, hclStringLiteral(mk), hclStringLiteral(m[mk]))
 			}
+			b.WriteString(indent + "}\n")
+			continue
 		}
 
 		line := buildHCLAttributeLine(key, val)
 		if line != "" {
-			b.WriteString("  ")
+			b.WriteString(indent)
 			b.WriteString(line)
 			b.WriteString("\n")
 		}
 	}
 
-	b.WriteString("}")
-	return b.String()
+	// ネストしたブロックは要素の順序を保って出力する
+	for _, key := range blockKeys {
+		for _, block := range attrs[key].([]terraform.HCLBlock) {
+			fmt.Fprintf(b, "\n%s%s {\n", indent, key)
+			writeHCLAttributes(b, block, indent+"  ")
+			b.WriteString(indent + "}\n")
+		}
+	}
+}
+
+// idAttributes は Relation の参照先 Type ごとに、参照先の ID を保持する属性名。
+// Resource がこの属性を持つ場合、値を参照式に置き換える。
+var idAttributes = map[string]string{
+	"aws_vpc":                             "vpc_id",
+	"aws_instance":                        "instance_id",
+	"aws_network_interface":               "network_interface_id",
+	"aws_eip":                             "allocation_id",
+	"aws_network_acl":                     "network_acl_id",
+	"aws_route_table":                     "route_table_id",
+	"aws_vpc_peering_connection":          "vpc_peering_connection_id",
+	"aws_vpc_peering_connection_accepter": "vpc_peering_connection_id",
+	"aws_ec2_transit_gateway":             "transit_gateway_id",
+	"aws_internet_gateway":                "gateway_id",
+	"aws_vpn_gateway":                     "gateway_id",
+	"aws_egress_only_internet_gateway":    "egress_only_gateway_id",
+	"aws_nat_gateway":                     "nat_gateway_id",
+	"aws_vpc_endpoint":                    "vpc_endpoint_id",
+	"aws_ec2_carrier_gateway":             "carrier_gateway_id",
+}
+
+// idListAttributes は Relation の参照先 Type ごとに、参照先の ID 一覧を保持する属性名の候補。
+// Resource がこれらの属性を持つ場合、一覧の各 ID を参照式に置き換える（VPC エンドポイントなど）。
+var idListAttributes = map[string][]string{
+	"aws_route_table":           {"route_table_ids"},
+	"aws_subnet":                {"subnet_ids", "subnets"},
+	"aws_security_group":        {"security_group_ids", "security_groups", "vpc_security_group_ids", "vpc_security_group_memberships"},
+	"aws_ecs_capacity_provider": {"capacity_providers"},
+}
+
+// idValueAttributes は Relation の参照先 Type ごとに、参照先の ID を保持しうる属性名の候補。
+// 同じ型への参照を複数持つリソース（セキュリティグループルールの security_group_id / referenced_security_group_id など）や、
+// ネストしたブロック内の参照（リスナーの default_action.target_group_arn など）向けで、
+// 値が参照先の ID と一致する属性のみを参照式に置き換える。
+var idValueAttributes = map[string][]string{
+	"aws_security_group":              {"security_group_id", "referenced_security_group_id"},
+	"aws_lb":                          {"load_balancer_arn", "target_id"},
+	"aws_lb_target_group":             {"target_group_arn", "arn"},
+	"aws_lb_listener":                 {"listener_arn"},
+	"aws_instance":                    {"target_id"},
+	"aws_acm_certificate":             {"certificate_arn"},
+	"aws_db_subnet_group":             {"db_subnet_group_name"},
+	"aws_db_parameter_group":          {"parameter_group_name", "db_parameter_group_name"},
+	"aws_rds_cluster_parameter_group": {"db_cluster_parameter_group_name"},
+	"aws_db_option_group":             {"option_group_name"},
+	"aws_rds_cluster":                 {"cluster_identifier"},
+	"aws_kms_key":                     {"kms_key_id", "kms_key_arn", "encryption_key", "target_key_id"},
+	"aws_kms_alias":                   {"kms_key_id", "kms_key_arn", "encryption_key"},
+	"aws_elasticache_subnet_group":    {"subnet_group_name"},
+	"aws_elasticache_parameter_group": {"parameter_group_name"},
+	"aws_ecs_cluster":                 {"cluster", "cluster_name"},
+	"aws_ecs_task_definition":         {"task_definition"},
+	"aws_ecs_capacity_provider":       {"capacity_provider"},
+	"aws_lambda_function":             {"function_name"},
+	"aws_vpc":                         {"vpc_id"},
+	"aws_iam_role":                    {"role", "service_role", "iam_role", "task_role_arn", "execution_role_arn", "iam_role_arn"},
+	"aws_iam_instance_profile":        {"iam_instance_profile"},
+	"aws_cloudwatch_log_group":        {"log_group", "group_name", "log_destination"},
+}
+
+// referenceAttributes は参照先 Type ごとに、id の代わりに参照する属性名（KMS キーは ARN、ECS クラスター・ロググループは名前で参照する）。
+// KMS エイリアス（AWS 管理キーの data ソースを含む）は、参照元がキー ARN を保持するため target_key_arn を参照する。
+// idValueAttributes の照合にもこの属性の値を使う。値が参照先の ARN / id / 名前と一致する場合はその属性を参照する（replaceReferenceValues）。
+var referenceAttributes = map[string]string{
+	"aws_kms_key":               "arn",
+	"aws_kms_alias":             "target_key_arn",
+	"aws_ecs_cluster":           "name",
+	"aws_ecs_task_definition":   "arn",
+	"aws_ecs_capacity_provider": "name",
+	"aws_cloudwatch_log_group":  "name",
+}
+
+// matchOnlyAttributes は Type ごとに、参照元との照合（replaceReferenceValues）にのみ使い、
+// resource / data ブロックには出力しない属性名（読み取り専用で、引数や検索条件に指定できないもの）。
+var matchOnlyAttributes = map[string][]string{
+	"aws_acm_certificate":         {"arn"},
+	"aws_kms_alias":               {"arn", "target_key_arn"},
+	"aws_ecs_cluster":             {"arn"},
+	"aws_ecs_capacity_provider":   {"arn"},
+	"aws_ecs_task_definition":     {"arn"},
+	"aws_iam_role":                {"arn"},
+	"aws_iam_instance_profile":    {"arn"},
+	"aws_lambda_function":         {"arn"},
+	"aws_codebuild_project":       {"arn"},
+	"aws_kms_key":                 {"arn"},
+	"aws_cloudwatch_log_group":    {"arn"},
+	"aws_cloudwatch_metric_alarm": {"arn"},
+}
+
+// reverseIDAttributes は Relation の参照元 Type ごとに、参照先 Resource 側でその ID を保持する属性名。
+// 関係の向きと HCL 上の参照の向きが逆になるもの（eip -> nat_gateway に対する nat_gateway.allocation_id）に利用する。
+var reverseIDAttributes = map[string]string{
+	"aws_eip": "allocation_id",
 }
 
 // applyRelationsToAttributes は Relation に応じて attributes 内の参照フィールドを
@@ -224,24 +659,60 @@ func applyRelationsToAttributes(r terraform.Resource, attrs map[string]any, rels
 	}
 
 	var sgExprs []terraform.HCLExpression
+	// listRefs は ID 一覧属性ごとの「クラウド ID -> 参照式」
+	listRefs := make(map[string]map[string]terraform.HCLExpression)
+	// blockListRefs はネストしたブロック内の ID 一覧属性ごとの「クラウド ID -> 参照式」
+	blockListRefs := make(map[string]map[string]terraform.HCLExpression)
 
 	for _, rel := range rels {
 		target, ok := resByID[rel.To]
 		if !ok {
 			continue
 		}
+		expr := referenceExpression(target)
+
+		// ID 一覧属性を持つリソースは、該当する要素のみを参照式に置き換える
+		if key, ok := listAttributeKey(attrs, target.Type); ok {
+			if id, ok := target.Attributes["id"].(string); ok && id != "" {
+				if listRefs[key] == nil {
+					listRefs[key] = make(map[string]terraform.HCLExpression)
+				}
+				listRefs[key][id] = expr
+			}
+			continue
+		}
+		// インラインの SG ルール（ingress / egress ブロックの security_groups）など
+		if key, ok := blockListAttributeKey(attrs, target.Type); ok {
+			if id, ok := target.Attributes["id"].(string); ok && id != "" {
+				if blockListRefs[key] == nil {
+					blockListRefs[key] = make(map[string]terraform.HCLExpression)
+				}
+				blockListRefs[key][id] = expr
+			}
+			continue
+		}
+
+		if keys := idValueAttributes[target.Type]; len(keys) > 0 && replaceReferenceValues(attrs, keys, target) {
+			continue
+		}
+
+		// サブネット / ルートテーブル等 -> VPC の vpc_id、NACL ルール -> NACL の network_acl_id など
+		if key, ok := idAttributes[target.Type]; ok {
+			if _, ok := attrs[key]; ok {
+				attrs[key] = expr
+			}
+			continue
+		}
 
 		switch rel.Kind {
 		case terraform.RelationNetwork:
 			// インスタンス -> サブネット の network 関係を subnet_id に反映
 			if target.Type == "aws_subnet" {
-				expr := terraform.HCLExpression(fmt.Sprintf("%s.%s.id", target.Type, target.Name))
 				attrs["subnet_id"] = expr
 			}
 		case terraform.RelationSecurity:
 			// インスタンス -> セキュリティグループ の security 関係を vpc_security_group_ids に反映
 			if target.Type == "aws_security_group" {
-				expr := terraform.HCLExpression(fmt.Sprintf("%s.%s.id", target.Type, target.Name))
 				sgExprs = append(sgExprs, expr)
 			}
 		}
@@ -250,13 +721,191 @@ func applyRelationsToAttributes(r terraform.Resource, attrs map[string]any, rels
 	if len(sgExprs) > 0 {
 		attrs["vpc_security_group_ids"] = sgExprs
 	}
+	for key, refs := range listRefs {
+		attrs[key] = resolveIDList(attrs[key], refs)
+	}
+	if len(blockListRefs) > 0 {
+		resolveBlockIDLists(attrs, blockListRefs)
+	}
+}
+
+// replaceReferenceValues は attrs のうち keys に含まれ、値が target の参照属性（referenceAttribute）・ARN・id・名前の
+// いずれかと一致する属性を、一致した属性への参照式に置き換える。IAM ロールのように名前（ロールポリシーの role）と
+// ARN（Lambda の role）のどちらでも参照されるリソースや、キー ARN・キー ID・エイリアス名のいずれでも参照される KMS キー向け。
+// 1 つでも置き換えた場合は true を返す。
+func replaceReferenceValues(attrs map[string]any, keys []string, target terraform.Resource) bool {
+	matched := false
+	seen := make(map[string]bool)
+	for _, attr := range []string{referenceAttribute(target.Type), "arn", "id", "name"} {
+		if seen[attr] {
+			continue
+		}
+		seen[attr] = true
+		if v, ok := target.Attributes[attr].(string); ok && v != "" && replaceIDValues(attrs, keys, v, attributeReferenceExpression(target, attr)) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// replaceIDValues は attrs（およびネストしたブロック）のうち、keys に含まれ値が id と一致する属性を expr に置き換える。
+// ブロックは Resource.Attributes と共有しているため、置き換えたブロックはコピーする。1 つでも置き換えた場合は true を返す。
+func replaceIDValues(attrs map[string]any, keys []string, id string, expr terraform.HCLExpression) bool {
+	matched := false
+	for name, val := range attrs {
+		if v, ok := val.(string); ok && v == id {
+			for _, key := range keys {
+				if name == key {
+					attrs[name] = expr
+					matched = true
+				}
+			}
+			continue
+		}
+		blocks, ok := val.([]terraform.HCLBlock)
+		if !ok {
+			continue
+		}
+		var replaced []terraform.HCLBlock
+		for i, block := range blocks {
+			copied := make(terraform.HCLBlock, len(block))
+			for k, v := range block {
+				copied[k] = v
+			}
+			if !replaceIDValues(copied, keys, id, expr) {
+				continue
+			}
+			if replaced == nil {
+				replaced = append([]terraform.HCLBlock(nil), blocks...)
+			}
+			replaced[i] = copied
+		}
+		if replaced != nil {
+			attrs[name] = replaced
+			matched = true
+		}
+	}
+	return matched
+}
+
+// blockListAttributeKey は attrs のネストしたブロックが持つ、target 型の ID 一覧属性の名前を返す。
+func blockListAttributeKey(attrs map[string]any, targetType string) (string, bool) {
+	for _, key := range idListAttributes[targetType] {
+		for _, val := range attrs {
+			blocks, ok := val.([]terraform.HCLBlock)
+			if !ok {
+				continue
+			}
+			for _, block := range blocks {
+				if _, ok := block[key]; ok {
+					return key, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// resolveBlockIDLists はネストしたブロック内の ID 一覧属性を refs に従って参照式に置き換える。
+// ブロックは Resource.Attributes と共有しているため、置き換えたブロックはコピーする。
+func resolveBlockIDLists(attrs map[string]any, refs map[string]map[string]terraform.HCLExpression) {
+	for name, val := range attrs {
+		blocks, ok := val.([]terraform.HCLBlock)
+		if !ok {
+			continue
+		}
+		resolved := make([]terraform.HCLBlock, len(blocks))
+		for i, block := range blocks {
+			copied := make(terraform.HCLBlock, len(block))
+			for k, v := range block {
+				copied[k] = v
+			}
+			for key, keyRefs := range refs {
+				if v, ok := copied[key]; ok {
+					copied[key] = resolveIDList(v, keyRefs)
+				}
+			}
+			resolved[i] = copied
+		}
+		attrs[name] = resolved
+	}
+}
+
+// listAttributeKey は attrs が持つ、target 型の ID 一覧属性の名前を返す。
+func listAttributeKey(attrs map[string]any, targetType string) (string, bool) {
+	for _, key := range idListAttributes[targetType] {
+		if _, ok := attrs[key]; ok {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// applyReverseRelationsToAttributes は r を参照先とする Relation のうち、reverseIDAttributes に該当するものについて
+// r 側の属性を参照元 Resource への参照式に置き換える。
+func applyReverseRelationsToAttributes(r terraform.Resource, attrs map[string]any, relsByTo map[string][]terraform.Relation, resByID map[string]terraform.Resource) {
+	for _, rel := range relsByTo[r.ID] {
+		source, ok := resByID[rel.From]
+		if !ok {
+			continue
+		}
+		key, ok := reverseIDAttributes[source.Type]
+		if !ok {
+			continue
+		}
+		// 参照元の ID と一致する値のみ置き換える（NAT ゲートウェイのセカンダリ EIP など）
+		if id, ok := source.Attributes["id"].(string); ok && attrs[key] == id {
+			attrs[key] = referenceExpression(source)
+		}
+	}
+}
+
+// referenceExpression は target の id（referenceAttributes に定義がある場合はその属性）を参照する HCL 式を返す
+// （data ソースの場合は data.<type>.<name>.id）。
+func referenceExpression(target terraform.Resource) terraform.HCLExpression {
+	return attributeReferenceExpression(target, referenceAttribute(target.Type))
+}
+
+// attributeReferenceExpression は target の attr 属性を参照する HCL 式を返す。
+func attributeReferenceExpression(target terraform.Resource, attr string) terraform.HCLExpression {
+	if target.IsDataSource() {
+		return terraform.HCLExpression(fmt.Sprintf("data.%s.%s.%s", target.Type, target.Name, attr))
+	}
+	return terraform.HCLExpression(fmt.Sprintf("%s.%s.%s", target.Type, target.Name, attr))
+}
+
+// referenceAttribute は resourceType のリソースを参照する際の属性名を返す（既定は id）。
+func referenceAttribute(resourceType string) string {
+	if attr, ok := referenceAttributes[resourceType]; ok {
+		return attr
+	}
+	return "id"
+}
+
+// resolveIDList は ID 一覧の各要素を、refs に対応する参照式があれば置き換え、
+// なければ文字列リテラルのまま残した HCLExpression 一覧を返す（順序は維持する）。
+// ディスカバリ対象外（別 VPC の SG 等）の ID が混在していても値が欠落しないようにするため。
+func resolveIDList(val any, refs map[string]terraform.HCLExpression) any {
+	ids, ok := val.([]string)
+	if !ok {
+		return val
+	}
+	exprs := make([]terraform.HCLExpression, 0, len(ids))
+	for _, id := range ids {
+		if expr, ok := refs[id]; ok {
+			exprs = append(exprs, expr)
+			continue
+		}
+		exprs = append(exprs, terraform.HCLExpression(hclStringLiteral(id)))
+	}
+	return exprs
 }
 
 // buildHCLAttributeLine は 1 つの属性から HCL の 1 行を生成する。
 func buildHCLAttributeLine(key string, val any) string {
 	switch v := val.(type) {
 	case string:
-		return fmt.Sprintf("%s = %q", key, v)
+		return fmt.Sprintf("%s = %s", key, hclStringLiteral(v))
 	case bool:
 		if v {
 			return fmt.Sprintf("%s = true", key)
@@ -269,7 +918,7 @@ func buildHCLAttributeLine(key string, val any) string {
 	case []string:
 		var parts []string
 		for _, s := range v {
-			parts = append(parts, fmt.Sprintf("%q", s))
+			parts = append(parts, hclStringLiteral(s))
 		}
 		return fmt.Sprintf("%s = [%s]", key, strings.Join(parts, ", "))
 	case []terraform.HCLExpression:
@@ -0,0 +1,295 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ukms/archaeform/pkg/terraform"
+)
+
+const (
+	testRegion  = "ap-northeast-1"
+	testVpcID   = "vpc-1"
+	testAccount = "111122223333"
+)
+
+// mapped は mapper の結果を集約する。
+type mapped struct {
+	resources []terraform.Resource
+	relations []terraform.Relation
+}
+
+func (m *mapped) add(t *testing.T) func([]terraform.Resource, []terraform.Relation, error) {
+	return func(rs []terraform.Resource, rels []terraform.Relation, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected mapping error: %v", err)
+		}
+		m.resources = append(m.resources, rs...)
+		m.relations = append(m.relations, rels...)
+	}
+}
+
+// TestGenerate は mapper の出力から生成した HCL に、参照式・jsonencode()・ヒアドキュメント・変数・provider が
+// 出力され、照合用の属性（読み取り専用の arn 等）が出力されないことを確認する。
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name string
+		// build は mapper で Resource / Relation を生成する
+		build func(t *testing.T, m *terraform.AwsToResourceMapper, out *mapped)
+		cfg   HclGenerationConfig
+		// want はファイル名ごとに含まれるべき行（前後の空白は無視する）
+		want map[string][]string
+		// absent はファイル名ごとに含まれてはならない行の接頭辞
+		absent map[string][]string
+	}{
+		{
+			name: "ecs",
+			build: func(t *testing.T, m *terraform.AwsToResourceMapper, out *mapped) {
+				taskDefArn := "arn:aws:ecs:" + testRegion + ":" + testAccount + ":task-definition/web:3"
+				out.add(t)(m.MapEcsCluster([]terraform.RawEcsCluster{{
+					Arn:               "arn:aws:ecs:" + testRegion + ":" + testAccount + ":cluster/web",
+					Name:              "web",
+					VpcID:             testVpcID,
+					CapacityProviders: []string{"FARGATE"},
+				}}, nil, testRegion))
+				out.add(t)(m.MapEcsService([]terraform.RawEcsService{{
+					Arn:               "arn:aws:ecs:" + testRegion + ":" + testAccount + ":service/web/web",
+					Name:              "web",
+					ClusterName:       "web",
+					VpcID:             testVpcID,
+					TaskDefinitionArn: taskDefArn,
+					DesiredCount:      2,
+					LaunchType:        "FARGATE",
+				}}, []terraform.RawEcsTaskDefinition{{
+					Arn:                  taskDefArn,
+					Family:               "web",
+					Revision:             3,
+					VpcID:                testVpcID,
+					ContainerDefinitions: `[{"name":"app","image":"nginx","essential":true}]`,
+					ExecutionRoleArn:     "arn:aws:iam::" + testAccount + ":role/ecs-exec",
+				}}, testRegion))
+				out.add(t)(m.MapIamRole([]terraform.RawIamRole{{
+					Name:             "ecs-exec",
+					Arn:              "arn:aws:iam::" + testAccount + ":role/ecs-exec",
+					AssumeRolePolicy: `{"Version":"2012-10-17","Statement":[]}`,
+				}}, nil, testRegion, testVpcID))
+			},
+			want: map[string][]string{
+				"aws_ecs_cluster.tf":                    {`resource "aws_ecs_cluster" "web" {`, `name = "web"`},
+				"aws_ecs_cluster_capacity_providers.tf": {`cluster_name = aws_ecs_cluster.web.name`},
+				"aws_ecs_service.tf":                    {`cluster = aws_ecs_cluster.web.name`, `task_definition = aws_ecs_task_definition.web.arn`},
+				"aws_ecs_task_definition.tf": {
+					`container_definitions = jsonencode([`,
+					`image = "nginx"`,
+					`execution_role_arn = aws_iam_role.ecs_exec.arn`,
+				},
+			},
+			absent: map[string][]string{
+				"aws_ecs_cluster.tf":         {"arn ="},
+				"aws_ecs_task_definition.tf": {"arn ="},
+				"aws_iam_role.tf":            {"arn ="},
+			},
+		},
+		{
+			name: "iam",
+			build: func(t *testing.T, m *terraform.AwsToResourceMapper, out *mapped) {
+				out.add(t)(m.MapIamRole([]terraform.RawIamRole{{
+					Name:             "app",
+					Arn:              "arn:aws:iam::" + testAccount + ":role/app",
+					AssumeRolePolicy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}]}`,
+					InlinePolicies: []terraform.RawIamRolePolicy{{
+						Name:   "s3",
+						Policy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+					}},
+				}}, []terraform.RawIamInstanceProfile{{
+					Name:     "app",
+					Arn:      "arn:aws:iam::" + testAccount + ":instance-profile/app",
+					RoleName: "app",
+				}}, testRegion, testVpcID))
+				out.add(t)(m.MapInstance([]terraform.RawInstance{{
+					ID:                 "i-web",
+					Ami:                "ami-1",
+					InstanceType:       "t3.micro",
+					VpcID:              testVpcID,
+					SubnetID:           "subnet-a",
+					IamInstanceProfile: "app",
+				}}, testRegion))
+			},
+			want: map[string][]string{
+				"aws_iam_role.tf": {
+					`resource "aws_iam_role" "app" {`,
+					`assume_role_policy = jsonencode({`,
+					`Service = "ec2.amazonaws.com"`,
+				},
+				"aws_iam_role_policy.tf":      {`role = aws_iam_role.app.id`, `policy = jsonencode({`},
+				"aws_iam_instance_profile.tf": {`role = aws_iam_role.app.id`},
+				"aws_instance.tf":             {`iam_instance_profile = aws_iam_instance_profile.app.id`},
+			},
+			absent: map[string][]string{
+				"aws_iam_role.tf":             {"arn ="},
+				"aws_iam_instance_profile.tf": {"arn ="},
+			},
+		},
+		{
+			name: "kms",
+			build: func(t *testing.T, m *terraform.AwsToResourceMapper, out *mapped) {
+				keyArn := "arn:aws:kms:" + testRegion + ":" + testAccount + ":key/key-app"
+				managedArn := "arn:aws:kms:" + testRegion + ":" + testAccount + ":key/key-rds"
+				out.add(t)(m.MapKmsKey([]terraform.RawKmsKey{
+					{
+						KeyID:    "key-app",
+						Arn:      keyArn,
+						Enabled:  true,
+						KeyUsage: "ENCRYPT_DECRYPT",
+						Policy:   `{"Version":"2012-10-17","Statement":[]}`,
+						Aliases:  []terraform.RawKmsAlias{{Name: "alias/app", Arn: "arn:aws:kms:" + testRegion + ":" + testAccount + ":alias/app"}},
+					},
+					{
+						KeyID:      "key-rds",
+						Arn:        managedArn,
+						AwsManaged: true,
+						Enabled:    true,
+						Aliases:    []terraform.RawKmsAlias{{Name: "alias/aws/rds", Arn: "arn:aws:kms:" + testRegion + ":" + testAccount + ":alias/aws/rds"}},
+						Referrers:  []terraform.RawKmsReferrer{{ResourceID: "aws:aws_cloudwatch_log_group:/rds/db"}},
+					},
+				}, testRegion, testVpcID))
+				out.add(t)(m.MapCloudWatchLogGroup([]terraform.RawCloudWatchLogGroup{
+					{Name: "/app", Arn: "arn:aws:logs:" + testRegion + ":" + testAccount + ":log-group:/app", KmsKeyID: keyArn},
+					{Name: "/rds/db", Arn: "arn:aws:logs:" + testRegion + ":" + testAccount + ":log-group:/rds/db", KmsKeyID: managedArn},
+				}, testRegion, testVpcID))
+			},
+			want: map[string][]string{
+				"aws_kms_key.tf": {`policy = jsonencode({`, `key_usage = "ENCRYPT_DECRYPT"`},
+				"aws_kms_alias.tf": {
+					`resource "aws_kms_alias" "app" {`,
+					`target_key_id = aws_kms_key.app.id`,
+					`data "aws_kms_alias" "aws_rds" {`,
+					`name = "alias/aws/rds"`,
+				},
+				"aws_cloudwatch_log_group.tf": {
+					`kms_key_id = aws_kms_key.app.arn`,
+					`kms_key_id = data.aws_kms_alias.aws_rds.target_key_arn`,
+				},
+			},
+			absent: map[string][]string{
+				"aws_kms_key.tf":              {"arn ="},
+				"aws_kms_alias.tf":            {"arn =", "target_key_arn ="},
+				"aws_cloudwatch_log_group.tf": {"arn ="},
+			},
+		},
+		{
+			name: "codebuild buildspec",
+			build: func(t *testing.T, m *terraform.AwsToResourceMapper, out *mapped) {
+				out.add(t)(m.MapCodeBuildProject([]terraform.RawCodeBuildProject{{
+					Name:            "ci",
+					Arn:             "arn:aws:codebuild:" + testRegion + ":" + testAccount + ":project/ci",
+					VpcID:           testVpcID,
+					ServiceRole:     "arn:aws:iam::" + testAccount + ":role/ci",
+					SourceType:      "NO_SOURCE",
+					Buildspec:       "version: 0.2\nphases:\n  build:\n    commands:\n      - echo ${FOO}\n",
+					ArtifactsType:   "NO_ARTIFACTS",
+					EnvironmentType: "LINUX_CONTAINER",
+					ComputeType:     "BUILD_GENERAL1_SMALL",
+					Image:           "aws/codebuild/standard:7.0",
+				}}, testRegion))
+			},
+			want: map[string][]string{
+				"aws_codebuild_project.tf": {`buildspec = <<EOT`, `- echo $${FOO}`, `EOT`},
+			},
+			absent: map[string][]string{
+				"aws_codebuild_project.tf": {"arn ="},
+			},
+		},
+		{
+			name: "variables and providers",
+			build: func(t *testing.T, m *terraform.AwsToResourceMapper, out *mapped) {
+				out.add(t)(m.MapLambdaFunction([]terraform.RawLambdaFunction{{
+					Name:        "api",
+					Arn:         "arn:aws:lambda:" + testRegion + ":" + testAccount + ":function:api",
+					VpcID:       testVpcID,
+					Role:        "arn:aws:iam::" + testAccount + ":role/api",
+					PackageType: "Zip",
+					Runtime:     "python3.12",
+					Handler:     "app.handler",
+					MemorySize:  128,
+					Timeout:     3,
+				}}, nil, testRegion))
+			},
+			cfg: HclGenerationConfig{
+				ProviderAliasPerRegion: true,
+				AssumeRoles:            []terraform.AssumeRole{{RoleArn: "arn:aws:iam::" + testAccount + ":role/importer", SessionName: "import"}},
+			},
+			want: map[string][]string{
+				"aws_lambda_function.tf": {`provider = aws.ap_northeast_1`, `filename = var.lambda_function_api_filename`},
+				"variables.tf":           {`variable "lambda_function_api_filename" {`, `type = string`},
+				"providers.tf": {
+					`alias  = "ap_northeast_1"`,
+					`region = "ap-northeast-1"`,
+					`role_arn     = "arn:aws:iam::111122223333:role/importer"`,
+					`session_name = "import"`,
+				},
+			},
+			absent: map[string][]string{
+				"aws_lambda_function.tf": {"arn ="},
+				"variables.tf":           {"sensitive"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out mapped
+			tt.build(t, terraform.NewAwsToResourceMapper(nil), &out)
+
+			cfg := tt.cfg
+			cfg.OutputDir = t.TempDir()
+			if _, err := NewHclGenerator().Generate(out.resources, out.relations, cfg); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for file, lines := range tt.want {
+				got := readGeneratedLines(t, filepath.Join(cfg.OutputDir, file))
+				for _, line := range lines {
+					if !containsLine(got, line) {
+						t.Errorf("%s: missing %q in:\n%s", file, line, strings.Join(got, "\n"))
+					}
+				}
+			}
+			for file, prefixes := range tt.absent {
+				for _, line := range readGeneratedLines(t, filepath.Join(cfg.OutputDir, file)) {
+					for _, prefix := range prefixes {
+						if strings.HasPrefix(line, prefix) {
+							t.Errorf("%s: unexpected %q", file, line)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// readGeneratedLines は生成されたファイルを前後の空白を除いた行の一覧として返す。
+func readGeneratedLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	return lines
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
@@ -63,8 +63,12 @@ func (g *ImportCommandGenerator) GenerateImportScript(resources []terraform.Reso
 	b.WriteString("# Generated terraform import commands\n")
 
 	for _, r := range resources {
+		// data ソースは import できない
+		if r.IsDataSource() {
+			continue
+		}
 		address := fmt.Sprintf("%s.%s", r.Type, r.Name)
-		importID, ok := resolveImportID(r)
+		importID, ok := ResolveImportID(r)
 		if !ok {
 			// import ID が取れない場合はスキップ
 			continue
@@ -84,9 +88,10 @@ func (g *ImportCommandGenerator) GenerateImportScript(resources []terraform.Reso
 	return scriptPath, nil
 }
 
-// resolveImportID は Resource から terraform import の ID を解決する。
+// ResolveImportID は Resource から terraform import の ID を解決する。
 // 初期実装では Attributes["id"] を最優先で利用し、なければ Labels["aws_id"] を試す。
-func resolveImportID(r terraform.Resource) (string, bool) {
+// --apply 時に TerraformExecutor.Import へ渡す ID の決定にも利用する。
+func ResolveImportID(r terraform.Resource) (string, bool) {
 	if r.Attributes != nil {
 		if v, ok := r.Attributes["id"]; ok {
 			if s, ok := v.(string); ok && s != "" {
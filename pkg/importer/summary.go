@@ -3,6 +3,8 @@ package importer
 import (
 	"fmt"
 	"io"
+
+	"github.com/ukms/archaeform/pkg/terraform"
 )
 
 // ImportSummary は VPC import 全体の結果サマリを表す。
@@ -14,6 +16,18 @@ type ImportSummary struct {
 	GeneratedHclFiles       int
 	GeneratedImportCommands int
 
+	// ディスカバリ（lister 単位）の実行結果
+	DiscoveryListersOK      int
+	DiscoveryListersFailed  int
+	DiscoveryListersSkipped int
+
+	// AWS API 呼び出し統計
+	APICalls     int64
+	APIRetries   int64
+	APIThrottled int64
+	// ディスカバリキャッシュから結果を取得した lister 数
+	DiscoveryCacheHits int
+
 	ApplyRequested bool
 	ApplySucceeded int
 	ApplyFailed    int
@@ -22,6 +36,33 @@ type ImportSummary struct {
 	Errors   []string
 }
 
+// AddDiscoveryReport は DiscoveryReport の内容を ImportSummary に反映する。
+// 失敗・スキップした lister は致命的エラーではなく Warnings として記録する。
+func (s *ImportSummary) AddDiscoveryReport(report terraform.DiscoveryReport) {
+	calls, retries, throttled := report.APICallTotals()
+	s.APICalls += calls
+	s.APIRetries += retries
+	s.APIThrottled += throttled
+	s.DiscoveryCacheHits += report.CacheHits()
+
+	for _, l := range report.Listers {
+		name := l.Name
+		if t := l.Target(); t != "" {
+			name = fmt.Sprintf("%s (%s)", l.Name, t)
+		}
+		switch l.Status {
+		case terraform.ListerStatusOK:
+			s.DiscoveryListersOK++
+		case terraform.ListerStatusSkipped:
+			s.DiscoveryListersSkipped++
+			s.Warnings = append(s.Warnings, fmt.Sprintf("%s was skipped: %s", name, l.Error))
+		case terraform.ListerStatusFailed:
+			s.DiscoveryListersFailed++
+			s.Warnings = append(s.Warnings, fmt.Sprintf("failed to discover %s, these resources are not imported: %s", name, l.Error))
+		}
+	}
+}
+
 // WriteText は ImportSummary を人間が読みやすいテキストとして writer に出力する。
 // 実際の CLI では os.Stdout に対して呼び出す想定。
 func (s *ImportSummary) WriteText(w io.Writer, vpcID string, region string, hclOutputDir string, importScriptPath string) error {
@@ -34,6 +75,15 @@ func (s *ImportSummary) WriteText(w io.Writer, vpcID string, region string, hclO
 		fmt.Fprintf(w, "Target VPC          : %s (%s)\n", vpcID, region)
 	}
 	fmt.Fprintf(w, "Discovered resources: %d\n", s.TotalResources)
+	if s.DiscoveryListersFailed > 0 || s.DiscoveryListersSkipped > 0 {
+		fmt.Fprintf(w, "  Listers           : %d ok, %d failed, %d skipped\n", s.DiscoveryListersOK, s.DiscoveryListersFailed, s.DiscoveryListersSkipped)
+	}
+	if s.DiscoveryCacheHits > 0 {
+		fmt.Fprintf(w, "  Cached listers    : %d (use --refresh to re-fetch from AWS)\n", s.DiscoveryCacheHits)
+	}
+	if s.APICalls > 0 {
+		fmt.Fprintf(w, "  AWS API calls     : %d (retries: %d, throttled: %d)\n", s.APICalls, s.APIRetries, s.APIThrottled)
+	}
 	fmt.Fprintf(w, "Importable          : %d\n", s.ImportableResources)
 	fmt.Fprintf(w, "Conflicted          : %d\n", s.ConflictedResources)
 	fmt.Fprintln(w)
@@ -0,0 +1,55 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultAssumeRoleSessionName は SessionName 未指定時に用いるセッション名。
+const DefaultAssumeRoleSessionName = "archaeform"
+
+// AssumeRole は sts:AssumeRole で引き受ける 1 つのロールを表す。
+// DiscoveryScope.AssumeRoles では、先頭から順に引き受けるロールチェーンとして扱う。
+type AssumeRole struct {
+	RoleArn     string `json:"roleArn"`
+	ExternalID  string `json:"externalId,omitempty"`
+	SessionName string `json:"sessionName,omitempty"`
+}
+
+// SessionNameOrDefault は SessionName が空の場合に DefaultAssumeRoleSessionName を返す。
+func (r AssumeRole) SessionNameOrDefault() string {
+	if r.SessionName != "" {
+		return r.SessionName
+	}
+	return DefaultAssumeRoleSessionName
+}
+
+// ParseAssumeRole は 1 つの --assume-role 式を AssumeRole に変換する。
+// 例: "arn:aws:iam::123456789012:role/Deploy,external-id=xyz,session-name=import"
+func ParseAssumeRole(expr string) (AssumeRole, error) {
+	parts := strings.Split(strings.TrimSpace(expr), ",")
+	role := AssumeRole{RoleArn: strings.TrimSpace(parts[0])}
+	if !strings.HasPrefix(role.RoleArn, "arn:") {
+		return AssumeRole{}, fmt.Errorf("invalid role ARN: %q", role.RoleArn)
+	}
+
+	for _, p := range parts[1:] {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(p, "=")
+		if !ok {
+			return AssumeRole{}, fmt.Errorf("invalid assume role option: %q", p)
+		}
+		switch strings.TrimSpace(key) {
+		case "external-id":
+			role.ExternalID = strings.TrimSpace(val)
+		case "session-name":
+			role.SessionName = strings.TrimSpace(val)
+		default:
+			return AssumeRole{}, fmt.Errorf("unknown assume role option: %q", p)
+		}
+	}
+	return role, nil
+}
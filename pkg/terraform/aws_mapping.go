@@ -11,17 +11,22 @@ type RawSubnet struct {
 	Name      string
 	Tags      map[string]string
 	Az        string
+
+	MapPublicIpOnLaunch bool
 }
 
 // RawInstance は EC2 インスタンス向けの中間構造体。
 // F02_internal-resource-model-mapping.md の属性例を参考にしている。
+// IamInstanceProfile はインスタンスプロファイル名（ARN ではない）。
 type RawInstance struct {
-	ID               string
-	Ami              string
-	InstanceType     string
-	SubnetID         string
-	SecurityGroupIDs []string
-	Tags             map[string]string
+	ID                 string
+	Ami                string
+	InstanceType       string
+	VpcID              string
+	SubnetID           string
+	SecurityGroupIDs   []string
+	IamInstanceProfile string
+	Tags               map[string]string
 }
 
 // CloudResourceMapper はクラウド固有の生データから共通 Resource/Relation への
@@ -82,7 +87,7 @@ func (m *AwsToResourceMapper) MapSubnet(subnets []RawSubnet, region string) ([]R
 			"vpc_id":                 s.VpcID,
 			"cidr_block":             s.CidrBlock,
 			"availability_zone":      s.Az,
-			"map_public_ip_on_launch": nil, // F-01/F-02 で必要に応じて拡張
+			"map_public_ip_on_launch": s.MapPublicIpOnLaunch,
 			"tags":                   s.Tags,
 		}
 
@@ -118,6 +123,7 @@ func (m *AwsToResourceMapper) MapSubnet(subnets []RawSubnet, region string) ([]R
 // - Relation:
 //   - instance -> subnet (network)
 //   - instance -> security_group (security) ※ SG 側の Resource.ID とは別途対応が必要
+//   - instance -> iam_instance_profile (iam)
 func (m *AwsToResourceMapper) MapInstance(instances []RawInstance, region string) ([]Resource, []Relation, error) {
 	var resources []Resource
 	var relations []Relation
@@ -136,6 +142,9 @@ func (m *AwsToResourceMapper) MapInstance(instances []RawInstance, region string
 		if region != "" {
 			labels["aws_region"] = region
 		}
+		if inst.VpcID != "" {
+			labels["vpc_id"] = inst.VpcID
+		}
 
 		id := fmt.Sprintf("aws:aws_instance:%s", inst.ID)
 
@@ -149,6 +158,9 @@ func (m *AwsToResourceMapper) MapInstance(instances []RawInstance, region string
 			"vpc_security_group_ids": inst.SecurityGroupIDs,
 			"tags":              inst.Tags,
 		}
+		if inst.IamInstanceProfile != "" {
+			attr["iam_instance_profile"] = inst.IamInstanceProfile
+		}
 
 		res := Resource{
 			ID:         id,
@@ -183,6 +195,15 @@ func (m *AwsToResourceMapper) MapInstance(instances []RawInstance, region string
 				Kind: RelationSecurity,
 			})
 		}
+
+		// インスタンスプロファイルとの iam 関係
+		if inst.IamInstanceProfile != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   fmt.Sprintf("aws:aws_iam_instance_profile:%s", inst.IamInstanceProfile),
+				Kind: RelationIAM,
+			})
+		}
 	}
 
 	return resources, relations, nil
@@ -0,0 +1,129 @@
+package terraform
+
+// RawCloudWatchLogGroup は VPC 内のリソースのログの出力先ロググループ向けの中間構造体。
+type RawCloudWatchLogGroup struct {
+	Name string
+	Arn  string
+	// RetentionInDays はログの保持期間（日）。0 の場合は無期限。
+	RetentionInDays int32
+	KmsKeyID        string
+	LogGroupClass   string // "STANDARD" / "INFREQUENT_ACCESS"
+	Tags            map[string]string
+}
+
+// RawCloudWatchMetricAlarm は VPC 内のリソースを監視する CloudWatch メトリクスアラーム向けの中間構造体。
+type RawCloudWatchMetricAlarm struct {
+	Name               string
+	Arn                string
+	Description        string
+	Namespace          string
+	MetricName         string
+	Statistic          string
+	ExtendedStatistic  string
+	ComparisonOperator string
+	Threshold          float64
+	EvaluationPeriods  int32
+	DatapointsToAlarm  int32
+	Period             int32
+	Unit               string
+	// TreatMissingData は欠落データの扱い。既定値（"missing"）の場合は出力しない。
+	TreatMissingData        string
+	ActionsEnabled          bool
+	AlarmActions            []string
+	OKActions               []string
+	InsufficientDataActions []string
+	Dimensions              map[string]string
+	// Targets はディメンションが指している監視対象の Resource ID。
+	Targets []string
+	Tags    map[string]string
+}
+
+// MapCloudWatchLogGroup は RawCloudWatchLogGroup 一覧から Resource / Relation を生成する。
+// 参照元（Lambda・ECS タスク定義・CodeBuild・フローログ・RDS）-> cloudwatch_log_group の関係は各リソースの mapper が出力する。
+// - Type: aws_cloudwatch_log_group
+// - Relation: cloudwatch_log_group -> kms_key (encryption)
+func (m *AwsToResourceMapper) MapCloudWatchLogGroup(groups []RawCloudWatchLogGroup, region string, vpcID string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, g := range groups {
+		attr := map[string]any{
+			"id":   g.Name,
+			"name": g.Name,
+			"arn":  g.Arn,
+			"tags": g.Tags,
+		}
+		if g.RetentionInDays != 0 {
+			attr["retention_in_days"] = g.RetentionInDays
+		}
+		setNonEmpty(attr, map[string]string{"kms_key_id": g.KmsKeyID})
+		if g.LogGroupClass != "" && g.LogGroupClass != "STANDARD" {
+			attr["log_group_class"] = g.LogGroupClass
+		}
+
+		res := m.newNamedAwsResource("aws_cloudwatch_log_group", g.Name, newAwsLabels(g.Tags, region, vpcID), nameLabelsOr(g.Tags, g.Name), attr)
+		resources = append(resources, res)
+		if to := kmsKeyResourceID(g.KmsKeyID); to != "" {
+			relations = append(relations, Relation{From: res.ID, To: to, Kind: RelationEncryption})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// MapCloudWatchMetricAlarm は RawCloudWatchMetricAlarm 一覧から Resource / Relation を生成する。
+// import ID はアラーム名。
+// - Type: aws_cloudwatch_metric_alarm
+// - Relation: cloudwatch_metric_alarm -> 監視対象（instance / lb / lb_target_group / db_instance / rds_cluster など）(monitoring)
+func (m *AwsToResourceMapper) MapCloudWatchMetricAlarm(alarms []RawCloudWatchMetricAlarm, region string, vpcID string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, a := range alarms {
+		attr := map[string]any{
+			"id":                  a.Name,
+			"alarm_name":          a.Name,
+			"arn":                 a.Arn,
+			"namespace":           a.Namespace,
+			"metric_name":         a.MetricName,
+			"comparison_operator": a.ComparisonOperator,
+			"threshold":           a.Threshold,
+			"evaluation_periods":  a.EvaluationPeriods,
+			"period":              a.Period,
+			"actions_enabled":     a.ActionsEnabled,
+			"tags":                a.Tags,
+		}
+		setNonEmpty(attr, map[string]string{
+			"alarm_description":  a.Description,
+			"statistic":          a.Statistic,
+			"extended_statistic": a.ExtendedStatistic,
+			"unit":               a.Unit,
+		})
+		if a.DatapointsToAlarm != 0 {
+			attr["datapoints_to_alarm"] = a.DatapointsToAlarm
+		}
+		if a.TreatMissingData != "" && a.TreatMissingData != "missing" {
+			attr["treat_missing_data"] = a.TreatMissingData
+		}
+		if len(a.Dimensions) > 0 {
+			attr["dimensions"] = a.Dimensions
+		}
+		for key, actions := range map[string][]string{
+			"alarm_actions":             a.AlarmActions,
+			"ok_actions":                a.OKActions,
+			"insufficient_data_actions": a.InsufficientDataActions,
+		} {
+			if len(actions) > 0 {
+				attr[key] = actions
+			}
+		}
+
+		res := m.newNamedAwsResource("aws_cloudwatch_metric_alarm", a.Name, newAwsLabels(a.Tags, region, vpcID), nameLabelsOr(a.Tags, a.Name), attr)
+		resources = append(resources, res)
+		for _, to := range a.Targets {
+			relations = append(relations, Relation{From: res.ID, To: to, Kind: RelationMonitoring})
+		}
+	}
+
+	return resources, relations, nil
+}
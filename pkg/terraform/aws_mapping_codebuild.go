@@ -0,0 +1,229 @@
+package terraform
+
+import "strings"
+
+// RawCodeBuildProject は VPC に接続された CodeBuild プロジェクト向けの中間構造体。
+type RawCodeBuildProject struct {
+	Name                 string
+	Arn                  string
+	VpcID                string
+	Description          string
+	ServiceRole          string
+	BuildTimeoutMinutes  int32
+	QueuedTimeoutMinutes int32
+	ConcurrentBuildLimit int32
+	EncryptionKey        string
+	SourceType           string
+	SourceLocation       string
+	// Buildspec はインラインの buildspec（YAML）またはリポジトリ内の buildspec ファイルのパス。
+	Buildspec              string
+	GitCloneDepth          int32
+	ArtifactsType          string
+	ArtifactsLocation      string
+	ArtifactsName          string
+	ArtifactsPath          string
+	ArtifactsPackaging     string
+	ArtifactsNamespaceType string
+	EnvironmentType        string
+	ComputeType            string
+	Image                  string
+	ImagePullCredentials   string
+	PrivilegedMode         bool
+	EnvironmentVariables   []RawCodeBuildEnvironmentVariable
+	CacheType              string
+	CacheLocation          string
+	CacheModes             []string
+	// CloudWatchLogsStatus が "DISABLED" の場合はロググループへの関係を出力しない。
+	CloudWatchLogsStatus string
+	LogGroupName         string
+	LogStreamName        string
+	S3LogsStatus         string
+	S3LogsLocation       string
+	SubnetIDs            []string
+	SecurityGroupIDs     []string
+	Tags                 map[string]string
+}
+
+// RawCodeBuildEnvironmentVariable はビルド環境の環境変数。
+type RawCodeBuildEnvironmentVariable struct {
+	Name  string
+	Value string
+	Type  string // "PLAINTEXT" / "PARAMETER_STORE" / "SECRETS_MANAGER"
+}
+
+// MapCodeBuildProject は RawCodeBuildProject 一覧から Resource / Relation を生成する。
+// インラインの buildspec は生成時にヒアドキュメントとして出力される。
+// - Type: aws_codebuild_project
+// - Relation: codebuild_project -> vpc / subnet (network), codebuild_project -> security_group (security)
+// - Relation: codebuild_project -> iam_role（サービスロール）(iam), codebuild_project -> kms_key (encryption)
+// - Relation: codebuild_project -> ecr_repository（ビルドイメージ）(artifact), codebuild_project -> s3_bucket（ソース・アーティファクト・キャッシュ・ログ）(storage)
+// - Relation: codebuild_project -> cloudwatch_log_group (monitoring), codebuild_project -> ssm_parameter / secretsmanager_secret (secret)
+func (m *AwsToResourceMapper) MapCodeBuildProject(projects []RawCodeBuildProject, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, p := range projects {
+		attr := map[string]any{
+			"id":           p.Name,
+			"name":         p.Name,
+			"arn":          p.Arn,
+			"service_role": p.ServiceRole,
+			"tags":         p.Tags,
+		}
+		setNonEmpty(attr, map[string]string{
+			"description":    p.Description,
+			"encryption_key": p.EncryptionKey,
+		})
+		for key, val := range map[string]int32{
+			"build_timeout":          p.BuildTimeoutMinutes,
+			"queued_timeout":         p.QueuedTimeoutMinutes,
+			"concurrent_build_limit": p.ConcurrentBuildLimit,
+		} {
+			if val != 0 {
+				attr[key] = val
+			}
+		}
+
+		source := stringMapBlock(map[string]string{
+			"type":      p.SourceType,
+			"location":  p.SourceLocation,
+			"buildspec": p.Buildspec,
+		})
+		if p.GitCloneDepth != 0 {
+			source["git_clone_depth"] = p.GitCloneDepth
+		}
+		attr["source"] = []HCLBlock{source}
+
+		attr["artifacts"] = []HCLBlock{stringMapBlock(map[string]string{
+			"type":           p.ArtifactsType,
+			"location":       p.ArtifactsLocation,
+			"name":           p.ArtifactsName,
+			"path":           p.ArtifactsPath,
+			"packaging":      p.ArtifactsPackaging,
+			"namespace_type": p.ArtifactsNamespaceType,
+		})}
+
+		env := stringMapBlock(map[string]string{
+			"type":                        p.EnvironmentType,
+			"compute_type":                p.ComputeType,
+			"image":                       p.Image,
+			"image_pull_credentials_type": p.ImagePullCredentials,
+		})
+		env["privileged_mode"] = p.PrivilegedMode
+		var envVars []HCLBlock
+		for _, v := range p.EnvironmentVariables {
+			envVars = append(envVars, HCLBlock{"name": v.Name, "value": v.Value, "type": v.Type})
+		}
+		if len(envVars) > 0 {
+			env["environment_variable"] = envVars
+		}
+		attr["environment"] = []HCLBlock{env}
+
+		if p.CacheType != "" && p.CacheType != "NO_CACHE" {
+			cache := stringMapBlock(map[string]string{"type": p.CacheType, "location": p.CacheLocation})
+			if len(p.CacheModes) > 0 {
+				cache["modes"] = p.CacheModes
+			}
+			attr["cache"] = []HCLBlock{cache}
+		}
+
+		logs := HCLBlock{}
+		if p.CloudWatchLogsStatus != "" {
+			logs["cloudwatch_logs"] = []HCLBlock{stringMapBlock(map[string]string{
+				"status":      p.CloudWatchLogsStatus,
+				"group_name":  p.LogGroupName,
+				"stream_name": p.LogStreamName,
+			})}
+		}
+		if p.S3LogsStatus != "" {
+			logs["s3_logs"] = []HCLBlock{stringMapBlock(map[string]string{
+				"status":   p.S3LogsStatus,
+				"location": p.S3LogsLocation,
+			})}
+		}
+		if len(logs) > 0 {
+			attr["logs_config"] = []HCLBlock{logs}
+		}
+
+		attr["vpc_config"] = []HCLBlock{{
+			"vpc_id":             p.VpcID,
+			"subnets":            p.SubnetIDs,
+			"security_group_ids": p.SecurityGroupIDs,
+		}}
+
+		res := m.newNamedAwsResource("aws_codebuild_project", p.Name, newAwsLabels(p.Tags, region, p.VpcID), nameLabelsOr(p.Tags, p.Name), attr)
+		resources = append(resources, res)
+		relations = append(relations, codeBuildRelations(res.ID, p)...)
+	}
+
+	return resources, relations, nil
+}
+
+// codeBuildRelations はプロジェクトから VPC・IAM ロール・ECR・S3 バケット・ロググループ等への Relation を返す。
+func codeBuildRelations(from string, p RawCodeBuildProject) []Relation {
+	relations := []Relation{{From: from, To: awsResourceID("aws_vpc", p.VpcID), Kind: RelationNetwork}}
+	for _, id := range p.SubnetIDs {
+		relations = append(relations, Relation{From: from, To: awsResourceID("aws_subnet", id), Kind: RelationNetwork})
+	}
+	for _, id := range p.SecurityGroupIDs {
+		relations = append(relations, Relation{From: from, To: awsResourceID("aws_security_group", id), Kind: RelationSecurity})
+	}
+	relations = append(relations, iamRoleRelations(from, p.ServiceRole)...)
+	if to := kmsKeyResourceID(p.EncryptionKey); to != "" {
+		relations = append(relations, Relation{From: from, To: to, Kind: RelationEncryption})
+	}
+	if repo := ecrRepositoryNameFromImage(p.Image); repo != "" {
+		relations = append(relations, Relation{From: from, To: awsResourceID("aws_ecr_repository", repo), Kind: RelationArtifact})
+	}
+
+	var buckets []string
+	if p.SourceType == "S3" {
+		buckets = appendUniqueString(buckets, s3BucketFromLocation(p.SourceLocation))
+	}
+	if p.ArtifactsType == "S3" {
+		buckets = appendUniqueString(buckets, s3BucketFromLocation(p.ArtifactsLocation))
+	}
+	if p.CacheType == "S3" {
+		buckets = appendUniqueString(buckets, s3BucketFromLocation(p.CacheLocation))
+	}
+	if p.S3LogsStatus == "ENABLED" {
+		buckets = appendUniqueString(buckets, s3BucketFromLocation(p.S3LogsLocation))
+	}
+	for _, bucket := range buckets {
+		relations = append(relations, Relation{From: from, To: awsResourceID("aws_s3_bucket", bucket), Kind: RelationStorage})
+	}
+
+	if p.CloudWatchLogsStatus != "DISABLED" {
+		group := p.LogGroupName
+		if group == "" {
+			group = "/aws/codebuild/" + p.Name
+		}
+		relations = append(relations, Relation{From: from, To: awsResourceID("aws_cloudwatch_log_group", group), Kind: RelationMonitoring})
+	}
+	for _, v := range p.EnvironmentVariables {
+		if v.Type == "PARAMETER_STORE" || v.Type == "SECRETS_MANAGER" {
+			relations = append(relations, Relation{From: from, To: secretResourceID(v.Value), Kind: RelationSecret})
+		}
+	}
+	return relations
+}
+
+// s3BucketFromLocation は S3 のロケーション（"<bucket>/<key>" または "arn:aws:s3:::<bucket>/<key>"）からバケット名を返す。
+func s3BucketFromLocation(location string) string {
+	location = strings.TrimPrefix(location, "arn:aws:s3:::")
+	bucket, _, _ := strings.Cut(location, "/")
+	return bucket
+}
+
+// appendUniqueString は s が空でなく list に含まれない場合のみ追加する。
+func appendUniqueString(list []string, s string) []string {
+	if s == "" {
+		return list
+	}
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,523 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RawEcsCluster は ECS クラスター向けの中間構造体。
+type RawEcsCluster struct {
+	Arn  string
+	Name string
+	// VpcID はクラスターのサービスが配置された VPC（ラベル用）。
+	VpcID string
+	// ContainerInsights は containerInsights 設定の値（"enabled" / "disabled" / "enhanced"）。空の場合は出力しない。
+	ContainerInsights string
+	// CapacityProviders はクラスターに関連付けられたキャパシティプロバイダー名（FARGATE / FARGATE_SPOT を含む）。
+	CapacityProviders []string
+	DefaultStrategy   []RawEcsCapacityProviderStrategy
+	Tags              map[string]string
+}
+
+// RawEcsCapacityProviderStrategy はキャパシティプロバイダー戦略の 1 要素。
+type RawEcsCapacityProviderStrategy struct {
+	CapacityProvider string
+	Base             int32
+	Weight           int32
+}
+
+// RawEcsCapacityProvider は Auto Scaling グループを使うカスタムのキャパシティプロバイダー向けの中間構造体。
+type RawEcsCapacityProvider struct {
+	Arn                          string
+	Name                         string
+	VpcID                        string
+	AutoScalingGroupArn          string
+	ManagedTerminationProtection string // "ENABLED" / "DISABLED"
+	ManagedScalingStatus         string // "ENABLED" / "DISABLED"
+	TargetCapacity               int32
+	MinimumScalingStepSize       int32
+	MaximumScalingStepSize       int32
+	Tags                         map[string]string
+}
+
+// RawEcsService は ECS サービス向けの中間構造体。awsvpc ネットワークモードで VPC 内のサブネットに配置されたものを対象とする。
+type RawEcsService struct {
+	Arn                             string
+	Name                            string
+	ClusterName                     string
+	VpcID                           string
+	TaskDefinitionArn               string
+	DesiredCount                    int32
+	LaunchType                      string
+	PlatformVersion                 string
+	SchedulingStrategy              string
+	CapacityProviderStrategy        []RawEcsCapacityProviderStrategy
+	SubnetIDs                       []string
+	SecurityGroupIDs                []string
+	AssignPublicIP                  bool
+	LoadBalancers                   []RawEcsLoadBalancer
+	ServiceRegistries               []RawEcsServiceRegistry
+	RoleArn                         string
+	EnableExecuteCommand            bool
+	EnableEcsManagedTags            bool
+	PropagateTags                   string
+	HealthCheckGracePeriodSeconds   int32
+	DeploymentMaximumPercent        int32
+	DeploymentMinimumHealthyPercent int32
+	CircuitBreakerEnable            bool
+	CircuitBreakerRollback          bool
+	// TaskRoleArn はサービスが利用するタスク定義のタスクロール（Relation 用）。
+	TaskRoleArn string
+	Tags        map[string]string
+}
+
+// RawEcsLoadBalancer はサービスとターゲットグループの紐付け。
+type RawEcsLoadBalancer struct {
+	TargetGroupArn string
+	ContainerName  string
+	ContainerPort  int32
+}
+
+// RawEcsServiceRegistry はサービスと Cloud Map（サービスディスカバリ）のサービスの紐付け。
+type RawEcsServiceRegistry struct {
+	RegistryArn   string
+	ContainerName string
+	ContainerPort int32
+	Port          int32
+}
+
+// RawEcsTaskDefinition はサービスが利用している（アクティブな）タスク定義のリビジョン向けの中間構造体。
+type RawEcsTaskDefinition struct {
+	Arn      string
+	Family   string
+	Revision int32
+	VpcID    string
+	// ContainerDefinitions は RegisterTaskDefinition と同じ形式（camelCase）の JSON 文字列。
+	// HCL 上では jsonencode() の式として出力する。
+	ContainerDefinitions    string
+	Containers              []RawEcsContainer
+	Cpu                     string
+	Memory                  string
+	NetworkMode             string
+	RequiresCompatibilities []string
+	TaskRoleArn             string
+	ExecutionRoleArn        string
+	CpuArchitecture         string
+	OperatingSystemFamily   string
+	Volumes                 []RawEcsVolume
+	Tags                    map[string]string
+}
+
+// RawEcsContainer はコンテナ定義のうち、Relation の導出に利用する値。
+type RawEcsContainer struct {
+	Name  string
+	Image string
+	// LogGroup は awslogs ログドライバーの出力先ロググループ名。
+	LogGroup string
+	// SecretRefs は secrets / repositoryCredentials が参照する Secrets Manager / SSM パラメータの ARN（または名前）。
+	SecretRefs []string
+}
+
+// RawEcsVolume はタスク定義のボリューム。
+type RawEcsVolume struct {
+	Name                 string
+	HostPath             string
+	EfsFileSystemID      string
+	EfsRootDirectory     string
+	EfsTransitEncryption string
+	EfsAccessPointID     string
+}
+
+// MapEcsCluster は RawEcsCluster / RawEcsCapacityProvider 一覧から Resource / Relation を生成する。
+// キャパシティプロバイダーを持つクラスターは aws_ecs_cluster_capacity_providers も出力する。
+// - Type: aws_ecs_cluster, aws_ecs_cluster_capacity_providers, aws_ecs_capacity_provider
+// - Relation: ecs_cluster_capacity_providers -> ecs_cluster / ecs_capacity_provider (depends_on)
+// - Relation: ecs_capacity_provider -> autoscaling_group (depends_on)
+func (m *AwsToResourceMapper) MapEcsCluster(clusters []RawEcsCluster, providers []RawEcsCapacityProvider, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, p := range providers {
+		asg := HCLBlock{"auto_scaling_group_arn": p.AutoScalingGroupArn}
+		if p.ManagedTerminationProtection != "" {
+			asg["managed_termination_protection"] = p.ManagedTerminationProtection
+		}
+		if p.ManagedScalingStatus != "" {
+			scaling := HCLBlock{"status": p.ManagedScalingStatus}
+			for key, val := range map[string]int32{
+				"target_capacity":           p.TargetCapacity,
+				"minimum_scaling_step_size": p.MinimumScalingStepSize,
+				"maximum_scaling_step_size": p.MaximumScalingStepSize,
+			} {
+				if val != 0 {
+					scaling[key] = val
+				}
+			}
+			asg["managed_scaling"] = []HCLBlock{scaling}
+		}
+		attr := map[string]any{
+			"id":                          p.Name,
+			"name":                        p.Name,
+			"arn":                         p.Arn,
+			"auto_scaling_group_provider": []HCLBlock{asg},
+			"tags":                        p.Tags,
+		}
+
+		res := m.newNamedAwsResource("aws_ecs_capacity_provider", p.Name, newAwsLabels(p.Tags, region, p.VpcID), nameLabelsOr(p.Tags, p.Name), attr)
+		resources = append(resources, res)
+		if name := autoScalingGroupNameFromArn(p.AutoScalingGroupArn); name != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_autoscaling_group", name),
+				Kind: RelationDependsOn,
+			})
+		}
+	}
+
+	for _, c := range clusters {
+		labels := newAwsLabels(c.Tags, region, c.VpcID)
+		attr := map[string]any{
+			"id":   c.Name,
+			"name": c.Name,
+			"arn":  c.Arn,
+			"tags": c.Tags,
+		}
+		if c.ContainerInsights != "" {
+			attr["setting"] = []HCLBlock{{"name": "containerInsights", "value": c.ContainerInsights}}
+		}
+
+		res := m.newNamedAwsResource("aws_ecs_cluster", c.Name, labels, nameLabelsOr(c.Tags, c.Name), attr)
+		resources = append(resources, res)
+
+		if len(c.CapacityProviders) == 0 {
+			continue
+		}
+		cpAttr := map[string]any{
+			"id":                 c.Name,
+			"cluster_name":       c.Name,
+			"capacity_providers": c.CapacityProviders,
+		}
+		if len(c.DefaultStrategy) > 0 {
+			cpAttr["default_capacity_provider_strategy"] = ecsStrategyBlocks(c.DefaultStrategy)
+		}
+		cpRes := m.newNamedAwsResource("aws_ecs_cluster_capacity_providers", c.Name, labels, nameLabelsOr(c.Tags, c.Name), cpAttr)
+		resources = append(resources, cpRes)
+		relations = append(relations, Relation{From: cpRes.ID, To: res.ID, Kind: RelationDependsOn})
+		for _, name := range c.CapacityProviders {
+			if isFargateCapacityProvider(name) {
+				continue
+			}
+			relations = append(relations, Relation{
+				From: cpRes.ID,
+				To:   awsResourceID("aws_ecs_capacity_provider", name),
+				Kind: RelationDependsOn,
+			})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// MapEcsService は RawEcsService / RawEcsTaskDefinition 一覧から Resource / Relation を生成する。
+// サービスの import ID は "<クラスター名>/<サービス名>"、タスク定義の import ID は ARN。
+// - Type: aws_ecs_service, aws_ecs_task_definition
+// - Relation: ecs_service -> ecs_cluster / ecs_task_definition (depends_on)
+// - Relation: ecs_service -> subnet / lb_target_group / service_discovery_service (network), ecs_service -> security_group (security)
+// - Relation: ecs_service -> iam_role（タスクロール・サービスロール）(iam)
+// - Relation: ecs_task_definition -> ecr_repository (artifact), ecs_task_definition -> cloudwatch_log_group (monitoring)
+// - Relation: ecs_task_definition -> secretsmanager_secret / ssm_parameter (secret), ecs_task_definition -> iam_role (iam)
+func (m *AwsToResourceMapper) MapEcsService(services []RawEcsService, taskDefs []RawEcsTaskDefinition, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, td := range taskDefs {
+		res, rels := m.mapEcsTaskDefinition(td, region)
+		resources = append(resources, res)
+		relations = append(relations, rels...)
+	}
+
+	for _, svc := range services {
+		importID := ecsServiceImportID(svc.ClusterName, svc.Name)
+		attr := map[string]any{
+			"id":                      importID,
+			"name":                    svc.Name,
+			"cluster":                 svc.ClusterName,
+			"task_definition":         svc.TaskDefinitionArn,
+			"enable_execute_command":  svc.EnableExecuteCommand,
+			"enable_ecs_managed_tags": svc.EnableEcsManagedTags,
+			"tags":                    svc.Tags,
+		}
+		// DAEMON サービスは desired_count を指定できない
+		if svc.SchedulingStrategy == "DAEMON" {
+			attr["scheduling_strategy"] = svc.SchedulingStrategy
+		} else {
+			attr["desired_count"] = svc.DesiredCount
+		}
+		if len(svc.CapacityProviderStrategy) > 0 {
+			attr["capacity_provider_strategy"] = ecsStrategyBlocks(svc.CapacityProviderStrategy)
+		} else if svc.LaunchType != "" {
+			attr["launch_type"] = svc.LaunchType
+		}
+		setNonEmpty(attr, map[string]string{
+			"platform_version": svc.PlatformVersion,
+			"iam_role":         svc.RoleArn,
+		})
+		if svc.PropagateTags != "" && svc.PropagateTags != "NONE" {
+			attr["propagate_tags"] = svc.PropagateTags
+		}
+		if svc.HealthCheckGracePeriodSeconds != 0 {
+			attr["health_check_grace_period_seconds"] = svc.HealthCheckGracePeriodSeconds
+		}
+		if svc.DeploymentMaximumPercent != 0 {
+			attr["deployment_maximum_percent"] = svc.DeploymentMaximumPercent
+			attr["deployment_minimum_healthy_percent"] = svc.DeploymentMinimumHealthyPercent
+		}
+		if svc.CircuitBreakerEnable {
+			attr["deployment_circuit_breaker"] = []HCLBlock{{"enable": true, "rollback": svc.CircuitBreakerRollback}}
+		}
+		if len(svc.SubnetIDs) > 0 {
+			attr["network_configuration"] = []HCLBlock{{
+				"subnets":          svc.SubnetIDs,
+				"security_groups":  svc.SecurityGroupIDs,
+				"assign_public_ip": svc.AssignPublicIP,
+			}}
+		}
+		var lbBlocks []HCLBlock
+		for _, lb := range svc.LoadBalancers {
+			lbBlocks = append(lbBlocks, HCLBlock{
+				"target_group_arn": lb.TargetGroupArn,
+				"container_name":   lb.ContainerName,
+				"container_port":   lb.ContainerPort,
+			})
+		}
+		if len(lbBlocks) > 0 {
+			attr["load_balancer"] = lbBlocks
+		}
+		var registryBlocks []HCLBlock
+		for _, r := range svc.ServiceRegistries {
+			block := HCLBlock{"registry_arn": r.RegistryArn}
+			if r.ContainerName != "" {
+				block["container_name"] = r.ContainerName
+				block["container_port"] = r.ContainerPort
+			}
+			if r.Port != 0 {
+				block["port"] = r.Port
+			}
+			registryBlocks = append(registryBlocks, block)
+		}
+		if len(registryBlocks) > 0 {
+			attr["service_registries"] = registryBlocks
+		}
+
+		res := m.newNamedAwsResource("aws_ecs_service", importID, newAwsLabels(svc.Tags, region, svc.VpcID), nameLabelsOr(svc.Tags, svc.Name), attr)
+		resources = append(resources, res)
+
+		relations = append(relations,
+			Relation{From: res.ID, To: awsResourceID("aws_ecs_cluster", svc.ClusterName), Kind: RelationDependsOn},
+			Relation{From: res.ID, To: awsResourceID("aws_ecs_task_definition", svc.TaskDefinitionArn), Kind: RelationDependsOn},
+		)
+		for _, id := range svc.SubnetIDs {
+			relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_subnet", id), Kind: RelationNetwork})
+		}
+		for _, id := range svc.SecurityGroupIDs {
+			relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_security_group", id), Kind: RelationSecurity})
+		}
+		for _, lb := range svc.LoadBalancers {
+			if lb.TargetGroupArn != "" {
+				relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_lb_target_group", lb.TargetGroupArn), Kind: RelationNetwork})
+			}
+		}
+		for _, r := range svc.ServiceRegistries {
+			if id := serviceDiscoveryServiceIDFromArn(r.RegistryArn); id != "" {
+				relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_service_discovery_service", id), Kind: RelationNetwork})
+			}
+		}
+		relations = append(relations, iamRoleRelations(res.ID, svc.TaskRoleArn, svc.RoleArn)...)
+	}
+
+	return resources, relations, nil
+}
+
+// mapEcsTaskDefinition は 1 つのタスク定義から aws_ecs_task_definition の Resource / Relation を生成する。
+func (m *AwsToResourceMapper) mapEcsTaskDefinition(td RawEcsTaskDefinition, region string) (Resource, []Relation) {
+	attr := map[string]any{
+		"id":                    td.Arn,
+		"arn":                   td.Arn,
+		"family":                td.Family,
+		"container_definitions": td.ContainerDefinitions,
+		"tags":                  td.Tags,
+	}
+	if len(td.RequiresCompatibilities) > 0 {
+		attr["requires_compatibilities"] = td.RequiresCompatibilities
+	}
+	setNonEmpty(attr, map[string]string{
+		"cpu":                td.Cpu,
+		"memory":             td.Memory,
+		"network_mode":       td.NetworkMode,
+		"task_role_arn":      td.TaskRoleArn,
+		"execution_role_arn": td.ExecutionRoleArn,
+	})
+	if td.CpuArchitecture != "" || td.OperatingSystemFamily != "" {
+		attr["runtime_platform"] = []HCLBlock{stringMapBlock(map[string]string{
+			"cpu_architecture":        td.CpuArchitecture,
+			"operating_system_family": td.OperatingSystemFamily,
+		})}
+	}
+	var volumes []HCLBlock
+	for _, v := range td.Volumes {
+		block := HCLBlock{"name": v.Name}
+		if v.HostPath != "" {
+			block["host_path"] = v.HostPath
+		}
+		if v.EfsFileSystemID != "" {
+			efs := stringMapBlock(map[string]string{
+				"file_system_id":     v.EfsFileSystemID,
+				"root_directory":     v.EfsRootDirectory,
+				"transit_encryption": v.EfsTransitEncryption,
+			})
+			if v.EfsAccessPointID != "" {
+				efs["authorization_config"] = []HCLBlock{{"access_point_id": v.EfsAccessPointID}}
+			}
+			block["efs_volume_configuration"] = []HCLBlock{efs}
+		}
+		volumes = append(volumes, block)
+	}
+	if len(volumes) > 0 {
+		attr["volume"] = volumes
+	}
+
+	nameLabels := nameLabelsOr(td.Tags, td.Family)
+	res := m.newNamedAwsResource("aws_ecs_task_definition", td.Arn, newAwsLabels(td.Tags, region, td.VpcID), nameLabels, attr)
+
+	var relations []Relation
+	seen := make(map[string]bool)
+	add := func(to string, kind RelationKind) {
+		if to == "" || seen[to] {
+			return
+		}
+		seen[to] = true
+		relations = append(relations, Relation{From: res.ID, To: to, Kind: kind})
+	}
+	for _, c := range td.Containers {
+		if repo := ecrRepositoryNameFromImage(c.Image); repo != "" {
+			add(awsResourceID("aws_ecr_repository", repo), RelationArtifact)
+		}
+		if c.LogGroup != "" {
+			add(awsResourceID("aws_cloudwatch_log_group", c.LogGroup), RelationMonitoring)
+		}
+		for _, ref := range c.SecretRefs {
+			add(secretResourceID(ref), RelationSecret)
+		}
+	}
+	for _, rel := range iamRoleRelations(res.ID, td.TaskRoleArn, td.ExecutionRoleArn) {
+		add(rel.To, rel.Kind)
+	}
+	return res, relations
+}
+
+// ecsStrategyBlocks はキャパシティプロバイダー戦略を HCL ブロックに変換する。
+func ecsStrategyBlocks(strategy []RawEcsCapacityProviderStrategy) []HCLBlock {
+	blocks := make([]HCLBlock, 0, len(strategy))
+	for _, s := range strategy {
+		blocks = append(blocks, HCLBlock{
+			"capacity_provider": s.CapacityProvider,
+			"base":              s.Base,
+			"weight":            s.Weight,
+		})
+	}
+	return blocks
+}
+
+// isFargateCapacityProvider は AWS 管理の Fargate キャパシティプロバイダーかどうかを判定する。
+func isFargateCapacityProvider(name string) bool {
+	return name == "FARGATE" || name == "FARGATE_SPOT"
+}
+
+// iamRoleRelations は ロール ARN 一覧から aws_iam_role への Relation を返す（重複・空は除く）。
+func iamRoleRelations(from string, roleArns ...string) []Relation {
+	var relations []Relation
+	seen := make(map[string]bool)
+	for _, arn := range roleArns {
+		name := iamRoleNameFromArn(arn)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		relations = append(relations, Relation{
+			From: from,
+			To:   awsResourceID("aws_iam_role", name),
+			Kind: RelationIAM,
+		})
+	}
+	return relations
+}
+
+// iamRoleNameFromArn はロール ARN（arn:aws:iam::<account>:role/<path>/<name>）からロール名を返す。
+func iamRoleNameFromArn(arn string) string {
+	if !strings.Contains(arn, ":role/") {
+		return ""
+	}
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+// autoScalingGroupNameFromArn は Auto Scaling グループの ARN（...:autoScalingGroupName/<name>）からグループ名を返す。
+func autoScalingGroupNameFromArn(arn string) string {
+	const marker = "autoScalingGroupName/"
+	if i := strings.Index(arn, marker); i >= 0 {
+		return arn[i+len(marker):]
+	}
+	return ""
+}
+
+// serviceDiscoveryServiceIDFromArn は Cloud Map のサービス ARN（...:service/srv-xxx）からサービス ID を返す。
+func serviceDiscoveryServiceIDFromArn(arn string) string {
+	if i := strings.LastIndex(arn, ":service/"); i >= 0 {
+		return arn[i+len(":service/"):]
+	}
+	return ""
+}
+
+// ecrRepositoryNameFromImage は ECR のイメージ URI（<account>.dkr.ecr.<region>.amazonaws.com/<repo>[:tag|@digest]）から
+// リポジトリ名を返す。ECR 以外のイメージの場合は空文字を返す。
+func ecrRepositoryNameFromImage(image string) string {
+	host, path, ok := strings.Cut(image, "/")
+	if !ok || !strings.Contains(host, ".dkr.ecr.") {
+		return ""
+	}
+	if i := strings.Index(path, "@"); i >= 0 {
+		path = path[:i]
+	}
+	if i := strings.LastIndex(path, ":"); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// secretResourceID はコンテナ定義の secrets の valueFrom（Secrets Manager のシークレット ARN または SSM パラメータの ARN / 名前）から
+// 参照先の Resource ID を返す。Secrets Manager の JSON キー・バージョン指定（ARN の 8 番目以降の要素）は取り除く。
+func secretResourceID(ref string) string {
+	switch {
+	case strings.HasPrefix(ref, "arn:") && strings.Contains(ref, ":secretsmanager:"):
+		parts := strings.Split(ref, ":")
+		if len(parts) > 7 {
+			parts = parts[:7]
+		}
+		return awsResourceID("aws_secretsmanager_secret", strings.Join(parts, ":"))
+	case strings.HasPrefix(ref, "arn:") && strings.Contains(ref, ":parameter/"):
+		name := ref[strings.Index(ref, ":parameter/")+len(":parameter/"):]
+		// 階層化されたパラメータ名（/prod/db など）は ARN 上で先頭の "/" が省略される
+		if strings.Contains(name, "/") {
+			name = "/" + name
+		}
+		return awsResourceID("aws_ssm_parameter", name)
+	case ref != "":
+		return awsResourceID("aws_ssm_parameter", ref)
+	default:
+		return ""
+	}
+}
+
+// ecsServiceImportID はサービスの import ID（"<クラスター名>/<サービス名>"）を返す。
+func ecsServiceImportID(clusterName string, serviceName string) string {
+	return fmt.Sprintf("%s/%s", clusterName, serviceName)
+}
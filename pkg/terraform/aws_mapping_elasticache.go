@@ -0,0 +1,249 @@
+package terraform
+
+// RawElastiCacheSubnetGroup は ElastiCache サブネットグループ向けの中間構造体。
+type RawElastiCacheSubnetGroup struct {
+	Name        string
+	Description string
+	VpcID       string
+	SubnetIDs   []string
+	Tags        map[string]string
+}
+
+// RawElastiCacheParameterGroup は ElastiCache パラメータグループ向けの中間構造体。
+// Parameters はユーザーが変更したパラメータ（Source = user）のみを持つ。
+type RawElastiCacheParameterGroup struct {
+	Name        string
+	Family      string
+	Description string
+	Parameters  map[string]string
+	Tags        map[string]string
+}
+
+// RawElastiCacheReplicationGroup は ElastiCache レプリケーショングループ（Redis / Valkey）向けの中間構造体。
+// サブネットグループ・セキュリティグループ・パラメータグループ等はメンバークラスターの値を持つ。
+type RawElastiCacheReplicationGroup struct {
+	ID                       string
+	Arn                      string
+	VpcID                    string
+	Description              string
+	Engine                   string
+	EngineVersion            string
+	NodeType                 string
+	Port                     int32
+	ClusterEnabled           bool
+	NumNodeGroups            int32
+	ReplicasPerNodeGroup     int32
+	NumCacheClusters         int32
+	AutomaticFailover        bool
+	MultiAZ                  bool
+	SubnetGroupName          string
+	ParameterGroupName       string
+	SecurityGroupIDs         []string
+	AtRestEncryptionEnabled  bool
+	TransitEncryptionEnabled bool
+	KmsKeyID                 string
+	SnapshotRetentionLimit   int32
+	SnapshotWindow           string
+	MaintenanceWindow        string
+	Tags                     map[string]string
+}
+
+// RawElastiCacheCluster はレプリケーショングループに属さない ElastiCache クラスター（Memcached / 単体の Redis）向けの中間構造体。
+type RawElastiCacheCluster struct {
+	ID                     string
+	Arn                    string
+	VpcID                  string
+	Engine                 string
+	EngineVersion          string
+	NodeType               string
+	NumCacheNodes          int32
+	Port                   int32
+	AvailabilityZone       string
+	SubnetGroupName        string
+	ParameterGroupName     string
+	SecurityGroupIDs       []string
+	SnapshotRetentionLimit int32
+	SnapshotWindow         string
+	MaintenanceWindow      string
+	Tags                   map[string]string
+}
+
+// MapElastiCacheSubnetGroup は RawElastiCacheSubnetGroup 一覧から Resource / Relation を生成する。
+// - Type: aws_elasticache_subnet_group
+// - Relation: elasticache_subnet_group -> subnet (network)
+func (m *AwsToResourceMapper) MapElastiCacheSubnetGroup(groups []RawElastiCacheSubnetGroup, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, g := range groups {
+		attr := map[string]any{
+			"id":          g.Name,
+			"name":        g.Name,
+			"description": g.Description,
+			"subnet_ids":  g.SubnetIDs,
+			"tags":        g.Tags,
+		}
+
+		res := m.newNamedAwsResource("aws_elasticache_subnet_group", g.Name, newAwsLabels(g.Tags, region, g.VpcID), nameLabelsOr(g.Tags, g.Name), attr)
+		resources = append(resources, res)
+
+		for _, id := range g.SubnetIDs {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_subnet", id),
+				Kind: RelationNetwork,
+			})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// MapElastiCacheParameterGroup は RawElastiCacheParameterGroup 一覧から Resource を生成する。
+// - Type: aws_elasticache_parameter_group
+// - Relation: なし（レプリケーショングループ / クラスターから参照される）
+func (m *AwsToResourceMapper) MapElastiCacheParameterGroup(groups []RawElastiCacheParameterGroup, region string, vpcID string) ([]Resource, []Relation, error) {
+	var resources []Resource
+
+	for _, g := range groups {
+		attr := map[string]any{
+			"id":          g.Name,
+			"name":        g.Name,
+			"family":      g.Family,
+			"description": g.Description,
+			"tags":        g.Tags,
+		}
+		if len(g.Parameters) > 0 {
+			params := make([]HCLBlock, 0, len(g.Parameters))
+			for _, name := range sortedStringKeys(g.Parameters) {
+				params = append(params, HCLBlock{"name": name, "value": g.Parameters[name]})
+			}
+			attr["parameter"] = params
+		}
+
+		resources = append(resources, m.newNamedAwsResource("aws_elasticache_parameter_group", g.Name, newAwsLabels(g.Tags, region, vpcID), nameLabelsOr(g.Tags, g.Name), attr))
+	}
+
+	return resources, nil, nil
+}
+
+// MapElastiCacheReplicationGroup は RawElastiCacheReplicationGroup 一覧から Resource / Relation を生成する。
+// メンバークラスターは aws_elasticache_cluster として出力しない（レプリケーショングループ側で管理される）。
+// - Type: aws_elasticache_replication_group
+// - Relation: replication_group -> elasticache_subnet_group (network), replication_group -> security_group (security)
+// - Relation: replication_group -> elasticache_parameter_group (depends_on), replication_group -> kms_key (encryption)
+func (m *AwsToResourceMapper) MapElastiCacheReplicationGroup(groups []RawElastiCacheReplicationGroup, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, g := range groups {
+		attr := map[string]any{
+			"id":                         g.ID,
+			"replication_group_id":       g.ID,
+			"description":                g.Description,
+			"engine":                     g.Engine,
+			"node_type":                  g.NodeType,
+			"automatic_failover_enabled": g.AutomaticFailover,
+			"multi_az_enabled":           g.MultiAZ,
+			"security_group_ids":         g.SecurityGroupIDs,
+			"at_rest_encryption_enabled": g.AtRestEncryptionEnabled,
+			"transit_encryption_enabled": g.TransitEncryptionEnabled,
+			"snapshot_retention_limit":   g.SnapshotRetentionLimit,
+			"tags":                       g.Tags,
+		}
+		// クラスターモード有効の場合はシャード数とシャードあたりのレプリカ数、無効の場合はノード数で表す
+		if g.ClusterEnabled {
+			attr["num_node_groups"] = g.NumNodeGroups
+			attr["replicas_per_node_group"] = g.ReplicasPerNodeGroup
+		} else {
+			attr["num_cache_clusters"] = g.NumCacheClusters
+		}
+		if g.Port != 0 {
+			attr["port"] = g.Port
+		}
+		setNonEmpty(attr, map[string]string{
+			"engine_version":       g.EngineVersion,
+			"subnet_group_name":    g.SubnetGroupName,
+			"parameter_group_name": g.ParameterGroupName,
+			"kms_key_id":           g.KmsKeyID,
+			"snapshot_window":      g.SnapshotWindow,
+			"maintenance_window":   g.MaintenanceWindow,
+		})
+
+		res := m.newNamedAwsResource("aws_elasticache_replication_group", g.ID, newAwsLabels(g.Tags, region, g.VpcID), nameLabelsOr(g.Tags, g.ID), attr)
+		resources = append(resources, res)
+		relations = append(relations, elastiCacheRelations(res.ID, g.SubnetGroupName, g.ParameterGroupName, g.SecurityGroupIDs)...)
+		if to := kmsKeyResourceID(g.KmsKeyID); to != "" {
+			relations = append(relations, Relation{From: res.ID, To: to, Kind: RelationEncryption})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// MapElastiCacheCluster は RawElastiCacheCluster 一覧から Resource / Relation を生成する。
+// レプリケーショングループのメンバーは呼び出し側で除外しておくこと。
+// - Type: aws_elasticache_cluster
+// - Relation: elasticache_cluster -> elasticache_subnet_group (network), elasticache_cluster -> security_group (security)
+// - Relation: elasticache_cluster -> elasticache_parameter_group (depends_on)
+func (m *AwsToResourceMapper) MapElastiCacheCluster(clusters []RawElastiCacheCluster, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, c := range clusters {
+		attr := map[string]any{
+			"id":                       c.ID,
+			"cluster_id":               c.ID,
+			"engine":                   c.Engine,
+			"node_type":                c.NodeType,
+			"num_cache_nodes":          c.NumCacheNodes,
+			"security_group_ids":       c.SecurityGroupIDs,
+			"snapshot_retention_limit": c.SnapshotRetentionLimit,
+			"tags":                     c.Tags,
+		}
+		if c.Port != 0 {
+			attr["port"] = c.Port
+		}
+		setNonEmpty(attr, map[string]string{
+			"engine_version":       c.EngineVersion,
+			"availability_zone":    c.AvailabilityZone,
+			"subnet_group_name":    c.SubnetGroupName,
+			"parameter_group_name": c.ParameterGroupName,
+			"snapshot_window":      c.SnapshotWindow,
+			"maintenance_window":   c.MaintenanceWindow,
+		})
+
+		res := m.newNamedAwsResource("aws_elasticache_cluster", c.ID, newAwsLabels(c.Tags, region, c.VpcID), nameLabelsOr(c.Tags, c.ID), attr)
+		resources = append(resources, res)
+		relations = append(relations, elastiCacheRelations(res.ID, c.SubnetGroupName, c.ParameterGroupName, c.SecurityGroupIDs)...)
+	}
+
+	return resources, relations, nil
+}
+
+// elastiCacheRelations はレプリケーショングループ / クラスターからサブネットグループ・パラメータグループ・セキュリティグループへの Relation を返す。
+func elastiCacheRelations(from string, subnetGroupName string, parameterGroupName string, sgIDs []string) []Relation {
+	var relations []Relation
+	if subnetGroupName != "" {
+		relations = append(relations, Relation{
+			From: from,
+			To:   awsResourceID("aws_elasticache_subnet_group", subnetGroupName),
+			Kind: RelationNetwork,
+		})
+	}
+	if parameterGroupName != "" {
+		relations = append(relations, Relation{
+			From: from,
+			To:   awsResourceID("aws_elasticache_parameter_group", parameterGroupName),
+			Kind: RelationDependsOn,
+		})
+	}
+	for _, id := range sgIDs {
+		relations = append(relations, Relation{
+			From: from,
+			To:   awsResourceID("aws_security_group", id),
+			Kind: RelationSecurity,
+		})
+	}
+	return relations
+}
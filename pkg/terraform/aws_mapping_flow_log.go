@@ -0,0 +1,97 @@
+package terraform
+
+import "strings"
+
+// RawFlowLog は VPC / サブネットのフローログ向けの中間構造体。
+// SubnetID が空の場合は VPC 単位のフローログ。
+type RawFlowLog struct {
+	ID                 string
+	VpcID              string
+	SubnetID           string
+	TrafficType        string // "ACCEPT" / "REJECT" / "ALL"
+	LogDestinationType string // "cloud-watch-logs" / "s3" / "kinesis-data-firehose"
+	// LogDestination は配信先の ARN（ロググループ / S3 バケット / Firehose）。
+	LogDestination string
+	LogGroupName   string
+	// IamRoleArn は CloudWatch Logs への配信に使う IAM ロール。
+	IamRoleArn string
+	LogFormat  string
+	// MaxAggregationInterval は集約間隔（秒）。既定値（600）の場合は出力しない。
+	MaxAggregationInterval int32
+	Tags                   map[string]string
+}
+
+// flowLogDefaultFormat は log_format 未指定時のフローログのフォーマット（バージョン 2 の既定フィールド）。
+const flowLogDefaultFormat = "${version} ${account-id} ${interface-id} ${srcaddr} ${dstaddr} ${srcport} ${dstport} ${protocol} ${packets} ${bytes} ${start} ${end} ${action} ${log-status}"
+
+// flowLogDefaultMaxAggregationInterval は max_aggregation_interval の既定値（秒）。
+const flowLogDefaultMaxAggregationInterval = 600
+
+// MapFlowLog は RawFlowLog 一覧から Resource / Relation を生成する。
+// - Type: aws_flow_log
+// - Relation: flow_log -> vpc / subnet (network), flow_log -> iam_role (iam)
+// - Relation: flow_log -> cloudwatch_log_group (monitoring), flow_log -> s3_bucket (storage)
+func (m *AwsToResourceMapper) MapFlowLog(flowLogs []RawFlowLog, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, f := range flowLogs {
+		attr := map[string]any{
+			"id":                   f.ID,
+			"traffic_type":         f.TrafficType,
+			"log_destination_type": f.LogDestinationType,
+			"tags":                 f.Tags,
+		}
+		setNonEmpty(attr, map[string]string{
+			"log_destination": f.LogDestination,
+			"iam_role_arn":    f.IamRoleArn,
+		})
+		if f.SubnetID != "" {
+			attr["subnet_id"] = f.SubnetID
+		} else {
+			attr["vpc_id"] = f.VpcID
+		}
+		if f.LogFormat != "" && f.LogFormat != flowLogDefaultFormat {
+			attr["log_format"] = f.LogFormat
+		}
+		if f.MaxAggregationInterval != 0 && f.MaxAggregationInterval != flowLogDefaultMaxAggregationInterval {
+			attr["max_aggregation_interval"] = f.MaxAggregationInterval
+		}
+
+		res := m.newAwsResource("aws_flow_log", f.ID, newAwsLabels(f.Tags, region, f.VpcID), attr)
+		resources = append(resources, res)
+
+		if f.SubnetID != "" {
+			relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_subnet", f.SubnetID), Kind: RelationNetwork})
+		} else {
+			relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_vpc", f.VpcID), Kind: RelationNetwork})
+		}
+		relations = append(relations, iamRoleRelations(res.ID, f.IamRoleArn)...)
+		switch f.LogDestinationType {
+		case "cloud-watch-logs":
+			group := f.LogGroupName
+			if group == "" {
+				group = LogGroupNameFromArn(f.LogDestination)
+			}
+			if group != "" {
+				relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_cloudwatch_log_group", group), Kind: RelationMonitoring})
+			}
+		case "s3":
+			if bucket := s3BucketFromLocation(f.LogDestination); bucket != "" {
+				relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_s3_bucket", bucket), Kind: RelationStorage})
+			}
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// LogGroupNameFromArn はロググループの ARN（arn:aws:logs:<region>:<account>:log-group:<name>[:*]）からロググループ名を返す。
+// ロググループの ARN でない場合は空文字を返す。
+func LogGroupNameFromArn(arn string) string {
+	_, name, ok := strings.Cut(arn, ":log-group:")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSuffix(name, ":*")
+}
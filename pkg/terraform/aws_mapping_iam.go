@@ -0,0 +1,117 @@
+package terraform
+
+import "strings"
+
+// RawIamRole は VPC 内のワークロードが参照する IAM ロール向けの中間構造体。
+type RawIamRole struct {
+	Name        string
+	Arn         string
+	Path        string
+	Description string
+	// AssumeRolePolicy は信頼ポリシーの JSON 文字列（URL デコード済み）。HCL 上では jsonencode() の式として出力する。
+	AssumeRolePolicy    string
+	MaxSessionDuration  int32
+	PermissionsBoundary string
+	InlinePolicies      []RawIamRolePolicy
+	// AttachedPolicyArns はアタッチされたマネージドポリシー（AWS 管理 / カスタマー管理）の ARN。
+	AttachedPolicyArns []string
+	Tags               map[string]string
+}
+
+// RawIamRolePolicy はロールのインラインポリシー。
+type RawIamRolePolicy struct {
+	Name string
+	// Policy はポリシードキュメントの JSON 文字列（URL デコード済み）。
+	Policy string
+}
+
+// RawIamInstanceProfile は EC2 インスタンスが参照するインスタンスプロファイル向けの中間構造体。
+type RawIamInstanceProfile struct {
+	Name     string
+	Arn      string
+	Path     string
+	RoleName string
+	Tags     map[string]string
+}
+
+// iamDefaultMaxSessionDuration はロールの最大セッション時間の既定値（秒）。
+const iamDefaultMaxSessionDuration = 3600
+
+// MapIamRole は RawIamRole / RawIamInstanceProfile 一覧から Resource / Relation を生成する。
+// IAM はグローバルなサービスだが、VPC 内のワークロードに付随して取り込むため vpcID をラベルに付与する。
+// import ID はロール名 / プロファイル名と、ロール単位の複合 ID（"<ロール名>:<ポリシー名>" / "<ロール名>/<ポリシー ARN>"）。
+// - Type: aws_iam_role, aws_iam_role_policy, aws_iam_role_policy_attachment, aws_iam_instance_profile
+// - Relation: iam_instance_profile -> iam_role (iam)
+// - Relation: iam_role_policy / iam_role_policy_attachment -> iam_role (depends_on)
+func (m *AwsToResourceMapper) MapIamRole(roles []RawIamRole, profiles []RawIamInstanceProfile, region string, vpcID string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, r := range roles {
+		labels := newAwsLabels(r.Tags, region, vpcID)
+		attr := map[string]any{
+			"id":                 r.Name,
+			"name":               r.Name,
+			"arn":                r.Arn,
+			"assume_role_policy": r.AssumeRolePolicy,
+			"tags":               r.Tags,
+		}
+		if r.Path != "" && r.Path != "/" {
+			attr["path"] = r.Path
+		}
+		if r.MaxSessionDuration != 0 && r.MaxSessionDuration != iamDefaultMaxSessionDuration {
+			attr["max_session_duration"] = r.MaxSessionDuration
+		}
+		setNonEmpty(attr, map[string]string{
+			"description":          r.Description,
+			"permissions_boundary": r.PermissionsBoundary,
+		})
+
+		res := m.newNamedAwsResource("aws_iam_role", r.Name, labels, nameLabelsOr(r.Tags, r.Name), attr)
+		resources = append(resources, res)
+
+		for _, p := range r.InlinePolicies {
+			importID := r.Name + ":" + p.Name
+			policyRes := m.newNamedAwsResource("aws_iam_role_policy", importID, labels, map[string]string{"Name": r.Name + "_" + p.Name}, map[string]any{
+				"id":     importID,
+				"name":   p.Name,
+				"role":   r.Name,
+				"policy": p.Policy,
+			})
+			resources = append(resources, policyRes)
+			relations = append(relations, Relation{From: policyRes.ID, To: res.ID, Kind: RelationDependsOn})
+		}
+		for _, arn := range r.AttachedPolicyArns {
+			importID := r.Name + "/" + arn
+			policyName := arn[strings.LastIndex(arn, "/")+1:]
+			attachRes := m.newNamedAwsResource("aws_iam_role_policy_attachment", importID, labels, map[string]string{"Name": r.Name + "_" + policyName}, map[string]any{
+				"id":         importID,
+				"role":       r.Name,
+				"policy_arn": arn,
+			})
+			resources = append(resources, attachRes)
+			relations = append(relations, Relation{From: attachRes.ID, To: res.ID, Kind: RelationDependsOn})
+		}
+	}
+
+	for _, p := range profiles {
+		attr := map[string]any{
+			"id":   p.Name,
+			"name": p.Name,
+			"arn":  p.Arn,
+			"tags": p.Tags,
+		}
+		if p.Path != "" && p.Path != "/" {
+			attr["path"] = p.Path
+		}
+		setNonEmpty(attr, map[string]string{"role": p.RoleName})
+
+		res := m.newNamedAwsResource("aws_iam_instance_profile", p.Name, newAwsLabels(p.Tags, region, vpcID), nameLabelsOr(p.Tags, p.Name), attr)
+		resources = append(resources, res)
+		if p.RoleName != "" {
+			relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_iam_role", p.RoleName), Kind: RelationIAM})
+		}
+	}
+
+	return resources, relations, nil
+}
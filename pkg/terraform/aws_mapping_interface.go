@@ -0,0 +1,179 @@
+package terraform
+
+// RawNetworkInterface は ENI（Elastic Network Interface）向けの中間構造体。
+// AttachmentInstanceID / AttachmentDeviceIndex はインスタンスにアタッチされている場合のみ意味を持つ。
+type RawNetworkInterface struct {
+	ID                    string
+	VpcID                 string
+	SubnetID              string
+	Description           string
+	InterfaceType         string
+	PrivateIPs            []string
+	SecurityGroupIDs      []string
+	SourceDestCheck       bool
+	AttachmentInstanceID  string
+	AttachmentDeviceIndex int32
+	Tags                  map[string]string
+}
+
+// RawEip は Elastic IP 向けの中間構造体。
+// AssociationID は関連付けを aws_eip_association として管理する場合のみ設定する
+// （NAT ゲートウェイや ELB などが管理する関連付けは空にする）。
+// NetworkInterfaceID / InstanceID / NatGatewayID は関連付け先（所有者）。
+type RawEip struct {
+	AllocationID       string
+	PublicIP           string
+	VpcID              string
+	AssociationID      string
+	NetworkInterfaceID string
+	PrivateIPAddress   string
+	InstanceID         string
+	NatGatewayID       string
+	Tags               map[string]string
+}
+
+// MapNetworkInterface は RawNetworkInterface 一覧から Resource / Relation を生成する。
+// - Type: aws_network_interface
+// - Relation: network_interface -> subnet / instance (network), network_interface -> security_group (security)
+func (m *AwsToResourceMapper) MapNetworkInterface(enis []RawNetworkInterface, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, e := range enis {
+		labels := newAwsLabels(e.Tags, region, e.VpcID)
+
+		attr := map[string]any{
+			"id":                e.ID,
+			"subnet_id":         e.SubnetID,
+			"source_dest_check": e.SourceDestCheck,
+			"tags":              e.Tags,
+		}
+		if e.Description != "" {
+			attr["description"] = e.Description
+		}
+		// interface_type は efa / branch / trunk の場合のみ指定する
+		if e.InterfaceType != "" && e.InterfaceType != "interface" {
+			attr["interface_type"] = e.InterfaceType
+		}
+		if len(e.PrivateIPs) > 0 {
+			attr["private_ips"] = e.PrivateIPs
+		}
+		if len(e.SecurityGroupIDs) > 0 {
+			attr["security_groups"] = e.SecurityGroupIDs
+		}
+		if e.AttachmentInstanceID != "" {
+			attr["attachment"] = []HCLBlock{{
+				"instance":     e.AttachmentInstanceID,
+				"device_index": e.AttachmentDeviceIndex,
+			}}
+		}
+
+		res := m.newAwsResource("aws_network_interface", e.ID, labels, attr)
+		resources = append(resources, res)
+
+		if e.SubnetID != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_subnet", e.SubnetID),
+				Kind: RelationNetwork,
+			})
+		}
+		for _, id := range e.SecurityGroupIDs {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_security_group", id),
+				Kind: RelationSecurity,
+			})
+		}
+		if e.AttachmentInstanceID != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_instance", e.AttachmentInstanceID),
+				Kind: RelationNetwork,
+			})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// MapEip は RawEip 一覧から Resource / Relation を生成する。
+// - Type: aws_eip, aws_eip_association（AssociationID がある場合）
+// - Relation: eip -> network_interface / instance / nat_gateway (network)
+// - Relation: eip_association -> eip / network_interface (network)
+//
+// NAT ゲートウェイの allocation_id は eip -> nat_gateway の関係から aws_eip への参照として出力される。
+func (m *AwsToResourceMapper) MapEip(eips []RawEip, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, e := range eips {
+		labels := newAwsLabels(e.Tags, region, e.VpcID)
+
+		attr := map[string]any{
+			"id":     e.AllocationID,
+			"domain": "vpc",
+			"tags":   e.Tags,
+		}
+
+		// Name タグがない場合はパブリック IP を論理名のベースにする
+		nameLabels := labels
+		if e.Tags["Name"] == "" && e.PublicIP != "" {
+			nameLabels = map[string]string{"Name": "eip_" + e.PublicIP}
+		}
+
+		res := Resource{
+			ID:         awsResourceID("aws_eip", e.AllocationID),
+			Provider:   "aws",
+			Type:       "aws_eip",
+			Name:       m.nameGenerator.Generate("aws_eip", nameLabels, e.AllocationID),
+			Labels:     labels,
+			Attributes: attr,
+			Origin:     OriginCloud,
+		}
+		resources = append(resources, res)
+
+		for _, owner := range []struct{ resourceType, id string }{
+			{"aws_network_interface", e.NetworkInterfaceID},
+			{"aws_instance", e.InstanceID},
+			{"aws_nat_gateway", e.NatGatewayID},
+		} {
+			if owner.id == "" {
+				continue
+			}
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID(owner.resourceType, owner.id),
+				Kind: RelationNetwork,
+			})
+		}
+
+		if e.AssociationID == "" || e.NetworkInterfaceID == "" {
+			continue
+		}
+		assocAttr := map[string]any{
+			"id":                   e.AssociationID,
+			"allocation_id":        e.AllocationID,
+			"network_interface_id": e.NetworkInterfaceID,
+		}
+		if e.PrivateIPAddress != "" {
+			assocAttr["private_ip_address"] = e.PrivateIPAddress
+		}
+		assoc := Resource{
+			ID:         awsResourceID("aws_eip_association", e.AssociationID),
+			Provider:   "aws",
+			Type:       "aws_eip_association",
+			Name:       m.nameGenerator.Generate("aws_eip_association", map[string]string{"Name": res.Name}, e.AssociationID),
+			Labels:     newAwsLabels(e.Tags, region, e.VpcID),
+			Attributes: assocAttr,
+			Origin:     OriginCloud,
+		}
+		resources = append(resources, assoc)
+		relations = append(relations,
+			Relation{From: assoc.ID, To: res.ID, Kind: RelationNetwork},
+			Relation{From: assoc.ID, To: awsResourceID("aws_network_interface", e.NetworkInterfaceID), Kind: RelationNetwork},
+		)
+	}
+
+	return resources, relations, nil
+}
@@ -0,0 +1,132 @@
+package terraform
+
+import "strings"
+
+// RawKmsKey は VPC 内のリソースが暗号化に利用する KMS キー向けの中間構造体。
+type RawKmsKey struct {
+	KeyID       string
+	Arn         string
+	Description string
+	// AwsManaged は AWS 管理キー（alias/aws/*）かどうか。AWS 管理キーは import せず data "aws_kms_alias" として参照する。
+	AwsManaged  bool
+	Enabled     bool
+	KeyUsage    string
+	KeySpec     string
+	MultiRegion bool
+	// Policy はキーポリシーの JSON 文字列。HCL 上では jsonencode() の式として出力する。
+	Policy          string
+	RotationEnabled bool
+	// RotationPeriodDays は自動ローテーションの間隔（日）。既定値（365）の場合は出力しない。
+	RotationPeriodDays int32
+	Aliases            []RawKmsAlias
+	// Referrers はこのキーを暗号化に利用している Resource。
+	Referrers []RawKmsReferrer
+	Tags      map[string]string
+}
+
+// RawKmsAlias は KMS キーのエイリアス。
+type RawKmsAlias struct {
+	Name string // "alias/<name>"
+	Arn  string
+}
+
+// RawKmsReferrer は KMS キーを参照している Resource と、参照に使われたエイリアス名。
+type RawKmsReferrer struct {
+	ResourceID string
+	// Alias が空の場合はキー ARN / キー ID で参照している。
+	Alias string
+}
+
+// kmsDefaultRotationPeriodDays はキーの自動ローテーション間隔の既定値（日）。
+const kmsDefaultRotationPeriodDays = 365
+
+// MapKmsKey は RawKmsKey 一覧から Resource / Relation を生成する。
+// カスタマー管理キーは aws_kms_key（import ID はキー ID）と aws_kms_alias（import ID はエイリアス名）として出力し、
+// AWS 管理キーは import できないため data "aws_kms_alias" として出力する。
+// キー ARN / ID による参照元 -> kms_key の関係は各リソースの mapper が出力するため、ここではエイリアス経由の参照と AWS 管理キーへの参照のみを出力する。
+// - Type: aws_kms_key, aws_kms_alias, data.aws_kms_alias
+// - Relation: kms_alias -> kms_key (depends_on)
+// - Relation: 参照元 -> kms_alias / data.kms_alias (encryption)
+func (m *AwsToResourceMapper) MapKmsKey(keys []RawKmsKey, region string, vpcID string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, k := range keys {
+		if k.AwsManaged {
+			alias := awsManagedKmsAlias(k.Aliases)
+			if alias == "" {
+				continue
+			}
+			data := m.newNamedAwsResource("aws_kms_alias", alias, newAwsLabels(nil, region, vpcID),
+				map[string]string{"Name": strings.ReplaceAll(strings.TrimPrefix(alias, "alias/"), "/", "_")},
+				// target_key_arn は参照元の kms_key_id 等との照合用で、data ブロックには出力しない（読み取り専用）
+				map[string]any{"name": alias, "target_key_arn": k.Arn})
+			data.Mode = ResourceModeData
+			resources = append(resources, data)
+			for _, ref := range k.Referrers {
+				relations = append(relations, Relation{From: ref.ResourceID, To: data.ID, Kind: RelationEncryption})
+			}
+			continue
+		}
+
+		labels := newAwsLabels(k.Tags, region, vpcID)
+		attr := map[string]any{
+			"id":                  k.KeyID,
+			"arn":                 k.Arn,
+			"key_usage":           k.KeyUsage,
+			"enable_key_rotation": k.RotationEnabled,
+			"tags":                k.Tags,
+		}
+		setNonEmpty(attr, map[string]string{
+			"description":              k.Description,
+			"customer_master_key_spec": k.KeySpec,
+			"policy":                   k.Policy,
+		})
+		if !k.Enabled {
+			attr["is_enabled"] = false
+		}
+		if k.MultiRegion {
+			attr["multi_region"] = true
+		}
+		if k.RotationEnabled && k.RotationPeriodDays != 0 && k.RotationPeriodDays != kmsDefaultRotationPeriodDays {
+			attr["rotation_period_in_days"] = k.RotationPeriodDays
+		}
+
+		// キー自体に名前はないため、論理名は最初のエイリアスをベースにする
+		name := k.KeyID
+		if len(k.Aliases) > 0 {
+			name = strings.TrimPrefix(k.Aliases[0].Name, "alias/")
+		}
+		res := m.newNamedAwsResource("aws_kms_key", k.KeyID, labels, nameLabelsOr(k.Tags, name), attr)
+		resources = append(resources, res)
+
+		for _, a := range k.Aliases {
+			aliasRes := m.newNamedAwsResource("aws_kms_alias", a.Name, newAwsLabels(nil, region, vpcID),
+				map[string]string{"Name": strings.TrimPrefix(a.Name, "alias/")}, map[string]any{
+					"id":            a.Name,
+					"name":          a.Name,
+					"arn":           a.Arn,
+					"target_key_id": k.KeyID,
+				})
+			resources = append(resources, aliasRes)
+			relations = append(relations, Relation{From: aliasRes.ID, To: res.ID, Kind: RelationDependsOn})
+		}
+		for _, ref := range k.Referrers {
+			if ref.Alias != "" {
+				relations = append(relations, Relation{From: ref.ResourceID, To: awsResourceID("aws_kms_alias", ref.Alias), Kind: RelationEncryption})
+			}
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// awsManagedKmsAlias は AWS 管理キーのエイリアス（alias/aws/<サービス>）を返す。見つからない場合は空文字を返す。
+func awsManagedKmsAlias(aliases []RawKmsAlias) string {
+	for _, a := range aliases {
+		if strings.HasPrefix(a.Name, "alias/aws/") {
+			return a.Name
+		}
+	}
+	return ""
+}
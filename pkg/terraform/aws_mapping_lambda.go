@@ -0,0 +1,194 @@
+package terraform
+
+import "strings"
+
+// RawLambdaFunction は VPC に接続された Lambda 関数向けの中間構造体。
+type RawLambdaFunction struct {
+	Name          string
+	Arn           string
+	VpcID         string
+	Description   string
+	Role          string
+	PackageType   string // "Zip" / "Image"
+	Runtime       string
+	Handler       string
+	ImageURI      string
+	MemorySize    int32
+	Timeout       int32
+	Architectures []string
+	Environment   map[string]string
+	Layers        []string
+	KmsKeyArn     string
+	TracingMode   string
+	// EphemeralStorageSize は /tmp のサイズ（MB）。既定値（512）の場合は出力しない。
+	EphemeralStorageSize int32
+	DeadLetterTargetArn  string
+	// LogGroup はログの出力先ロググループ名（未設定の場合は /aws/lambda/<関数名>）。
+	LogGroup         string
+	LogFormat        string // "Text" / "JSON"
+	SubnetIDs        []string
+	SecurityGroupIDs []string
+	Tags             map[string]string
+}
+
+// RawLambdaEventSourceMapping は Lambda のイベントソースマッピング向けの中間構造体。
+type RawLambdaEventSourceMapping struct {
+	UUID                           string
+	FunctionName                   string
+	VpcID                          string
+	EventSourceArn                 string
+	Enabled                        bool
+	BatchSize                      int32
+	MaximumBatchingWindowInSeconds int32
+	StartingPosition               string
+}
+
+// lambdaDefaultEphemeralStorageSize は Lambda の /tmp の既定サイズ（MB）。
+const lambdaDefaultEphemeralStorageSize = 512
+
+// MapLambdaFunction は RawLambdaFunction / RawLambdaEventSourceMapping 一覧から Resource / Relation を生成する。
+// デプロイパッケージは import できないため、Zip パッケージの関数の filename は生成時に変数への参照として出力される。
+// - Type: aws_lambda_function, aws_lambda_event_source_mapping
+// - Relation: lambda_function -> subnet (network), lambda_function -> security_group (security)
+// - Relation: lambda_function -> iam_role (iam), lambda_function -> kms_key (encryption)
+// - Relation: lambda_function -> cloudwatch_log_group (monitoring), lambda_function -> lambda_layer_version (depends_on)
+// - Relation: lambda_event_source_mapping -> lambda_function (depends_on), lambda_event_source_mapping -> sqs_queue / dynamodb_table (messaging)
+func (m *AwsToResourceMapper) MapLambdaFunction(functions []RawLambdaFunction, mappings []RawLambdaEventSourceMapping, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, f := range functions {
+		attr := map[string]any{
+			"id":            f.Name,
+			"function_name": f.Name,
+			"arn":           f.Arn,
+			"role":          f.Role,
+			"package_type":  f.PackageType,
+			"memory_size":   f.MemorySize,
+			"timeout":       f.Timeout,
+			"tags":          f.Tags,
+		}
+		setNonEmpty(attr, map[string]string{
+			"description": f.Description,
+			"runtime":     f.Runtime,
+			"handler":     f.Handler,
+			"image_uri":   f.ImageURI,
+			"kms_key_arn": f.KmsKeyArn,
+		})
+		if len(f.Architectures) > 0 {
+			attr["architectures"] = f.Architectures
+		}
+		if len(f.Layers) > 0 {
+			attr["layers"] = f.Layers
+		}
+		if len(f.Environment) > 0 {
+			attr["environment"] = []HCLBlock{{"variables": f.Environment}}
+		}
+		if f.TracingMode != "" {
+			attr["tracing_config"] = []HCLBlock{{"mode": f.TracingMode}}
+		}
+		if f.EphemeralStorageSize != 0 && f.EphemeralStorageSize != lambdaDefaultEphemeralStorageSize {
+			attr["ephemeral_storage"] = []HCLBlock{{"size": f.EphemeralStorageSize}}
+		}
+		if f.DeadLetterTargetArn != "" {
+			attr["dead_letter_config"] = []HCLBlock{{"target_arn": f.DeadLetterTargetArn}}
+		}
+		if (f.LogGroup != "" && f.LogGroup != lambdaDefaultLogGroup(f.Name)) || f.LogFormat == "JSON" {
+			logging := HCLBlock{"log_format": f.LogFormat}
+			if f.LogGroup != "" {
+				logging["log_group"] = f.LogGroup
+			}
+			attr["logging_config"] = []HCLBlock{logging}
+		}
+		attr["vpc_config"] = []HCLBlock{{
+			"subnet_ids":         f.SubnetIDs,
+			"security_group_ids": f.SecurityGroupIDs,
+		}}
+
+		res := m.newNamedAwsResource("aws_lambda_function", f.Name, newAwsLabels(f.Tags, region, f.VpcID), nameLabelsOr(f.Tags, f.Name), attr)
+		resources = append(resources, res)
+
+		for _, id := range f.SubnetIDs {
+			relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_subnet", id), Kind: RelationNetwork})
+		}
+		for _, id := range f.SecurityGroupIDs {
+			relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_security_group", id), Kind: RelationSecurity})
+		}
+		relations = append(relations, iamRoleRelations(res.ID, f.Role)...)
+		if to := kmsKeyResourceID(f.KmsKeyArn); to != "" {
+			relations = append(relations, Relation{From: res.ID, To: to, Kind: RelationEncryption})
+		}
+		logGroup := f.LogGroup
+		if logGroup == "" {
+			logGroup = lambdaDefaultLogGroup(f.Name)
+		}
+		relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_cloudwatch_log_group", logGroup), Kind: RelationMonitoring})
+		for _, layer := range f.Layers {
+			relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_lambda_layer_version", layer), Kind: RelationDependsOn})
+		}
+	}
+
+	for _, e := range mappings {
+		attr := map[string]any{
+			"id":               e.UUID,
+			"function_name":    e.FunctionName,
+			"event_source_arn": e.EventSourceArn,
+			"enabled":          e.Enabled,
+		}
+		if e.BatchSize != 0 {
+			attr["batch_size"] = e.BatchSize
+		}
+		if e.MaximumBatchingWindowInSeconds != 0 {
+			attr["maximum_batching_window_in_seconds"] = e.MaximumBatchingWindowInSeconds
+		}
+		setNonEmpty(attr, map[string]string{"starting_position": e.StartingPosition})
+
+		nameLabels := map[string]string{"Name": e.FunctionName + "_" + eventSourceName(e.EventSourceArn)}
+		res := m.newNamedAwsResource("aws_lambda_event_source_mapping", e.UUID, newAwsLabels(nil, region, e.VpcID), nameLabels, attr)
+		resources = append(resources, res)
+
+		relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_lambda_function", e.FunctionName), Kind: RelationDependsOn})
+		if to := eventSourceResourceID(e.EventSourceArn); to != "" {
+			relations = append(relations, Relation{From: res.ID, To: to, Kind: RelationMessaging})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// lambdaDefaultLogGroup は関数の既定のロググループ名を返す。
+func lambdaDefaultLogGroup(functionName string) string {
+	return "/aws/lambda/" + functionName
+}
+
+// eventSourceName はイベントソースの ARN からキュー名 / テーブル名を返す（リソース名の生成用）。
+func eventSourceName(arn string) string {
+	if table, ok := dynamoDBTableNameFromArn(arn); ok {
+		return table
+	}
+	return arn[strings.LastIndex(arn, ":")+1:]
+}
+
+// eventSourceResourceID はイベントソースの ARN から参照先の Resource ID を返す。
+// SQS キューは ARN、DynamoDB テーブルはストリーム ARN から取り出したテーブル名で識別する。それ以外（Kinesis / MSK など）は空文字を返す。
+func eventSourceResourceID(arn string) string {
+	switch {
+	case strings.HasPrefix(arn, "arn:") && strings.Contains(arn, ":sqs:"):
+		return awsResourceID("aws_sqs_queue", arn)
+	case strings.HasPrefix(arn, "arn:") && strings.Contains(arn, ":dynamodb:"):
+		if table, ok := dynamoDBTableNameFromArn(arn); ok {
+			return awsResourceID("aws_dynamodb_table", table)
+		}
+	}
+	return ""
+}
+
+// dynamoDBTableNameFromArn は DynamoDB のテーブル / ストリームの ARN（...:table/<name>[/stream/<label>]）からテーブル名を返す。
+func dynamoDBTableNameFromArn(arn string) (string, bool) {
+	_, rest, ok := strings.Cut(arn, ":table/")
+	if !ok {
+		return "", false
+	}
+	name, _, _ := strings.Cut(rest, "/")
+	return name, true
+}
@@ -0,0 +1,481 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RawLoadBalancer は ALB / NLB 向けの中間構造体。
+type RawLoadBalancer struct {
+	Arn              string
+	Name             string
+	Type             string // "application" / "network"
+	Internal         bool
+	VpcID            string
+	IpAddressType    string
+	SubnetIDs        []string
+	SecurityGroupIDs []string
+	Tags             map[string]string
+}
+
+// RawLbTargetGroup はターゲットグループ向けの中間構造体。
+// Targets は DescribeTargetHealth で取得した登録済みターゲット。
+type RawLbTargetGroup struct {
+	Arn             string
+	Name            string
+	VpcID           string
+	Port            int32
+	Protocol        string
+	ProtocolVersion string
+	TargetType      string // "instance" / "ip" / "alb"
+	HealthCheck     RawLbHealthCheck
+	Targets         []RawLbTarget
+	Tags            map[string]string
+}
+
+// RawLbHealthCheck はターゲットグループのヘルスチェック設定。
+type RawLbHealthCheck struct {
+	Enabled            bool
+	Path               string
+	Port               string
+	Protocol           string
+	Interval           int32
+	Timeout            int32
+	HealthyThreshold   int32
+	UnhealthyThreshold int32
+	Matcher            string
+}
+
+// RawLbTarget はターゲットグループに登録された 1 ターゲット。
+// ID はターゲットタイプに応じてインスタンス ID / IP アドレス / ALB の ARN。
+type RawLbTarget struct {
+	ID               string
+	Port             int32
+	AvailabilityZone string
+}
+
+// RawLbListener はリスナー向けの中間構造体。
+// CertificateArn はデフォルト証明書の ARN（HTTPS / TLS リスナーのみ）。
+// CertificateDomain / CertificateStatus は ACM DescribeCertificate で取得した証明書のドメイン名とステータスで、
+// 取得できなかった場合は空。
+type RawLbListener struct {
+	Arn               string
+	LoadBalancerArn   string
+	Port              int32
+	Protocol          string
+	SslPolicy         string
+	CertificateArn    string
+	CertificateDomain string
+	CertificateStatus string
+	DefaultActions    []RawLbAction
+	Rules             []RawLbListenerRule
+	Tags              map[string]string
+}
+
+// RawLbListenerRule はリスナールール向けの中間構造体。デフォルトルールは含めない。
+type RawLbListenerRule struct {
+	Arn        string
+	Priority   string
+	Actions    []RawLbAction
+	Conditions []RawLbRuleCondition
+	Tags       map[string]string
+}
+
+// RawLbAction はリスナー / リスナールールのアクション。
+// forward は ForwardTargetGroups、redirect / fixed-response は対応するフィールドを持つ。
+type RawLbAction struct {
+	Type                string
+	Order               int32
+	ForwardTargetGroups []RawLbWeightedTargetGroup
+	Redirect            map[string]string
+	FixedResponse       map[string]string
+}
+
+// RawLbWeightedTargetGroup は forward アクションの転送先ターゲットグループと重み。
+type RawLbWeightedTargetGroup struct {
+	Arn    string
+	Weight int32
+}
+
+// RawLbRuleCondition はリスナールールの条件。
+// Field は "host-header" / "path-pattern" / "http-header" / "http-request-method" / "query-string" / "source-ip"。
+// query-string の Values は "key=value"（key は省略可）形式。
+type RawLbRuleCondition struct {
+	Field          string
+	HttpHeaderName string
+	Values         []string
+}
+
+// MapLoadBalancer は RawLoadBalancer 一覧から Resource / Relation を生成する。
+// - Type: aws_lb
+// - Relation: lb -> subnet (network), lb -> security_group (security)
+func (m *AwsToResourceMapper) MapLoadBalancer(lbs []RawLoadBalancer, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, lb := range lbs {
+		labels := newAwsLabels(lb.Tags, region, lb.VpcID)
+
+		attr := map[string]any{
+			"id":                 lb.Arn,
+			"name":               lb.Name,
+			"internal":           lb.Internal,
+			"load_balancer_type": lb.Type,
+			"ip_address_type":    lb.IpAddressType,
+			"subnets":            lb.SubnetIDs,
+			"tags":               lb.Tags,
+		}
+		if len(lb.SecurityGroupIDs) > 0 {
+			attr["security_groups"] = lb.SecurityGroupIDs
+		}
+
+		res := m.newNamedAwsResource("aws_lb", lb.Arn, labels, nameLabelsOr(lb.Tags, lb.Name), attr)
+		resources = append(resources, res)
+
+		for _, id := range lb.SubnetIDs {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_subnet", id),
+				Kind: RelationNetwork,
+			})
+		}
+		for _, id := range lb.SecurityGroupIDs {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_security_group", id),
+				Kind: RelationSecurity,
+			})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// MapLbTargetGroup は RawLbTargetGroup 一覧から Resource / Relation を生成する。
+// 登録済みターゲットは aws_lb_target_group_attachment とする（import ID は "<target_group_arn>,<target_id>[,<port>]"）。
+// - Type: aws_lb_target_group, aws_lb_target_group_attachment
+// - Relation: target_group -> vpc (network), target_group -> instance / alb (network)
+// - Relation: target_group_attachment -> target_group / instance / alb (network)
+func (m *AwsToResourceMapper) MapLbTargetGroup(groups []RawLbTargetGroup, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, tg := range groups {
+		labels := newAwsLabels(tg.Tags, region, tg.VpcID)
+
+		attr := map[string]any{
+			"id":          tg.Arn,
+			"name":        tg.Name,
+			"port":        tg.Port,
+			"protocol":    tg.Protocol,
+			"vpc_id":      tg.VpcID,
+			"target_type": tg.TargetType,
+			"tags":        tg.Tags,
+		}
+		if tg.ProtocolVersion != "" {
+			attr["protocol_version"] = tg.ProtocolVersion
+		}
+		attr["health_check"] = []HCLBlock{lbHealthCheckBlock(tg.HealthCheck)}
+
+		res := m.newNamedAwsResource("aws_lb_target_group", tg.Arn, labels, nameLabelsOr(tg.Tags, tg.Name), attr)
+		resources = append(resources, res)
+
+		if tg.VpcID != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_vpc", tg.VpcID),
+				Kind: RelationNetwork,
+			})
+		}
+
+		for _, target := range tg.Targets {
+			attachment, attachmentRels := m.mapLbTargetGroupAttachment(tg, target, res, region)
+			resources = append(resources, attachment)
+			relations = append(relations, attachmentRels...)
+			if to := lbTargetResourceID(tg.TargetType, target.ID); to != "" {
+				relations = append(relations, Relation{From: res.ID, To: to, Kind: RelationNetwork})
+			}
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// lbHealthCheckBlock はヘルスチェック設定を aws_lb_target_group の health_check ブロックに変換する。
+func lbHealthCheckBlock(hc RawLbHealthCheck) HCLBlock {
+	block := HCLBlock{"enabled": hc.Enabled}
+	for key, val := range map[string]string{"path": hc.Path, "port": hc.Port, "protocol": hc.Protocol, "matcher": hc.Matcher} {
+		if val != "" {
+			block[key] = val
+		}
+	}
+	for key, val := range map[string]int32{
+		"interval":            hc.Interval,
+		"timeout":             hc.Timeout,
+		"healthy_threshold":   hc.HealthyThreshold,
+		"unhealthy_threshold": hc.UnhealthyThreshold,
+	} {
+		if val != 0 {
+			block[key] = val
+		}
+	}
+	return block
+}
+
+// lbTargetResourceID はターゲットに対応する Resource ID を返す。IP ターゲットなど対応するリソースがない場合は空文字を返す。
+func lbTargetResourceID(targetType string, targetID string) string {
+	switch targetType {
+	case "instance":
+		return awsResourceID("aws_instance", targetID)
+	case "alb":
+		return awsResourceID("aws_lb", targetID)
+	default:
+		return ""
+	}
+}
+
+// mapLbTargetGroupAttachment は登録済みターゲットから aws_lb_target_group_attachment の Resource / Relation を生成する。
+func (m *AwsToResourceMapper) mapLbTargetGroupAttachment(tg RawLbTargetGroup, target RawLbTarget, group Resource, region string) (Resource, []Relation) {
+	importID := tg.Arn + "," + target.ID
+	attr := map[string]any{
+		"target_group_arn": tg.Arn,
+		"target_id":        target.ID,
+	}
+	if target.Port != 0 && tg.TargetType != "alb" {
+		attr["port"] = target.Port
+		importID += fmt.Sprintf(",%d", target.Port)
+	}
+	// VPC 外の IP ターゲットは availability_zone = "all" で登録されている
+	if target.AvailabilityZone != "" {
+		attr["availability_zone"] = target.AvailabilityZone
+	}
+	attr["id"] = importID
+
+	nameLabels := map[string]string{"Name": group.Name + "_" + target.ID}
+	if target.Port != 0 && tg.TargetType != "alb" {
+		nameLabels["Name"] += fmt.Sprintf("_%d", target.Port)
+	}
+
+	// Labels はタグフィルタでターゲットグループと同じ扱いになるよう、ターゲットグループのタグを引き継ぐ
+	res := m.newNamedAwsResource("aws_lb_target_group_attachment", importID, newAwsLabels(tg.Tags, region, tg.VpcID), nameLabels, attr)
+
+	relations := []Relation{{From: res.ID, To: group.ID, Kind: RelationNetwork}}
+	if to := lbTargetResourceID(tg.TargetType, target.ID); to != "" {
+		relations = append(relations, Relation{From: res.ID, To: to, Kind: RelationNetwork})
+	}
+	return res, relations
+}
+
+// MapLbListener は RawLbListener 一覧から Resource / Relation を生成する。
+// デフォルト証明書は data "aws_acm_certificate" としてドメイン名（同じドメインの証明書は最新のもの）で参照する。
+// ドメイン名を取得できなかった証明書は data ソースを出力せず、certificate_arn を ARN のまま出力する。
+// - Type: aws_lb_listener, aws_lb_listener_rule, data.aws_acm_certificate
+// - Relation: listener -> lb (network), listener / listener_rule -> target_group (network)
+// - Relation: listener -> acm_certificate (security), listener_rule -> listener (network)
+func (m *AwsToResourceMapper) MapLbListener(listeners []RawLbListener, lbs []RawLoadBalancer, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+	certificates := make(map[string]bool)
+
+	lbByArn := make(map[string]RawLoadBalancer, len(lbs))
+	for _, lb := range lbs {
+		lbByArn[lb.Arn] = lb
+	}
+
+	for _, l := range listeners {
+		lb := lbByArn[l.LoadBalancerArn]
+		labels := newAwsLabels(l.Tags, region, lb.VpcID)
+
+		attr := map[string]any{
+			"id":                l.Arn,
+			"load_balancer_arn": l.LoadBalancerArn,
+			"port":              l.Port,
+			"protocol":          l.Protocol,
+			"tags":              l.Tags,
+			"default_action":    lbActionBlocks(l.DefaultActions),
+		}
+		if l.SslPolicy != "" {
+			attr["ssl_policy"] = l.SslPolicy
+		}
+		if l.CertificateArn != "" {
+			attr["certificate_arn"] = l.CertificateArn
+		}
+
+		nameLabels := map[string]string{"Name": fmt.Sprintf("%s_%s_%d", lb.Name, strings.ToLower(l.Protocol), l.Port)}
+		if name := l.Tags["Name"]; name != "" {
+			nameLabels["Name"] = name
+		}
+		res := m.newNamedAwsResource("aws_lb_listener", l.Arn, labels, nameLabels, attr)
+		resources = append(resources, res)
+
+		relations = append(relations, Relation{
+			From: res.ID,
+			To:   awsResourceID("aws_lb", l.LoadBalancerArn),
+			Kind: RelationNetwork,
+		})
+		relations = append(relations, lbActionRelations(res.ID, l.DefaultActions)...)
+
+		if l.CertificateArn != "" && l.CertificateDomain != "" {
+			if !certificates[l.CertificateArn] {
+				certificates[l.CertificateArn] = true
+				// 論理名は ARN 末尾の証明書 ID をベースにする。
+				// arn は参照元の certificate_arn との照合用で、HCL には出力しない
+				certLabels := newAwsLabels(nil, region, lb.VpcID)
+				certAttr := map[string]any{
+					"domain":      l.CertificateDomain,
+					"most_recent": true,
+					"arn":         l.CertificateArn,
+				}
+				// data ソースは既定で ISSUED の証明書のみを検索する
+				if l.CertificateStatus != "" && l.CertificateStatus != "ISSUED" {
+					certAttr["statuses"] = []string{l.CertificateStatus}
+				}
+				cert := m.newNamedAwsResource("aws_acm_certificate", l.CertificateArn, certLabels,
+					map[string]string{"Name": "certificate_" + l.CertificateArn[strings.LastIndex(l.CertificateArn, "/")+1:]}, certAttr)
+				cert.Mode = ResourceModeData
+				resources = append(resources, cert)
+			}
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_acm_certificate", l.CertificateArn),
+				Kind: RelationSecurity,
+			})
+		}
+
+		for _, rule := range l.Rules {
+			ruleRes, ruleRels := m.mapLbListenerRule(l, rule, res, labels)
+			resources = append(resources, ruleRes)
+			relations = append(relations, ruleRels...)
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// mapLbListenerRule はリスナールールから aws_lb_listener_rule の Resource / Relation を生成する。
+func (m *AwsToResourceMapper) mapLbListenerRule(l RawLbListener, rule RawLbListenerRule, listener Resource, listenerLabels map[string]string) (Resource, []Relation) {
+	attr := map[string]any{
+		"id":           rule.Arn,
+		"listener_arn": l.Arn,
+		"priority":     rule.Priority,
+		"action":       lbActionBlocks(rule.Actions),
+		"condition":    lbConditionBlocks(rule.Conditions),
+		"tags":         rule.Tags,
+	}
+
+	nameLabels := map[string]string{"Name": listener.Name + "_" + rule.Priority}
+	if name := rule.Tags["Name"]; name != "" {
+		nameLabels["Name"] = name
+	}
+	labels := make(map[string]string, len(listenerLabels)+len(rule.Tags))
+	for k, v := range listenerLabels {
+		labels[k] = v
+	}
+	for k, v := range rule.Tags {
+		labels[k] = v
+	}
+	res := m.newNamedAwsResource("aws_lb_listener_rule", rule.Arn, labels, nameLabels, attr)
+
+	relations := []Relation{{From: res.ID, To: listener.ID, Kind: RelationNetwork}}
+	relations = append(relations, lbActionRelations(res.ID, rule.Actions)...)
+	return res, relations
+}
+
+// lbActionBlocks はアクションを default_action / action ブロックに変換する。
+// 転送先が 1 つの forward は target_group_arn、重み付きの forward は forward ブロックで表現する。
+func lbActionBlocks(actions []RawLbAction) []HCLBlock {
+	blocks := make([]HCLBlock, 0, len(actions))
+	for _, a := range actions {
+		block := HCLBlock{"type": a.Type}
+		if a.Order != 0 {
+			block["order"] = a.Order
+		}
+		switch {
+		case len(a.ForwardTargetGroups) == 1:
+			block["target_group_arn"] = a.ForwardTargetGroups[0].Arn
+		case len(a.ForwardTargetGroups) > 1:
+			groups := make([]HCLBlock, 0, len(a.ForwardTargetGroups))
+			for _, tg := range a.ForwardTargetGroups {
+				groups = append(groups, HCLBlock{"arn": tg.Arn, "weight": tg.Weight})
+			}
+			block["forward"] = []HCLBlock{{"target_group": groups}}
+		case len(a.Redirect) > 0:
+			block["redirect"] = []HCLBlock{stringMapBlock(a.Redirect)}
+		case len(a.FixedResponse) > 0:
+			block["fixed_response"] = []HCLBlock{stringMapBlock(a.FixedResponse)}
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// lbActionRelations はアクションの転送先ターゲットグループへの Relation を返す。
+func lbActionRelations(from string, actions []RawLbAction) []Relation {
+	var relations []Relation
+	seen := make(map[string]bool)
+	for _, a := range actions {
+		for _, tg := range a.ForwardTargetGroups {
+			if seen[tg.Arn] {
+				continue
+			}
+			seen[tg.Arn] = true
+			relations = append(relations, Relation{
+				From: from,
+				To:   awsResourceID("aws_lb_target_group", tg.Arn),
+				Kind: RelationNetwork,
+			})
+		}
+	}
+	return relations
+}
+
+// lbConditionBlocks はリスナールールの条件を condition ブロックに変換する。
+func lbConditionBlocks(conditions []RawLbRuleCondition) []HCLBlock {
+	blocks := make([]HCLBlock, 0, len(conditions))
+	for _, c := range conditions {
+		var inner HCLBlock
+		switch c.Field {
+		case "http-header":
+			inner = HCLBlock{"http_header_name": c.HttpHeaderName, "values": c.Values}
+		case "query-string":
+			pairs := make([]HCLBlock, 0, len(c.Values))
+			for _, v := range c.Values {
+				key, value, ok := strings.Cut(v, "=")
+				if !ok {
+					pairs = append(pairs, HCLBlock{"value": v})
+					continue
+				}
+				pair := HCLBlock{"value": value}
+				if key != "" {
+					pair["key"] = key
+				}
+				pairs = append(pairs, pair)
+			}
+			blocks = append(blocks, HCLBlock{"query_string": pairs})
+			continue
+		default:
+			inner = HCLBlock{"values": c.Values}
+		}
+		blocks = append(blocks, HCLBlock{strings.ReplaceAll(c.Field, "-", "_"): []HCLBlock{inner}})
+	}
+	return blocks
+}
+
+// stringMapBlock は空でない値のみを持つ HCLBlock を返す。
+func stringMapBlock(m map[string]string) HCLBlock {
+	block := make(HCLBlock, len(m))
+	for k, v := range m {
+		if v != "" {
+			block[k] = v
+		}
+	}
+	return block
+}
+
+// nameLabelsOr は Name タグがない場合に name を論理名のベースにするラベルを返す。
+func nameLabelsOr(tags map[string]string, name string) map[string]string {
+	if tags["Name"] == "" && name != "" {
+		return map[string]string{"Name": name}
+	}
+	return tags
+}
@@ -0,0 +1,388 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RawVpc は VPC 向けの中間構造体。
+type RawVpc struct {
+	ID              string
+	CidrBlock       string
+	InstanceTenancy string
+	IsDefault       bool
+	Tags            map[string]string
+}
+
+// RawInternetGateway はインターネットゲートウェイ向けの中間構造体。
+// VpcID はアタッチ先 VPC（未アタッチの場合は空）。
+type RawInternetGateway struct {
+	ID    string
+	VpcID string
+	Tags  map[string]string
+}
+
+// RawNatGateway は NAT ゲートウェイ向けの中間構造体。
+type RawNatGateway struct {
+	ID               string
+	VpcID            string
+	SubnetID         string
+	AllocationID     string
+	ConnectivityType string
+	Tags             map[string]string
+}
+
+// RawVpcEndpoint は VPC エンドポイント向けの中間構造体。
+// Gateway 型は RouteTableIDs、Interface / GatewayLoadBalancer 型は SubnetIDs / SecurityGroupIDs を持つ。
+type RawVpcEndpoint struct {
+	ID                string
+	VpcID             string
+	ServiceName       string
+	VpcEndpointType   string
+	RouteTableIDs     []string
+	SubnetIDs         []string
+	SecurityGroupIDs  []string
+	PrivateDnsEnabled bool
+	Tags              map[string]string
+}
+
+// RawNetworkAcl はネットワーク ACL 向けの中間構造体。
+// SubnetIDs は関連付けられているサブネット、Entries はルール（AWS が自動作成する既定の拒否ルールを除く）。
+type RawNetworkAcl struct {
+	ID        string
+	VpcID     string
+	IsDefault bool
+	SubnetIDs []string
+	Entries   []RawNetworkAclEntry
+	Tags      map[string]string
+}
+
+// RawNetworkAclEntry はネットワーク ACL の 1 ルール。
+// Protocol は AWS API の値（"6", "-1" など）。IcmpType / IcmpCode は ICMP 系プロトコルの場合のみ意味を持つ。
+type RawNetworkAclEntry struct {
+	RuleNumber    int32
+	Egress        bool
+	Protocol      string
+	RuleAction    string
+	CidrBlock     string
+	Ipv6CidrBlock string
+	FromPort      int32
+	ToPort        int32
+	IcmpType      int32
+	IcmpCode      int32
+}
+
+// newAwsLabels はタグをコピーし、aws_region / vpc_id のメタデータを付加した Labels を生成する。
+func newAwsLabels(tags map[string]string, region string, vpcID string) map[string]string {
+	labels := make(map[string]string, len(tags)+2)
+	for k, v := range tags {
+		labels[k] = v
+	}
+	if region != "" {
+		labels["aws_region"] = region
+	}
+	if vpcID != "" {
+		labels["vpc_id"] = vpcID
+	}
+	return labels
+}
+
+// awsResourceID は <provider>:<type>:<cloud-unique-id> 形式の内部 ID を生成する。
+func awsResourceID(resourceType string, cloudID string) string {
+	return fmt.Sprintf("aws:%s:%s", resourceType, cloudID)
+}
+
+// newAwsResource は共通フィールドを埋めた Resource を生成する。
+func (m *AwsToResourceMapper) newAwsResource(resourceType string, cloudID string, labels map[string]string, attr map[string]any) Resource {
+	return m.newNamedAwsResource(resourceType, cloudID, labels, labels, attr)
+}
+
+// newNamedAwsResource は newAwsResource と同じだが、論理名を labels ではなく nameLabels から生成する。
+func (m *AwsToResourceMapper) newNamedAwsResource(resourceType string, cloudID string, labels map[string]string, nameLabels map[string]string, attr map[string]any) Resource {
+	return Resource{
+		ID:         awsResourceID(resourceType, cloudID),
+		Provider:   "aws",
+		Type:       resourceType,
+		Name:       m.nameGenerator.Generate(resourceType, nameLabels, cloudID),
+		Labels:     labels,
+		Attributes: attr,
+		Origin:     OriginCloud,
+	}
+}
+
+// newAwsDataSource は cloudID を id で参照する data ブロック用の Resource を生成する。
+func (m *AwsToResourceMapper) newAwsDataSource(resourceType string, cloudID string, labels map[string]string) Resource {
+	res := m.newAwsResource(resourceType, cloudID, labels, map[string]any{"id": cloudID})
+	res.Mode = ResourceModeData
+	return res
+}
+
+// MapVpc は RawVpc 一覧から Resource を生成する。
+// - Type: aws_vpc
+// - Relation: なし（VPC は関係の終端）
+func (m *AwsToResourceMapper) MapVpc(vpcs []RawVpc, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+
+	for _, v := range vpcs {
+		labels := newAwsLabels(v.Tags, region, v.ID)
+
+		attr := map[string]any{
+			"id":         v.ID,
+			"cidr_block": v.CidrBlock,
+			"tags":       v.Tags,
+		}
+		if v.InstanceTenancy != "" {
+			attr["instance_tenancy"] = v.InstanceTenancy
+		}
+
+		resources = append(resources, m.newAwsResource("aws_vpc", v.ID, labels, attr))
+	}
+
+	return resources, nil, nil
+}
+
+// MapInternetGateway は RawInternetGateway 一覧から Resource / Relation を生成する。
+// - Type: aws_internet_gateway
+// - Relation: internet_gateway -> vpc (network)
+func (m *AwsToResourceMapper) MapInternetGateway(gateways []RawInternetGateway, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, g := range gateways {
+		labels := newAwsLabels(g.Tags, region, g.VpcID)
+
+		attr := map[string]any{
+			"id":     g.ID,
+			"vpc_id": g.VpcID,
+			"tags":   g.Tags,
+		}
+
+		res := m.newAwsResource("aws_internet_gateway", g.ID, labels, attr)
+		resources = append(resources, res)
+
+		if g.VpcID != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_vpc", g.VpcID),
+				Kind: RelationNetwork,
+			})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// MapNatGateway は RawNatGateway 一覧から Resource / Relation を生成する。
+// - Type: aws_nat_gateway
+// - Relation: nat_gateway -> subnet (network)
+func (m *AwsToResourceMapper) MapNatGateway(gateways []RawNatGateway, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, g := range gateways {
+		labels := newAwsLabels(g.Tags, region, g.VpcID)
+
+		attr := map[string]any{
+			"id":                g.ID,
+			"subnet_id":         g.SubnetID,
+			"connectivity_type": g.ConnectivityType,
+			"tags":              g.Tags,
+		}
+		// private NAT ゲートウェイには EIP が紐づかない
+		if g.AllocationID != "" {
+			attr["allocation_id"] = g.AllocationID
+		}
+
+		res := m.newAwsResource("aws_nat_gateway", g.ID, labels, attr)
+		resources = append(resources, res)
+
+		if g.SubnetID != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_subnet", g.SubnetID),
+				Kind: RelationNetwork,
+			})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// MapVpcEndpoint は RawVpcEndpoint 一覧から Resource / Relation を生成する。
+// - Type: aws_vpc_endpoint
+// - Relation: vpc_endpoint -> vpc / route_table / subnet (network), vpc_endpoint -> security_group (security)
+func (m *AwsToResourceMapper) MapVpcEndpoint(endpoints []RawVpcEndpoint, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, e := range endpoints {
+		labels := newAwsLabels(e.Tags, region, e.VpcID)
+
+		attr := map[string]any{
+			"id":                e.ID,
+			"vpc_id":            e.VpcID,
+			"service_name":      e.ServiceName,
+			"vpc_endpoint_type": e.VpcEndpointType,
+			"tags":              e.Tags,
+		}
+		if len(e.RouteTableIDs) > 0 {
+			attr["route_table_ids"] = e.RouteTableIDs
+		}
+		if len(e.SubnetIDs) > 0 {
+			attr["subnet_ids"] = e.SubnetIDs
+		}
+		if len(e.SecurityGroupIDs) > 0 {
+			attr["security_group_ids"] = e.SecurityGroupIDs
+		}
+		// private_dns_enabled は Interface 型でのみ有効
+		if e.VpcEndpointType == "Interface" {
+			attr["private_dns_enabled"] = e.PrivateDnsEnabled
+		}
+
+		// Name タグがない場合はサービス名（com.amazonaws.<region>.s3 -> s3）を論理名のベースにする
+		nameLabels := labels
+		if e.Tags["Name"] == "" && e.ServiceName != "" {
+			nameLabels = map[string]string{"Name": vpcEndpointServiceShortName(e.ServiceName)}
+		}
+
+		res := Resource{
+			ID:         awsResourceID("aws_vpc_endpoint", e.ID),
+			Provider:   "aws",
+			Type:       "aws_vpc_endpoint",
+			Name:       m.nameGenerator.Generate("aws_vpc_endpoint", nameLabels, e.ID),
+			Labels:     labels,
+			Attributes: attr,
+			Origin:     OriginCloud,
+		}
+		resources = append(resources, res)
+
+		if e.VpcID != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_vpc", e.VpcID),
+				Kind: RelationNetwork,
+			})
+		}
+		for _, id := range e.RouteTableIDs {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_route_table", id),
+				Kind: RelationNetwork,
+			})
+		}
+		for _, id := range e.SubnetIDs {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_subnet", id),
+				Kind: RelationNetwork,
+			})
+		}
+		for _, id := range e.SecurityGroupIDs {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_security_group", id),
+				Kind: RelationSecurity,
+			})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// vpcEndpointServiceShortName はエンドポイントのサービス名から末尾のサービス部分を返す。
+// 例: "com.amazonaws.ap-northeast-1.ecr.dkr" -> "ecr.dkr"
+func vpcEndpointServiceShortName(serviceName string) string {
+	parts := strings.SplitN(serviceName, ".", 4)
+	if len(parts) == 4 {
+		return parts[3]
+	}
+	return serviceName
+}
+
+// MapNetworkAcl は RawNetworkAcl 一覧から Resource / Relation を生成する。
+// - Type: aws_network_acl（既定の NACL は aws_default_network_acl）
+// - Relation: network_acl -> vpc / subnet (network)
+//
+// ルールは ingress / egress のネストしたブロックとして Attributes に保持する。
+// 個別の aws_network_acl_rule に分割する場合は SeparateNetworkAclRules を利用する。
+func (m *AwsToResourceMapper) MapNetworkAcl(acls []RawNetworkAcl, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, a := range acls {
+		labels := newAwsLabels(a.Tags, region, a.VpcID)
+
+		resourceType := "aws_network_acl"
+		attr := map[string]any{
+			"id":   a.ID,
+			"tags": a.Tags,
+		}
+		if a.IsDefault {
+			// aws_default_network_acl は vpc_id ではなく default_network_acl_id を指定する
+			resourceType = "aws_default_network_acl"
+			attr["default_network_acl_id"] = a.ID
+		} else {
+			attr["vpc_id"] = a.VpcID
+		}
+		if len(a.SubnetIDs) > 0 {
+			attr["subnet_ids"] = a.SubnetIDs
+		}
+
+		var ingress, egress []HCLBlock
+		for _, e := range a.Entries {
+			if e.Egress {
+				egress = append(egress, networkAclEntryBlock(e))
+			} else {
+				ingress = append(ingress, networkAclEntryBlock(e))
+			}
+		}
+		if len(ingress) > 0 {
+			attr["ingress"] = ingress
+		}
+		if len(egress) > 0 {
+			attr["egress"] = egress
+		}
+
+		res := m.newAwsResource(resourceType, a.ID, labels, attr)
+		resources = append(resources, res)
+
+		if a.VpcID != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_vpc", a.VpcID),
+				Kind: RelationNetwork,
+			})
+		}
+		for _, id := range a.SubnetIDs {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_subnet", id),
+				Kind: RelationNetwork,
+			})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// networkAclEntryBlock は 1 つのルールを aws_network_acl の ingress / egress ブロックに変換する。
+func networkAclEntryBlock(e RawNetworkAclEntry) HCLBlock {
+	block := HCLBlock{
+		"rule_no":   e.RuleNumber,
+		"action":    e.RuleAction,
+		"protocol":  e.Protocol,
+		"from_port": e.FromPort,
+		"to_port":   e.ToPort,
+	}
+	if e.CidrBlock != "" {
+		block["cidr_block"] = e.CidrBlock
+	}
+	if e.Ipv6CidrBlock != "" {
+		block["ipv6_cidr_block"] = e.Ipv6CidrBlock
+	}
+	if isIcmpProtocol(e.Protocol) {
+		block["icmp_type"] = e.IcmpType
+		block["icmp_code"] = e.IcmpCode
+	}
+	return block
+}
@@ -0,0 +1,497 @@
+package terraform
+
+import (
+	"sort"
+	"strings"
+)
+
+// RawDbSubnetGroup は DB サブネットグループ向けの中間構造体。
+type RawDbSubnetGroup struct {
+	Name        string
+	Description string
+	VpcID       string
+	SubnetIDs   []string
+	Tags        map[string]string
+}
+
+// RawDbParameterGroup は DB パラメータグループ / DB クラスターパラメータグループ向けの中間構造体。
+// Parameters はユーザーが変更したパラメータ（Source = user）のみを持つ。
+type RawDbParameterGroup struct {
+	Name        string
+	Family      string
+	Description string
+	// Cluster が true の場合は aws_rds_cluster_parameter_group とする。
+	Cluster    bool
+	Parameters []RawDbParameter
+	Tags       map[string]string
+}
+
+// RawDbParameter はパラメータグループの 1 パラメータ。
+type RawDbParameter struct {
+	Name        string
+	Value       string
+	ApplyMethod string // "immediate" / "pending-reboot"
+}
+
+// RawDbOptionGroup は DB オプショングループ向けの中間構造体。
+type RawDbOptionGroup struct {
+	Name               string
+	EngineName         string
+	MajorEngineVersion string
+	Description        string
+	Options            []RawDbOption
+	Tags               map[string]string
+}
+
+// RawDbOption はオプショングループの 1 オプション。
+type RawDbOption struct {
+	Name             string
+	Version          string
+	Port             int32
+	SecurityGroupIDs []string
+	Settings         map[string]string
+}
+
+// RawRdsCluster は Aurora クラスター（および Multi-AZ DB クラスター）向けの中間構造体。
+// マスターパスワードは API から取得できないため持たない（HCL では変数として出力する）。
+type RawRdsCluster struct {
+	Identifier                  string
+	VpcID                       string
+	Engine                      string
+	EngineVersion               string
+	EngineMode                  string
+	DatabaseName                string
+	MasterUsername              string
+	ManageMasterUserPassword    bool
+	ReplicationSourceIdentifier string
+	SubnetGroupName             string
+	ParameterGroupName          string
+	SecurityGroupIDs            []string
+	Port                        int32
+	StorageEncrypted            bool
+	KmsKeyID                    string
+	BackupRetentionPeriod       int32
+	PreferredBackupWindow       string
+	PreferredMaintenanceWindow  string
+	DeletionProtection          bool
+	// EnabledCloudwatchLogsExports は CloudWatch Logs にエクスポートするログの種類（"audit", "postgresql" など）。
+	EnabledCloudwatchLogsExports []string
+	Tags                         map[string]string
+}
+
+// RawDbInstance は DB インスタンス向けの中間構造体。
+// ClusterIdentifier が空でない場合は Aurora クラスターのメンバー（aws_rds_cluster_instance）として扱う。
+// マスターパスワードは API から取得できないため持たない（HCL では変数として出力する）。
+type RawDbInstance struct {
+	Identifier                 string
+	ClusterIdentifier          string
+	VpcID                      string
+	Engine                     string
+	EngineVersion              string
+	InstanceClass              string
+	AllocatedStorage           int32
+	MaxAllocatedStorage        int32
+	StorageType                string
+	Iops                       int32
+	DBName                     string
+	MasterUsername             string
+	ManageMasterUserPassword   bool
+	ReplicateSourceDB          string
+	SubnetGroupName            string
+	ParameterGroupName         string
+	OptionGroupName            string
+	SecurityGroupIDs           []string
+	AvailabilityZone           string
+	MultiAZ                    bool
+	PubliclyAccessible         bool
+	Port                       int32
+	StorageEncrypted           bool
+	KmsKeyID                   string
+	BackupRetentionPeriod      int32
+	PreferredBackupWindow      string
+	PreferredMaintenanceWindow string
+	DeletionProtection         bool
+	// EnabledCloudwatchLogsExports は CloudWatch Logs にエクスポートするログの種類（"error", "slowquery" など）。
+	EnabledCloudwatchLogsExports []string
+	Tags                         map[string]string
+}
+
+// MapDbSubnetGroup は RawDbSubnetGroup 一覧から Resource / Relation を生成する。
+// - Type: aws_db_subnet_group
+// - Relation: db_subnet_group -> subnet (network)
+func (m *AwsToResourceMapper) MapDbSubnetGroup(groups []RawDbSubnetGroup, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, g := range groups {
+		attr := map[string]any{
+			"id":          g.Name,
+			"name":        g.Name,
+			"description": g.Description,
+			"subnet_ids":  g.SubnetIDs,
+			"tags":        g.Tags,
+		}
+
+		res := m.newNamedAwsResource("aws_db_subnet_group", g.Name, newAwsLabels(g.Tags, region, g.VpcID), nameLabelsOr(g.Tags, g.Name), attr)
+		resources = append(resources, res)
+
+		for _, id := range g.SubnetIDs {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_subnet", id),
+				Kind: RelationNetwork,
+			})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// MapDbParameterGroup は RawDbParameterGroup 一覧から Resource を生成する。
+// - Type: aws_db_parameter_group, aws_rds_cluster_parameter_group
+// - Relation: なし（インスタンス / クラスターから参照される）
+func (m *AwsToResourceMapper) MapDbParameterGroup(groups []RawDbParameterGroup, region string, vpcID string) ([]Resource, []Relation, error) {
+	var resources []Resource
+
+	for _, g := range groups {
+		resourceType := "aws_db_parameter_group"
+		if g.Cluster {
+			resourceType = "aws_rds_cluster_parameter_group"
+		}
+
+		params := make([]HCLBlock, 0, len(g.Parameters))
+		for _, p := range g.Parameters {
+			block := HCLBlock{"name": p.Name, "value": p.Value}
+			if p.ApplyMethod != "" {
+				block["apply_method"] = p.ApplyMethod
+			}
+			params = append(params, block)
+		}
+
+		attr := map[string]any{
+			"id":          g.Name,
+			"name":        g.Name,
+			"family":      g.Family,
+			"description": g.Description,
+			"tags":        g.Tags,
+		}
+		if len(params) > 0 {
+			attr["parameter"] = params
+		}
+
+		resources = append(resources, m.newNamedAwsResource(resourceType, g.Name, newAwsLabels(g.Tags, region, vpcID), nameLabelsOr(g.Tags, g.Name), attr))
+	}
+
+	return resources, nil, nil
+}
+
+// MapDbOptionGroup は RawDbOptionGroup 一覧から Resource / Relation を生成する。
+// - Type: aws_db_option_group
+// - Relation: db_option_group -> security_group (security)
+func (m *AwsToResourceMapper) MapDbOptionGroup(groups []RawDbOptionGroup, region string, vpcID string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, g := range groups {
+		var options []HCLBlock
+		var sgIDs []string
+		seenSG := make(map[string]bool)
+		for _, o := range g.Options {
+			block := HCLBlock{"option_name": o.Name}
+			if o.Version != "" {
+				block["version"] = o.Version
+			}
+			if o.Port != 0 {
+				block["port"] = o.Port
+			}
+			if len(o.SecurityGroupIDs) > 0 {
+				block["vpc_security_group_memberships"] = o.SecurityGroupIDs
+				for _, id := range o.SecurityGroupIDs {
+					if !seenSG[id] {
+						seenSG[id] = true
+						sgIDs = append(sgIDs, id)
+					}
+				}
+			}
+			var settings []HCLBlock
+			for _, name := range sortedStringKeys(o.Settings) {
+				settings = append(settings, HCLBlock{"name": name, "value": o.Settings[name]})
+			}
+			if len(settings) > 0 {
+				block["option_settings"] = settings
+			}
+			options = append(options, block)
+		}
+
+		attr := map[string]any{
+			"id":                       g.Name,
+			"name":                     g.Name,
+			"engine_name":              g.EngineName,
+			"major_engine_version":     g.MajorEngineVersion,
+			"option_group_description": g.Description,
+			"option":                   options,
+			"tags":                     g.Tags,
+		}
+
+		res := m.newNamedAwsResource("aws_db_option_group", g.Name, newAwsLabels(g.Tags, region, vpcID), nameLabelsOr(g.Tags, g.Name), attr)
+		resources = append(resources, res)
+
+		for _, id := range sgIDs {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_security_group", id),
+				Kind: RelationSecurity,
+			})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// MapRdsCluster は RawRdsCluster 一覧から Resource / Relation を生成する。
+// - Type: aws_rds_cluster
+// - Relation: rds_cluster -> db_subnet_group (network), rds_cluster -> security_group (security)
+// - Relation: rds_cluster -> rds_cluster_parameter_group (depends_on), rds_cluster -> kms_key (encryption)
+// - Relation: rds_cluster -> cloudwatch_log_group（ログのエクスポート先）(monitoring)
+func (m *AwsToResourceMapper) MapRdsCluster(clusters []RawRdsCluster, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, c := range clusters {
+		attr := map[string]any{
+			"id":                      c.Identifier,
+			"cluster_identifier":      c.Identifier,
+			"engine":                  c.Engine,
+			"db_subnet_group_name":    c.SubnetGroupName,
+			"vpc_security_group_ids":  c.SecurityGroupIDs,
+			"storage_encrypted":       c.StorageEncrypted,
+			"backup_retention_period": c.BackupRetentionPeriod,
+			"deletion_protection":     c.DeletionProtection,
+			"tags":                    c.Tags,
+		}
+		if c.Port != 0 {
+			attr["port"] = c.Port
+		}
+		setNonEmpty(attr, map[string]string{
+			"engine_version":                  c.EngineVersion,
+			"preferred_backup_window":         c.PreferredBackupWindow,
+			"preferred_maintenance_window":    c.PreferredMaintenanceWindow,
+			"engine_mode":                     c.EngineMode,
+			"database_name":                   c.DatabaseName,
+			"master_username":                 c.MasterUsername,
+			"replication_source_identifier":   c.ReplicationSourceIdentifier,
+			"db_cluster_parameter_group_name": c.ParameterGroupName,
+			"kms_key_id":                      c.KmsKeyID,
+		})
+		if c.ManageMasterUserPassword {
+			attr["manage_master_user_password"] = true
+		}
+		if len(c.EnabledCloudwatchLogsExports) > 0 {
+			attr["enabled_cloudwatch_logs_exports"] = c.EnabledCloudwatchLogsExports
+		}
+
+		res := m.newNamedAwsResource("aws_rds_cluster", c.Identifier, newAwsLabels(c.Tags, region, c.VpcID), nameLabelsOr(c.Tags, c.Identifier), attr)
+		resources = append(resources, res)
+		relations = append(relations, dbRelations(res.ID, c.SubnetGroupName, c.SecurityGroupIDs, c.KmsKeyID)...)
+		relations = append(relations, rdsLogGroupRelations(res.ID, "cluster", c.Identifier, c.EnabledCloudwatchLogsExports)...)
+		if c.ParameterGroupName != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_rds_cluster_parameter_group", c.ParameterGroupName),
+				Kind: RelationDependsOn,
+			})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// MapDbInstance は RawDbInstance 一覧から Resource / Relation を生成する。
+// Aurora クラスターのメンバーは aws_rds_cluster_instance とする（ストレージ・認証情報はクラスター側で管理される）。
+// - Type: aws_db_instance, aws_rds_cluster_instance
+// - Relation: db_instance -> db_subnet_group (network), db_instance -> security_group (security)
+// - Relation: db_instance -> db_parameter_group / db_option_group (depends_on), db_instance -> kms_key (encryption)
+// - Relation: db_instance -> cloudwatch_log_group（ログのエクスポート先）(monitoring)
+// - Relation: rds_cluster_instance -> rds_cluster (depends_on)
+func (m *AwsToResourceMapper) MapDbInstance(instances []RawDbInstance, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, inst := range instances {
+		labels := newAwsLabels(inst.Tags, region, inst.VpcID)
+
+		if inst.ClusterIdentifier != "" {
+			attr := map[string]any{
+				"id":                  inst.Identifier,
+				"identifier":          inst.Identifier,
+				"cluster_identifier":  inst.ClusterIdentifier,
+				"instance_class":      inst.InstanceClass,
+				"engine":              inst.Engine,
+				"publicly_accessible": inst.PubliclyAccessible,
+				"tags":                inst.Tags,
+			}
+			setNonEmpty(attr, map[string]string{
+				"engine_version":          inst.EngineVersion,
+				"db_subnet_group_name":    inst.SubnetGroupName,
+				"db_parameter_group_name": inst.ParameterGroupName,
+				"availability_zone":       inst.AvailabilityZone,
+			})
+
+			res := m.newNamedAwsResource("aws_rds_cluster_instance", inst.Identifier, labels, nameLabelsOr(inst.Tags, inst.Identifier), attr)
+			resources = append(resources, res)
+			relations = append(relations, dbRelations(res.ID, inst.SubnetGroupName, nil, "")...)
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_rds_cluster", inst.ClusterIdentifier),
+				Kind: RelationDependsOn,
+			})
+			relations = append(relations, dbGroupRelations(res.ID, inst.ParameterGroupName, "")...)
+			continue
+		}
+
+		attr := map[string]any{
+			"id":                      inst.Identifier,
+			"identifier":              inst.Identifier,
+			"engine":                  inst.Engine,
+			"instance_class":          inst.InstanceClass,
+			"allocated_storage":       inst.AllocatedStorage,
+			"vpc_security_group_ids":  inst.SecurityGroupIDs,
+			"multi_az":                inst.MultiAZ,
+			"publicly_accessible":     inst.PubliclyAccessible,
+			"storage_encrypted":       inst.StorageEncrypted,
+			"backup_retention_period": inst.BackupRetentionPeriod,
+			"deletion_protection":     inst.DeletionProtection,
+			"tags":                    inst.Tags,
+		}
+		if inst.Port != 0 {
+			attr["port"] = inst.Port
+		}
+		if inst.MaxAllocatedStorage != 0 {
+			attr["max_allocated_storage"] = inst.MaxAllocatedStorage
+		}
+		if inst.Iops != 0 {
+			attr["iops"] = inst.Iops
+		}
+		setNonEmpty(attr, map[string]string{
+			"engine_version":               inst.EngineVersion,
+			"storage_type":                 inst.StorageType,
+			"preferred_backup_window":      inst.PreferredBackupWindow,
+			"preferred_maintenance_window": inst.PreferredMaintenanceWindow,
+			"db_name":                      inst.DBName,
+			"username":                     inst.MasterUsername,
+			"replicate_source_db":          inst.ReplicateSourceDB,
+			"db_subnet_group_name":         inst.SubnetGroupName,
+			"parameter_group_name":         inst.ParameterGroupName,
+			"option_group_name":            inst.OptionGroupName,
+			"kms_key_id":                   inst.KmsKeyID,
+		})
+		// Multi-AZ の場合、AZ はフェイルオーバーで変わるため指定しない
+		if !inst.MultiAZ && inst.AvailabilityZone != "" {
+			attr["availability_zone"] = inst.AvailabilityZone
+		}
+		if inst.ManageMasterUserPassword {
+			attr["manage_master_user_password"] = true
+		}
+		if len(inst.EnabledCloudwatchLogsExports) > 0 {
+			attr["enabled_cloudwatch_logs_exports"] = inst.EnabledCloudwatchLogsExports
+		}
+
+		res := m.newNamedAwsResource("aws_db_instance", inst.Identifier, labels, nameLabelsOr(inst.Tags, inst.Identifier), attr)
+		resources = append(resources, res)
+		relations = append(relations, dbRelations(res.ID, inst.SubnetGroupName, inst.SecurityGroupIDs, inst.KmsKeyID)...)
+		relations = append(relations, dbGroupRelations(res.ID, inst.ParameterGroupName, inst.OptionGroupName)...)
+		relations = append(relations, rdsLogGroupRelations(res.ID, "instance", inst.Identifier, inst.EnabledCloudwatchLogsExports)...)
+	}
+
+	return resources, relations, nil
+}
+
+// dbRelations は DB インスタンス / クラスターからサブネットグループ・セキュリティグループ・KMS キーへの Relation を返す。
+func dbRelations(from string, subnetGroupName string, sgIDs []string, kmsKeyID string) []Relation {
+	var relations []Relation
+	if subnetGroupName != "" {
+		relations = append(relations, Relation{
+			From: from,
+			To:   awsResourceID("aws_db_subnet_group", subnetGroupName),
+			Kind: RelationNetwork,
+		})
+	}
+	for _, id := range sgIDs {
+		relations = append(relations, Relation{
+			From: from,
+			To:   awsResourceID("aws_security_group", id),
+			Kind: RelationSecurity,
+		})
+	}
+	if to := kmsKeyResourceID(kmsKeyID); to != "" {
+		relations = append(relations, Relation{From: from, To: to, Kind: RelationEncryption})
+	}
+	return relations
+}
+
+// rdsLogGroupRelations は DB インスタンス / クラスターから、ログのエクスポート先のロググループへの Relation を返す。
+// ロググループ名は RDS が決める /aws/rds/<kind>/<識別子>/<ログの種類>（kind は "instance" / "cluster"）。
+func rdsLogGroupRelations(from string, kind string, identifier string, exports []string) []Relation {
+	var relations []Relation
+	for _, name := range RdsLogGroupNames(kind, identifier, exports) {
+		relations = append(relations, Relation{From: from, To: awsResourceID("aws_cloudwatch_log_group", name), Kind: RelationMonitoring})
+	}
+	return relations
+}
+
+// RdsLogGroupNames は DB インスタンス / クラスター（kind は "instance" / "cluster"）のログのエクスポート先のロググループ名を返す。
+func RdsLogGroupNames(kind string, identifier string, exports []string) []string {
+	names := make([]string, 0, len(exports))
+	for _, logType := range exports {
+		names = append(names, "/aws/rds/"+kind+"/"+identifier+"/"+logType)
+	}
+	return names
+}
+
+// dbGroupRelations は DB インスタンスからパラメータグループ・オプショングループへの Relation を返す。
+func dbGroupRelations(from string, parameterGroupName string, optionGroupName string) []Relation {
+	var relations []Relation
+	if parameterGroupName != "" {
+		relations = append(relations, Relation{
+			From: from,
+			To:   awsResourceID("aws_db_parameter_group", parameterGroupName),
+			Kind: RelationDependsOn,
+		})
+	}
+	if optionGroupName != "" {
+		relations = append(relations, Relation{
+			From: from,
+			To:   awsResourceID("aws_db_option_group", optionGroupName),
+			Kind: RelationDependsOn,
+		})
+	}
+	return relations
+}
+
+// kmsKeyResourceID は KMS キーの ARN（または キー ID）から aws_kms_key の Resource ID を返す。
+// エイリアス ARN など、キー ID を特定できない場合は空文字を返す。
+func kmsKeyResourceID(keyArn string) string {
+	if keyArn == "" || strings.Contains(keyArn, ":alias/") || strings.HasPrefix(keyArn, "alias/") {
+		return ""
+	}
+	return awsResourceID("aws_kms_key", keyArn[strings.LastIndex(keyArn, "/")+1:])
+}
+
+// setNonEmpty は values のうち空でない値のみを attr に設定する。
+func setNonEmpty(attr map[string]any, values map[string]string) {
+	for k, v := range values {
+		if v != "" {
+			attr[k] = v
+		}
+	}
+}
+
+// sortedStringKeys は m のキーをソートして返す。
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,256 @@
+package terraform
+
+import "strings"
+
+// RawRouteTable はルートテーブル向けの中間構造体。
+// ルート・関連付けは別リソース（aws_route / aws_route_table_association）として扱うため、
+// aws_route_table の属性には含めない。
+type RawRouteTable struct {
+	ID           string
+	VpcID        string
+	Tags         map[string]string
+	Routes       []RawRoute
+	Associations []RawRouteTableAssociation
+}
+
+// RawRoute はルートテーブルの 1 ルート。宛先はいずれか 1 つ、ターゲットは該当するもののみ設定される。
+// ローカルルートやルート伝播・ゲートウェイ型 VPC エンドポイントが作成したルートは含めない。
+type RawRoute struct {
+	DestinationCidrBlock     string
+	DestinationIpv6CidrBlock string
+	DestinationPrefixListID  string
+
+	GatewayID              string // インターネットゲートウェイ / 仮想プライベートゲートウェイ
+	EgressOnlyGatewayID    string
+	NatGatewayID           string
+	NetworkInterfaceID     string
+	VpcEndpointID          string // Gateway Load Balancer エンドポイント
+	VpcPeeringConnectionID string
+	TransitGatewayID       string
+	CarrierGatewayID       string
+	LocalGatewayID         string
+	CoreNetworkArn         string
+}
+
+// Destination はルートの宛先（CIDR / IPv6 CIDR / プレフィックスリスト ID）を返す。
+func (r RawRoute) Destination() string {
+	switch {
+	case r.DestinationCidrBlock != "":
+		return r.DestinationCidrBlock
+	case r.DestinationIpv6CidrBlock != "":
+		return r.DestinationIpv6CidrBlock
+	default:
+		return r.DestinationPrefixListID
+	}
+}
+
+// RawRouteTableAssociation はルートテーブルの関連付け。
+// Main が true の場合は VPC のメインルートテーブルであることを表し、SubnetID / GatewayID は空。
+type RawRouteTableAssociation struct {
+	ID        string
+	SubnetID  string
+	GatewayID string
+	Main      bool
+}
+
+// MapRouteTable は RawRouteTable 一覧から Resource / Relation を生成する。
+// - Type: aws_route_table, aws_route, aws_route_table_association, aws_main_route_table_association
+// - Relation: route_table -> vpc (network), route -> route_table / ルートのターゲット (network)
+// - Relation: route_table_association -> route_table / subnet / gateway (network)
+func (m *AwsToResourceMapper) MapRouteTable(tables []RawRouteTable, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, t := range tables {
+		labels := newAwsLabels(t.Tags, region, t.VpcID)
+
+		attr := map[string]any{
+			"id":     t.ID,
+			"vpc_id": t.VpcID,
+			"tags":   t.Tags,
+		}
+
+		res := m.newAwsResource("aws_route_table", t.ID, labels, attr)
+		resources = append(resources, res)
+
+		if t.VpcID != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_vpc", t.VpcID),
+				Kind: RelationNetwork,
+			})
+		}
+
+		for _, route := range t.Routes {
+			routeRes, routeRels := m.mapRoute(t, route, res, region)
+			resources = append(resources, routeRes)
+			relations = append(relations, routeRels...)
+		}
+		for _, assoc := range t.Associations {
+			assocRes, assocRels := m.mapRouteTableAssociation(t, assoc, res, region)
+			resources = append(resources, assocRes)
+			relations = append(relations, assocRels...)
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// routeTableBaseName はルート・関連付けの論理名のベースにするルートテーブル名（Name タグまたは ID）を返す。
+func routeTableBaseName(t RawRouteTable) string {
+	if name := t.Tags["Name"]; name != "" {
+		return name
+	}
+	return t.ID
+}
+
+// gatewayResourceType はゲートウェイ ID（igw- / vgw-）に対応するリソースタイプを返す。
+func gatewayResourceType(gatewayID string) string {
+	if strings.HasPrefix(gatewayID, "vgw-") {
+		return "aws_vpn_gateway"
+	}
+	return "aws_internet_gateway"
+}
+
+// routeTarget はルートのターゲット属性名・参照先リソースタイプ・ID の組。
+type routeTarget struct {
+	attr         string
+	resourceType string
+	id           string
+}
+
+// routeTargetsOf はルートに設定されているターゲットを返す。
+func routeTargetsOf(route RawRoute) []routeTarget {
+	targets := []routeTarget{
+		{"gateway_id", gatewayResourceType(route.GatewayID), route.GatewayID},
+		{"egress_only_gateway_id", "aws_egress_only_internet_gateway", route.EgressOnlyGatewayID},
+		{"nat_gateway_id", "aws_nat_gateway", route.NatGatewayID},
+		{"network_interface_id", "aws_network_interface", route.NetworkInterfaceID},
+		{"vpc_endpoint_id", "aws_vpc_endpoint", route.VpcEndpointID},
+		{"vpc_peering_connection_id", "aws_vpc_peering_connection", route.VpcPeeringConnectionID},
+		{"transit_gateway_id", "aws_ec2_transit_gateway", route.TransitGatewayID},
+		{"carrier_gateway_id", "aws_ec2_carrier_gateway", route.CarrierGatewayID},
+		{"local_gateway_id", "", route.LocalGatewayID},
+		{"core_network_arn", "", route.CoreNetworkArn},
+	}
+	var out []routeTarget
+	for _, t := range targets {
+		if t.id != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// mapRoute は 1 つのルートから aws_route の Resource / Relation を生成する。
+// import ID は "<route_table_id>_<destination>" 形式。
+func (m *AwsToResourceMapper) mapRoute(t RawRouteTable, route RawRoute, table Resource, region string) (Resource, []Relation) {
+	importID := t.ID + "_" + route.Destination()
+
+	attr := map[string]any{
+		"id":             importID,
+		"route_table_id": t.ID,
+	}
+	switch {
+	case route.DestinationCidrBlock != "":
+		attr["destination_cidr_block"] = route.DestinationCidrBlock
+	case route.DestinationIpv6CidrBlock != "":
+		attr["destination_ipv6_cidr_block"] = route.DestinationIpv6CidrBlock
+	default:
+		attr["destination_prefix_list_id"] = route.DestinationPrefixListID
+	}
+
+	nameLabels := map[string]string{"Name": routeTableBaseName(t) + "_" + route.Destination()}
+
+	// Labels はタグフィルタでルートテーブルと同じ扱いになるよう、ルートテーブルのタグを引き継ぐ
+	res := Resource{
+		ID:         awsResourceID("aws_route", importID),
+		Provider:   "aws",
+		Type:       "aws_route",
+		Name:       m.nameGenerator.Generate("aws_route", nameLabels, importID),
+		Labels:     newAwsLabels(t.Tags, region, t.VpcID),
+		Attributes: attr,
+		Origin:     OriginCloud,
+	}
+
+	relations := []Relation{{From: res.ID, To: table.ID, Kind: RelationNetwork}}
+	for _, target := range routeTargetsOf(route) {
+		attr[target.attr] = target.id
+		switch {
+		case target.resourceType == "aws_vpc_peering_connection":
+			// 自 VPC がリクエスタ側・アクセプタ側のどちらでも参照を解決できるよう、両方の Resource に関係を張る
+			for _, id := range vpcPeeringResourceIDs(target.id) {
+				relations = append(relations, Relation{From: res.ID, To: id, Kind: RelationNetwork})
+			}
+		case target.resourceType != "":
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID(target.resourceType, target.id),
+				Kind: RelationNetwork,
+			})
+		}
+	}
+	return res, relations
+}
+
+// mapRouteTableAssociation はルートテーブルの関連付けから Resource / Relation を生成する。
+// サブネット / ゲートウェイとの関連付けは aws_route_table_association（import ID は "subnet-xxx/rtb-xxx" / "igw-xxx/rtb-xxx"）、
+// メインルートテーブルは aws_main_route_table_association とする。
+//
+// aws_main_route_table_association は terraform import をサポートしないため id を持たせない
+// （import スクリプトには含まれず、apply 時に現在と同じ関連付けとして作成される）。
+func (m *AwsToResourceMapper) mapRouteTableAssociation(t RawRouteTable, assoc RawRouteTableAssociation, table Resource, region string) (Resource, []Relation) {
+	labels := newAwsLabels(t.Tags, region, t.VpcID)
+
+	if assoc.Main {
+		attr := map[string]any{
+			"vpc_id":         t.VpcID,
+			"route_table_id": t.ID,
+		}
+		res := Resource{
+			ID:         awsResourceID("aws_main_route_table_association", assoc.ID),
+			Provider:   "aws",
+			Type:       "aws_main_route_table_association",
+			Name:       m.nameGenerator.Generate("aws_main_route_table_association", map[string]string{"Name": routeTableBaseName(t) + "_main"}, assoc.ID),
+			Labels:     labels,
+			Attributes: attr,
+			Origin:     OriginCloud,
+		}
+		return res, []Relation{
+			{From: res.ID, To: table.ID, Kind: RelationNetwork},
+			{From: res.ID, To: awsResourceID("aws_vpc", t.VpcID), Kind: RelationNetwork},
+		}
+	}
+
+	attr := map[string]any{
+		"route_table_id": t.ID,
+	}
+	var target string
+	var relations []Relation
+	if assoc.SubnetID != "" {
+		target = assoc.SubnetID
+		attr["subnet_id"] = assoc.SubnetID
+	} else {
+		target = assoc.GatewayID
+		attr["gateway_id"] = assoc.GatewayID
+	}
+	importID := target + "/" + t.ID
+	attr["id"] = importID
+
+	res := Resource{
+		ID:         awsResourceID("aws_route_table_association", importID),
+		Provider:   "aws",
+		Type:       "aws_route_table_association",
+		Name:       m.nameGenerator.Generate("aws_route_table_association", map[string]string{"Name": routeTableBaseName(t) + "_" + target}, importID),
+		Labels:     labels,
+		Attributes: attr,
+		Origin:     OriginCloud,
+	}
+	relations = append(relations, Relation{From: res.ID, To: table.ID, Kind: RelationNetwork})
+	if assoc.SubnetID != "" {
+		relations = append(relations, Relation{From: res.ID, To: awsResourceID("aws_subnet", assoc.SubnetID), Kind: RelationNetwork})
+	} else {
+		relations = append(relations, Relation{From: res.ID, To: awsResourceID(gatewayResourceType(assoc.GatewayID), assoc.GatewayID), Kind: RelationNetwork})
+	}
+	return res, relations
+}
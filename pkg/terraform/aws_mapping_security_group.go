@@ -0,0 +1,172 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RawSecurityGroup はセキュリティグループ向けの中間構造体。
+// ルールは別リソース（aws_vpc_security_group_ingress_rule / egress_rule）として扱うため、
+// aws_security_group の属性には含めない。
+type RawSecurityGroup struct {
+	ID          string
+	VpcID       string
+	GroupName   string
+	Description string
+	Tags        map[string]string
+	Rules       []RawSecurityGroupRule
+}
+
+// RawSecurityGroupRule はセキュリティグループの 1 ルール（DescribeSecurityGroupRules の 1 要素）。
+// 送信元 / 宛先は CidrIpv4 / CidrIpv6 / PrefixListID / ReferencedGroupID のいずれか 1 つが設定される。
+type RawSecurityGroupRule struct {
+	ID                string // sgr-xxx
+	GroupID           string
+	IsEgress          bool
+	IpProtocol        string // "tcp" / "udp" / "icmp" / "-1" など
+	FromPort          int32
+	ToPort            int32
+	CidrIpv4          string
+	CidrIpv6          string
+	PrefixListID      string
+	ReferencedGroupID string
+	Description       string
+	Tags              map[string]string
+}
+
+// ResourceType はルールに対応する Terraform のリソースタイプを返す。
+func (r RawSecurityGroupRule) ResourceType() string {
+	if r.IsEgress {
+		return "aws_vpc_security_group_egress_rule"
+	}
+	return "aws_vpc_security_group_ingress_rule"
+}
+
+// MapSecurityGroup は RawSecurityGroup 一覧から Resource / Relation を生成する。
+// - Type: aws_security_group, aws_vpc_security_group_ingress_rule, aws_vpc_security_group_egress_rule
+// - Relation: security_group -> vpc (network)
+// - Relation: security_group_rule -> security_group / referenced_security_group (security)
+//
+// ルールをインラインの ingress / egress ブロックにまとめる場合は InlineSecurityGroupRules を利用する。
+func (m *AwsToResourceMapper) MapSecurityGroup(groups []RawSecurityGroup, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, g := range groups {
+		labels := newAwsLabels(g.Tags, region, g.VpcID)
+
+		attr := map[string]any{
+			"id":          g.ID,
+			"name":        g.GroupName,
+			"description": g.Description,
+			"vpc_id":      g.VpcID,
+			"tags":        g.Tags,
+		}
+
+		// Name タグがない場合は GroupName を論理名のベースにする
+		nameLabels := labels
+		if g.Tags["Name"] == "" && g.GroupName != "" {
+			nameLabels = map[string]string{"Name": g.GroupName}
+		}
+
+		res := Resource{
+			ID:         awsResourceID("aws_security_group", g.ID),
+			Provider:   "aws",
+			Type:       "aws_security_group",
+			Name:       m.nameGenerator.Generate("aws_security_group", nameLabels, g.ID),
+			Labels:     labels,
+			Attributes: attr,
+			Origin:     OriginCloud,
+		}
+		resources = append(resources, res)
+
+		if g.VpcID != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_vpc", g.VpcID),
+				Kind: RelationNetwork,
+			})
+		}
+
+		for _, rule := range g.Rules {
+			ruleRes, ruleRels := m.mapSecurityGroupRule(g, rule, res, region)
+			resources = append(resources, ruleRes)
+			relations = append(relations, ruleRels...)
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// mapSecurityGroupRule は 1 つのルールから aws_vpc_security_group_ingress_rule / egress_rule の Resource / Relation を生成する。
+// import ID はルール ID（sgr-xxx）。
+func (m *AwsToResourceMapper) mapSecurityGroupRule(g RawSecurityGroup, rule RawSecurityGroupRule, group Resource, region string) (Resource, []Relation) {
+	resourceType := rule.ResourceType()
+
+	attr := map[string]any{
+		"id":                rule.ID,
+		"security_group_id": g.ID,
+		"ip_protocol":       rule.IpProtocol,
+		"tags":              rule.Tags,
+	}
+	// 全プロトコル（-1）の場合、ポートは指定できない
+	if rule.IpProtocol != "-1" {
+		attr["from_port"] = rule.FromPort
+		attr["to_port"] = rule.ToPort
+	}
+	for key, val := range map[string]string{
+		"cidr_ipv4":                    rule.CidrIpv4,
+		"cidr_ipv6":                    rule.CidrIpv6,
+		"prefix_list_id":               rule.PrefixListID,
+		"referenced_security_group_id": rule.ReferencedGroupID,
+		"description":                  rule.Description,
+	} {
+		if val != "" {
+			attr[key] = val
+		}
+	}
+
+	nameLabels := map[string]string{"Name": securityGroupRuleBaseName(group.Name, rule)}
+	if name := rule.Tags["Name"]; name != "" {
+		nameLabels["Name"] = name
+	}
+
+	// Labels はタグフィルタでセキュリティグループと同じ扱いになるよう、セキュリティグループのタグを引き継ぐ
+	res := Resource{
+		ID:         awsResourceID(resourceType, rule.ID),
+		Provider:   "aws",
+		Type:       resourceType,
+		Name:       m.nameGenerator.Generate(resourceType, nameLabels, rule.ID),
+		Labels:     newAwsLabels(g.Tags, region, g.VpcID),
+		Attributes: attr,
+		Origin:     OriginCloud,
+	}
+
+	relations := []Relation{{From: res.ID, To: group.ID, Kind: RelationSecurity}}
+	if rule.ReferencedGroupID != "" && rule.ReferencedGroupID != g.ID {
+		relations = append(relations, Relation{
+			From: res.ID,
+			To:   awsResourceID("aws_security_group", rule.ReferencedGroupID),
+			Kind: RelationSecurity,
+		})
+	}
+	return res, relations
+}
+
+// securityGroupRuleBaseName はルールの論理名のベース（"<SG 名>_ingress_tcp_443" など）を返す。
+func securityGroupRuleBaseName(groupName string, rule RawSecurityGroupRule) string {
+	direction := "ingress"
+	if rule.IsEgress {
+		direction = "egress"
+	}
+	parts := []string{groupName, direction}
+	switch {
+	case rule.IpProtocol == "-1":
+		parts = append(parts, "all")
+	case rule.FromPort == rule.ToPort:
+		parts = append(parts, rule.IpProtocol, fmt.Sprint(rule.FromPort))
+	default:
+		parts = append(parts, rule.IpProtocol, fmt.Sprintf("%d_%d", rule.FromPort, rule.ToPort))
+	}
+	return strings.Join(parts, "_")
+}
@@ -0,0 +1,140 @@
+package terraform
+
+import (
+	"sort"
+	"testing"
+)
+
+// TestMapRelations は ECS / IAM / KMS の mapper が出力する Resource（data ソースかどうかを含む）と Relation を確認する。
+func TestMapRelations(t *testing.T) {
+	m := NewAwsToResourceMapper(nil)
+
+	tests := []struct {
+		name string
+		run  func() ([]Resource, []Relation, error)
+		// wantResources は Resource.ID ごとの data ソースかどうか
+		wantResources map[string]bool
+		wantRelations []Relation
+	}{
+		{
+			name: "ecs cluster",
+			run: func() ([]Resource, []Relation, error) {
+				return m.MapEcsCluster([]RawEcsCluster{{
+					Arn:               "arn:aws:ecs:ap-northeast-1:111122223333:cluster/web",
+					Name:              "web",
+					CapacityProviders: []string{"FARGATE", "ec2-cp"},
+				}}, []RawEcsCapacityProvider{{
+					Arn:                 "arn:aws:ecs:ap-northeast-1:111122223333:capacity-provider/ec2-cp",
+					Name:                "ec2-cp",
+					AutoScalingGroupArn: "arn:aws:autoscaling:ap-northeast-1:111122223333:autoScalingGroup:uuid:autoScalingGroupName/ecs-asg",
+				}}, "ap-northeast-1")
+			},
+			wantResources: map[string]bool{
+				"aws:aws_ecs_capacity_provider:ec2-cp":       false,
+				"aws:aws_ecs_cluster:web":                    false,
+				"aws:aws_ecs_cluster_capacity_providers:web": false,
+			},
+			wantRelations: []Relation{
+				{From: "aws:aws_ecs_capacity_provider:ec2-cp", To: "aws:aws_autoscaling_group:ecs-asg", Kind: RelationDependsOn},
+				{From: "aws:aws_ecs_cluster_capacity_providers:web", To: "aws:aws_ecs_capacity_provider:ec2-cp", Kind: RelationDependsOn},
+				{From: "aws:aws_ecs_cluster_capacity_providers:web", To: "aws:aws_ecs_cluster:web", Kind: RelationDependsOn},
+			},
+		},
+		{
+			name: "iam role and instance profile",
+			run: func() ([]Resource, []Relation, error) {
+				return m.MapIamRole([]RawIamRole{{
+					Name:               "app",
+					Arn:                "arn:aws:iam::111122223333:role/app",
+					InlinePolicies:     []RawIamRolePolicy{{Name: "s3", Policy: "{}"}},
+					AttachedPolicyArns: []string{"arn:aws:iam::aws:policy/ReadOnlyAccess"},
+				}}, []RawIamInstanceProfile{{
+					Name:     "app",
+					Arn:      "arn:aws:iam::111122223333:instance-profile/app",
+					RoleName: "app",
+				}}, "ap-northeast-1", "vpc-1")
+			},
+			wantResources: map[string]bool{
+				"aws:aws_iam_instance_profile:app": false,
+				"aws:aws_iam_role:app":             false,
+				"aws:aws_iam_role_policy:app:s3":   false,
+				"aws:aws_iam_role_policy_attachment:app/arn:aws:iam::aws:policy/ReadOnlyAccess": false,
+			},
+			wantRelations: []Relation{
+				{From: "aws:aws_iam_instance_profile:app", To: "aws:aws_iam_role:app", Kind: RelationIAM},
+				{From: "aws:aws_iam_role_policy:app:s3", To: "aws:aws_iam_role:app", Kind: RelationDependsOn},
+				{From: "aws:aws_iam_role_policy_attachment:app/arn:aws:iam::aws:policy/ReadOnlyAccess", To: "aws:aws_iam_role:app", Kind: RelationDependsOn},
+			},
+		},
+		{
+			name: "kms customer and aws managed keys",
+			run: func() ([]Resource, []Relation, error) {
+				return m.MapKmsKey([]RawKmsKey{
+					{
+						KeyID:     "key-app",
+						Arn:       "arn:aws:kms:ap-northeast-1:111122223333:key/key-app",
+						Enabled:   true,
+						Aliases:   []RawKmsAlias{{Name: "alias/app"}},
+						Referrers: []RawKmsReferrer{{ResourceID: "aws:aws_db_instance:db", Alias: "alias/app"}},
+					},
+					{
+						KeyID:      "key-rds",
+						Arn:        "arn:aws:kms:ap-northeast-1:111122223333:key/key-rds",
+						AwsManaged: true,
+						Enabled:    true,
+						Aliases:    []RawKmsAlias{{Name: "alias/aws/rds"}},
+						Referrers:  []RawKmsReferrer{{ResourceID: "aws:aws_rds_cluster:aurora"}},
+					},
+				}, "ap-northeast-1", "vpc-1")
+			},
+			wantResources: map[string]bool{
+				"aws:aws_kms_alias:alias/app":     false,
+				"aws:aws_kms_alias:alias/aws/rds": true,
+				"aws:aws_kms_key:key-app":         false,
+			},
+			wantRelations: []Relation{
+				{From: "aws:aws_db_instance:db", To: "aws:aws_kms_alias:alias/app", Kind: RelationEncryption},
+				{From: "aws:aws_kms_alias:alias/app", To: "aws:aws_kms_key:key-app", Kind: RelationDependsOn},
+				{From: "aws:aws_rds_cluster:aurora", To: "aws:aws_kms_alias:alias/aws/rds", Kind: RelationEncryption},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs, rels, err := tt.run()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(rs) != len(tt.wantResources) {
+				t.Errorf("got %d resources, want %d", len(rs), len(tt.wantResources))
+			}
+			for _, r := range rs {
+				data, ok := tt.wantResources[r.ID]
+				if !ok {
+					t.Errorf("unexpected resource %s", r.ID)
+					continue
+				}
+				if r.IsDataSource() != data {
+					t.Errorf("%s: IsDataSource = %t, want %t", r.ID, r.IsDataSource(), data)
+				}
+			}
+
+			sort.Slice(rels, func(i, j int) bool {
+				if rels[i].From != rels[j].From {
+					return rels[i].From < rels[j].From
+				}
+				return rels[i].To < rels[j].To
+			})
+			if len(rels) != len(tt.wantRelations) {
+				t.Fatalf("got relations %+v, want %+v", rels, tt.wantRelations)
+			}
+			for i, rel := range rels {
+				if rel != tt.wantRelations[i] {
+					t.Errorf("relation %d = %+v, want %+v", i, rel, tt.wantRelations[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package terraform
+
+// RawVpcPeeringConnection は VPC ピアリング接続向けの中間構造体。
+// VpcID はディスカバリ対象 VPC 側、Peer* は相手側の情報。
+// IsAccepter は対象 VPC がアクセプタ側（相手からの接続要求を受け入れた側）かどうか。
+type RawVpcPeeringConnection struct {
+	ID          string
+	VpcID       string
+	PeerVpcID   string
+	PeerOwnerID string
+	PeerRegion  string
+	IsAccepter  bool
+	Tags        map[string]string
+}
+
+// RawTransitGatewayVpcAttachment は Transit Gateway の VPC アタッチメント向けの中間構造体。
+type RawTransitGatewayVpcAttachment struct {
+	ID                   string
+	VpcID                string
+	TransitGatewayID     string
+	SubnetIDs            []string
+	DnsSupport           string
+	Ipv6Support          string
+	ApplianceModeSupport string
+	Tags                 map[string]string
+}
+
+// vpcPeeringResourceIDs は VPC ピアリング接続 ID に対応しうる Resource.ID（リクエスタ側・アクセプタ側）を返す。
+func vpcPeeringResourceIDs(peeringID string) []string {
+	return []string{
+		awsResourceID("aws_vpc_peering_connection", peeringID),
+		awsResourceID("aws_vpc_peering_connection_accepter", peeringID),
+	}
+}
+
+// MapVpcPeeringConnection は RawVpcPeeringConnection 一覧から Resource / Relation を生成する。
+// - Type: aws_vpc_peering_connection（対象 VPC がリクエスタ側）、aws_vpc_peering_connection_accepter（アクセプタ側）
+// - Relation: vpc_peering_connection(_accepter) -> vpc (network), accepter -> vpc_peering_connection (network)
+//
+// 両側の VPC をディスカバリした場合は、アクセプタ側の vpc_peering_connection_id がリクエスタ側の参照になる。
+func (m *AwsToResourceMapper) MapVpcPeeringConnection(conns []RawVpcPeeringConnection, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+
+	for _, c := range conns {
+		labels := newAwsLabels(c.Tags, region, c.VpcID)
+
+		var res Resource
+		if c.IsAccepter {
+			attr := map[string]any{
+				"id":                        c.ID,
+				"vpc_peering_connection_id": c.ID,
+				"auto_accept":               true,
+				"tags":                      c.Tags,
+			}
+			res = m.newAwsResource("aws_vpc_peering_connection_accepter", c.ID, labels, attr)
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_vpc_peering_connection", c.ID),
+				Kind: RelationNetwork,
+			})
+		} else {
+			attr := map[string]any{
+				"id":          c.ID,
+				"vpc_id":      c.VpcID,
+				"peer_vpc_id": c.PeerVpcID,
+				"tags":        c.Tags,
+			}
+			if c.PeerOwnerID != "" {
+				attr["peer_owner_id"] = c.PeerOwnerID
+			}
+			// 同一リージョンのピアリングでは peer_region を指定しない
+			if c.PeerRegion != "" && c.PeerRegion != region {
+				attr["peer_region"] = c.PeerRegion
+			}
+			res = m.newAwsResource("aws_vpc_peering_connection", c.ID, labels, attr)
+		}
+		resources = append(resources, res)
+
+		if c.VpcID != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_vpc", c.VpcID),
+				Kind: RelationNetwork,
+			})
+		}
+	}
+
+	return resources, relations, nil
+}
+
+// MapTransitGatewayVpcAttachment は RawTransitGatewayVpcAttachment 一覧から Resource / Relation を生成する。
+// - Type: aws_ec2_transit_gateway_vpc_attachment、aws_ec2_transit_gateway（data）
+// - Relation: attachment -> vpc / subnet / transit_gateway (network)
+//
+// Transit Gateway 自体は VPC のスコープ外で管理されるため import せず、data ブロックで参照する。
+func (m *AwsToResourceMapper) MapTransitGatewayVpcAttachment(attachments []RawTransitGatewayVpcAttachment, region string) ([]Resource, []Relation, error) {
+	var resources []Resource
+	var relations []Relation
+	seenGateways := make(map[string]bool)
+
+	for _, a := range attachments {
+		labels := newAwsLabels(a.Tags, region, a.VpcID)
+
+		attr := map[string]any{
+			"id":                 a.ID,
+			"vpc_id":             a.VpcID,
+			"transit_gateway_id": a.TransitGatewayID,
+			"tags":               a.Tags,
+		}
+		if len(a.SubnetIDs) > 0 {
+			attr["subnet_ids"] = a.SubnetIDs
+		}
+		if a.DnsSupport != "" {
+			attr["dns_support"] = a.DnsSupport
+		}
+		if a.Ipv6Support != "" {
+			attr["ipv6_support"] = a.Ipv6Support
+		}
+		if a.ApplianceModeSupport != "" {
+			attr["appliance_mode_support"] = a.ApplianceModeSupport
+		}
+
+		res := m.newAwsResource("aws_ec2_transit_gateway_vpc_attachment", a.ID, labels, attr)
+		resources = append(resources, res)
+
+		if a.VpcID != "" {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_vpc", a.VpcID),
+				Kind: RelationNetwork,
+			})
+		}
+		for _, id := range a.SubnetIDs {
+			relations = append(relations, Relation{
+				From: res.ID,
+				To:   awsResourceID("aws_subnet", id),
+				Kind: RelationNetwork,
+			})
+		}
+
+		if a.TransitGatewayID == "" {
+			continue
+		}
+		relations = append(relations, Relation{
+			From: res.ID,
+			To:   awsResourceID("aws_ec2_transit_gateway", a.TransitGatewayID),
+			Kind: RelationNetwork,
+		})
+		if !seenGateways[a.TransitGatewayID] {
+			seenGateways[a.TransitGatewayID] = true
+			resources = append(resources, m.newAwsDataSource("aws_ec2_transit_gateway", a.TransitGatewayID, newAwsLabels(nil, region, "")))
+		}
+	}
+
+	return resources, relations, nil
+}
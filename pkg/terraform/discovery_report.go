@@ -0,0 +1,80 @@
+package terraform
+
+import "time"
+
+// ListerStatus は 1 つの lister（ListXXX）の実行結果の状態を表す。
+type ListerStatus string
+
+const (
+	ListerStatusOK      ListerStatus = "ok"
+	ListerStatusFailed  ListerStatus = "failed"
+	ListerStatusSkipped ListerStatus = "skipped" // 権限不足（AccessDenied 等）・クライアント未設定によりスキップ
+)
+
+// ListerReport は 1 つの lister の実行結果を表す。
+type ListerReport struct {
+	Name          string        `json:"name"`             // 主に出力するリソースタイプ名 (例: "aws_subnet")
+	Region        string        `json:"region,omitempty"` // 複数 VPC / リージョンを列挙した場合の対象
+	VpcID         string        `json:"vpcId,omitempty"`
+	Status        ListerStatus  `json:"status"`
+	Err           error         `json:"-"`
+	Error         string        `json:"error,omitempty"` // Err のメッセージ（シリアライズ用）
+	Duration      time.Duration `json:"duration"`
+	ResourceCount int           `json:"resourceCount"`
+	RelationCount int           `json:"relationCount"`
+
+	// API 呼び出し統計（リトライ・スロットリング制御を有効にしている場合のみ計上）
+	APICalls  int64 `json:"apiCalls,omitempty"`
+	Retries   int64 `json:"retries,omitempty"`
+	Throttled int64 `json:"throttled,omitempty"`
+
+	// CacheHit は結果をディスカバリキャッシュから取得した（AWS API を呼び出していない）ことを表す。
+	CacheHit bool `json:"cacheHit,omitempty"`
+}
+
+// DiscoveryReport はクラウド側リソース列挙全体の実行結果を表す。
+// 一部の lister が失敗しても列挙全体は継続し、その結果をここに記録する。
+type DiscoveryReport struct {
+	Listers []ListerReport `json:"listers"`
+}
+
+// CountByStatus は指定した状態の lister 数を返す。
+func (r DiscoveryReport) CountByStatus(status ListerStatus) int {
+	n := 0
+	for _, l := range r.Listers {
+		if l.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// APICallTotals は全 lister の API 呼び出し数・リトライ数・スロットリング数の合計を返す。
+func (r DiscoveryReport) APICallTotals() (calls int64, retries int64, throttled int64) {
+	for _, l := range r.Listers {
+		calls += l.APICalls
+		retries += l.Retries
+		throttled += l.Throttled
+	}
+	return calls, retries, throttled
+}
+
+// CacheHits は結果をディスカバリキャッシュから取得した lister 数を返す。
+func (r DiscoveryReport) CacheHits() int {
+	n := 0
+	for _, l := range r.Listers {
+		if l.CacheHit {
+			n++
+		}
+	}
+	return n
+}
+
+// Target は lister の対象（"<region>/<vpc-id>"）を返す。
+// 対象が記録されていない場合は空文字を返す。
+func (l ListerReport) Target() string {
+	if l.Region == "" && l.VpcID == "" {
+		return ""
+	}
+	return l.Region + "/" + l.VpcID
+}
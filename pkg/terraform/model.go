@@ -39,6 +39,21 @@ const (
 // HclGenerator などで、この型の値はクォートせずにそのまま埋め込まれる。
 type HCLExpression string
 
+// HCLBlock は Terraform HCL のネストしたブロック（ingress { ... } など）を表す。
+// Resource.Attributes の値を []HCLBlock にすると、HclGenerator はキー名のブロックを要素数分出力する。
+type HCLBlock map[string]any
+
+// ResourceMode は Terraform 上で resource（管理対象）と data（参照のみ）のどちらとして扱うかを表す。
+type ResourceMode string
+
+const (
+	// ResourceModeManaged は resource ブロックとして出力し、terraform import の対象とする（デフォルト）。
+	ResourceModeManaged ResourceMode = "managed"
+	// ResourceModeData は data ブロックとして出力し、import しない。
+	// スコープ外で管理されているリソース（共有 Transit Gateway など）の参照に利用する。
+	ResourceModeData ResourceMode = "data"
+)
+
 // Resource はクラウド / Terraform 双方で利用する共通リソースモデル。
 // system_design.md / vpc_import_basic_design.md に記載のフィールド構成に対応する。
 type Resource struct {
@@ -49,6 +64,12 @@ type Resource struct {
 	Labels     map[string]string `json:"labels,omitempty"`     // タグやメタデータ ("Name", "Env" など)
 	Attributes map[string]any    `json:"attributes,omitempty"` // 追加属性（初期は汎用マップ）
 	Origin     Origin            `json:"origin"`               // 由来 (cloud / terraform_config / terraform_state)
+	Mode       ResourceMode      `json:"mode,omitempty"`       // 空の場合は managed
+}
+
+// IsDataSource は Resource を data ブロックとして扱うかどうかを返す。
+func (r Resource) IsDataSource() bool {
+	return r.Mode == ResourceModeData
 }
 
 // Relation は 2 つの Resource 間の関係を表す。
@@ -67,11 +88,62 @@ type ResourceFilter struct {
 
 // DiscoveryScope はクラウド側リソース列挙のスコープを表す。
 // F-01 詳細設計の DiscoveryScope に対応。
+//
+// 複数 VPC / 複数リージョンを対象にする場合は VpcIDs / Regions を利用する。
+// AllVpcs が true の場合は、対象リージョン内のすべての VPC を列挙する。
+// 別アカウントの VPC を対象にする場合は、AssumeRoles に引き受けるロールを順に指定する。
+// LocalStack / moto などのエミュレータを対象にする場合は、EndpointURL / ServiceEndpoints と
+// StaticCredentials を指定する。
 type DiscoveryScope struct {
 	VpcID          string           `json:"vpcId"`
 	Region         string           `json:"region"`
+	VpcIDs         []string         `json:"vpcIds,omitempty"`
+	Regions        []string         `json:"regions,omitempty"`
+	AllVpcs        bool             `json:"allVpcs,omitempty"`
 	Profile        string           `json:"profile,omitempty"`
+	AssumeRoles    []AssumeRole     `json:"assumeRoles,omitempty"`
+	// EndpointURL は全サービス共通のエンドポイント URL。ServiceEndpoints はサービス名 (例: "ec2") ごとの上書き。
+	EndpointURL      string            `json:"endpointUrl,omitempty"`
+	ServiceEndpoints map[string]string `json:"serviceEndpoints,omitempty"`
+	// StaticCredentials はスナップショット等に書き出さない。
+	StaticCredentials *StaticCredentials `json:"-"`
 	ResourceFilters []ResourceFilter `json:"resourceFilters,omitempty"`
 }
 
+// StaticCredentials は固定のアクセスキーによるクレデンシャル（主にエミュレータ向けのテスト用）。
+type StaticCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// VpcIDList は VpcID と VpcIDs を重複なく結合した VPC ID 一覧を返す。
+func (s DiscoveryScope) VpcIDList() []string {
+	return mergeUnique(s.VpcID, s.VpcIDs)
+}
+
+// RegionList は Region と Regions を重複なく結合したリージョン一覧を返す。
+func (s DiscoveryScope) RegionList() []string {
+	return mergeUnique(s.Region, s.Regions)
+}
+
+// IsMultiRegion は複数リージョンを対象とするスコープかどうかを返す。
+func (s DiscoveryScope) IsMultiRegion() bool {
+	return len(s.RegionList()) > 1
+}
+
+// mergeUnique は first と rest を、空文字と重複を除いて順序を保ったまま結合する。
+func mergeUnique(first string, rest []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, v := range append([]string{first}, rest...) {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
+
 
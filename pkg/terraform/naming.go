@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // NameGenerator は Terraform のリソース論理名を生成するためのインターフェース。
@@ -18,14 +19,19 @@ type NameGenerator interface {
 // - Name タグがあればそれをベースにする
 // - なければ <type>_<short-id> 形式
 // - 衝突時は _1, _2 ... のサフィックスを付与
+//
+// Terraform のアドレスは <type>.<name> で一意になればよいため、衝突判定はリソースタイプごとに行う。
+// これにより、並列ディスカバリでも各タイプ内の生成順が保たれる限り名前は決定的になる。
+// 複数 goroutine から同時に呼び出してもよい。
 type DefaultNameGenerator struct {
-	used map[string]int
+	mu   sync.Mutex
+	used map[string]map[string]int
 }
 
 // NewDefaultNameGenerator は DefaultNameGenerator を生成する。
 func NewDefaultNameGenerator() *DefaultNameGenerator {
 	return &DefaultNameGenerator{
-		used: make(map[string]int),
+		used: make(map[string]map[string]int),
 	}
 }
 
@@ -49,14 +55,23 @@ func (g *DefaultNameGenerator) Generate(resourceType string, labels map[string]s
 
 	sanitized := sanitizeTerraformIdentifier(base)
 
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	used, ok := g.used[resourceType]
+	if !ok {
+		used = make(map[string]int)
+		g.used[resourceType] = used
+	}
+
 	// 衝突回避
-	if count, ok := g.used[sanitized]; ok {
+	if count, ok := used[sanitized]; ok {
 		count++
-		g.used[sanitized] = count
+		used[sanitized] = count
 		return sanitized + "_" + itoa(count)
 	}
 
-	g.used[sanitized] = 0
+	used[sanitized] = 0
 	return sanitized
 }
 
@@ -0,0 +1,122 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NetworkAclRuleMode はネットワーク ACL のルールを HCL 上でどう表現するかを表す。
+type NetworkAclRuleMode string
+
+const (
+	// NetworkAclRulesInline は aws_network_acl の ingress / egress ブロックとして出力する（デフォルト）。
+	NetworkAclRulesInline NetworkAclRuleMode = "inline"
+	// NetworkAclRulesSeparate はルールごとに aws_network_acl_rule リソースとして出力する。
+	NetworkAclRulesSeparate NetworkAclRuleMode = "separate"
+)
+
+// ParseNetworkAclRuleMode は --nacl-rules の値を NetworkAclRuleMode に変換する。空文字は inline として扱う。
+func ParseNetworkAclRuleMode(s string) (NetworkAclRuleMode, error) {
+	switch NetworkAclRuleMode(strings.TrimSpace(s)) {
+	case "", NetworkAclRulesInline:
+		return NetworkAclRulesInline, nil
+	case NetworkAclRulesSeparate:
+		return NetworkAclRulesSeparate, nil
+	default:
+		return "", fmt.Errorf("invalid network ACL rule mode %q (expected %q or %q)", s, NetworkAclRulesInline, NetworkAclRulesSeparate)
+	}
+}
+
+// SeparateNetworkAclRules は aws_network_acl の ingress / egress ブロックを、
+// ルールごとの aws_network_acl_rule リソースに分割した新しいスライスを返す。
+//   - ID:       "aws:aws_network_acl_rule:acl-xxx:100:false"（region で修飾済みの場合はそれを引き継ぐ）
+//   - Name:     "<acl の Name>_ingress_100"
+//   - import ID: "acl-xxx:100:6:false"（network_acl_id:rule_number:protocol:egress）
+//   - Relation: network_acl_rule -> network_acl (security)
+//
+// aws_default_network_acl はスタンドアロンのルールと併用できないため、ブロックのまま残す。
+// ディスカバリ結果（キャッシュ・スナップショット）には影響させず、HCL 生成の直前に適用する。
+func SeparateNetworkAclRules(resources []Resource, relations []Relation) ([]Resource, []Relation) {
+	outResources := make([]Resource, 0, len(resources))
+	outRelations := append([]Relation(nil), relations...)
+
+	for _, r := range resources {
+		if r.Type != "aws_network_acl" {
+			outResources = append(outResources, r)
+			continue
+		}
+
+		aclID, _ := r.Attributes["id"].(string)
+		attrs := make(map[string]any, len(r.Attributes))
+		for k, v := range r.Attributes {
+			if k == "ingress" || k == "egress" {
+				continue
+			}
+			attrs[k] = v
+		}
+		acl := r
+		acl.Attributes = attrs
+		outResources = append(outResources, acl)
+
+		for _, egress := range []bool{false, true} {
+			key := "ingress"
+			if egress {
+				key = "egress"
+			}
+			blocks, _ := r.Attributes[key].([]HCLBlock)
+			for _, block := range blocks {
+				rule := networkAclRuleResource(r, aclID, key, egress, block)
+				outResources = append(outResources, rule)
+				outRelations = append(outRelations, Relation{
+					From: rule.ID,
+					To:   r.ID,
+					Kind: RelationSecurity,
+				})
+			}
+		}
+	}
+
+	return outResources, outRelations
+}
+
+// networkAclRuleResource は aws_network_acl の 1 ブロックから aws_network_acl_rule の Resource を生成する。
+func networkAclRuleResource(acl Resource, aclID string, direction string, egress bool, block HCLBlock) Resource {
+	ruleNo := fmt.Sprint(block["rule_no"])
+	protocol := fmt.Sprint(block["protocol"])
+
+	attr := map[string]any{
+		"id":             fmt.Sprintf("%s:%s:%s:%t", aclID, ruleNo, protocol, egress),
+		"network_acl_id": aclID,
+		"rule_number":    block["rule_no"],
+		"egress":         egress,
+		"protocol":       block["protocol"],
+		"rule_action":    block["action"],
+		"from_port":      block["from_port"],
+		"to_port":        block["to_port"],
+	}
+	for _, k := range []string{"cidr_block", "ipv6_cidr_block", "icmp_type", "icmp_code"} {
+		if v, ok := block[k]; ok {
+			attr[k] = v
+		}
+	}
+
+	labels := make(map[string]string, len(acl.Labels))
+	for k, v := range acl.Labels {
+		labels[k] = v
+	}
+
+	return Resource{
+		ID:         strings.Replace(acl.ID, ":aws_network_acl:", ":aws_network_acl_rule:", 1) + fmt.Sprintf(":%s:%t", ruleNo, egress),
+		Provider:   acl.Provider,
+		Type:       "aws_network_acl_rule",
+		Name:       sanitizeTerraformIdentifier(fmt.Sprintf("%s_%s_%s", acl.Name, direction, ruleNo)),
+		Labels:     labels,
+		Attributes: attr,
+		Origin:     acl.Origin,
+	}
+}
+
+// isIcmpProtocol は AWS API のプロトコル番号が ICMP / ICMPv6 かどうかを判定する。
+func isIcmpProtocol(protocol string) bool {
+	return protocol == "1" || protocol == "58"
+}
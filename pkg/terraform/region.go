@@ -0,0 +1,90 @@
+package terraform
+
+import (
+	"strings"
+)
+
+// RegionProviderAlias はリージョンに対応する aws provider の alias 名を返す。
+// 例: "ap-northeast-1" -> "ap_northeast_1"
+func RegionProviderAlias(region string) string {
+	return sanitizeTerraformIdentifier(region)
+}
+
+// RegionOf は Resource のリージョン（Labels["aws_region"]）を返す。
+func RegionOf(r Resource) string {
+	if r.Labels == nil {
+		return ""
+	}
+	return r.Labels["aws_region"]
+}
+
+// QualifyByRegion は複数リージョンの列挙結果が衝突しないよう、
+// 1 リージョン分の列挙結果の Resource.ID / Resource.Name および Relation を region で修飾した新しいスライスを返す。
+//   - ID:   "aws:aws_subnet:subnet-xxx" -> "aws:ap-northeast-1:aws_subnet:subnet-xxx"
+//   - Name: "web" -> "ap_northeast_1_web"
+//
+// IAM ロールなどのグローバルなリソース（globalResourceTypes）は修飾しない。
+// 各リージョンの列挙結果で同じ ID になるため、集約時に重複として除かれる。
+//
+// 修飾前の ID はリージョンをまたぐと重複しうるため、リージョンごとの結果を集約する前に呼び出すこと。
+func QualifyByRegion(region string, resources []Resource, relations []Relation) ([]Resource, []Relation) {
+	outResources := make([]Resource, 0, len(resources))
+	for _, r := range resources {
+		if globalResourceTypes[r.Type] {
+			outResources = append(outResources, r)
+			continue
+		}
+		r.ID = qualifyID(r.ID, region)
+		r.Name = RegionProviderAlias(region) + "_" + r.Name
+		outResources = append(outResources, r)
+	}
+
+	outRelations := make([]Relation, 0, len(relations))
+	for _, rel := range relations {
+		rel.From = qualifyID(rel.From, region)
+		rel.To = qualifyID(rel.To, region)
+		outRelations = append(outRelations, rel)
+	}
+	return outResources, outRelations
+}
+
+// globalResourceTypes はリージョンに属さず、リージョンで修飾しない Type。
+var globalResourceTypes = map[string]bool{
+	"aws_iam_role":                   true,
+	"aws_iam_role_policy":            true,
+	"aws_iam_role_policy_attachment": true,
+	"aws_iam_instance_profile":       true,
+}
+
+// qualifyID は "<provider>:<rest>" 形式の ID の provider 直後にリージョンを挿入する。
+// "<provider>:<type>:<cloud_id>" の type がグローバルなリソースの ID はそのまま返す。
+func qualifyID(id string, region string) string {
+	provider, rest, ok := strings.Cut(id, ":")
+	if !ok {
+		return region + ":" + id
+	}
+	if resourceType, _, ok := strings.Cut(rest, ":"); ok && globalResourceTypes[resourceType] {
+		return id
+	}
+	return provider + ":" + region + ":" + rest
+}
+
+// EnsureUniqueNames は同一 Type 内で Name が重複している Resource に _1, _2 ... を付与する。
+// 複数 VPC の結果を集約した場合や、キャッシュから取得した結果を混在させた場合でも
+// Terraform のアドレス（<type>.<name>）が一意になるようにする。
+// 先に現れた Resource の Name を優先して残す。
+func EnsureUniqueNames(resources []Resource) {
+	used := make(map[string]map[string]bool)
+	for i := range resources {
+		t := resources[i].Type
+		if used[t] == nil {
+			used[t] = make(map[string]bool)
+		}
+		name := resources[i].Name
+		for n := 1; used[t][name]; n++ {
+			name = resources[i].Name + "_" + itoa(n)
+		}
+		used[t][name] = true
+		resources[i].Name = name
+	}
+}
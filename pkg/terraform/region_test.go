@@ -0,0 +1,70 @@
+package terraform
+
+import "testing"
+
+// TestQualifyByRegion はリージョンで修飾した結果のうち、グローバルな IAM リソースの ID / 名前と
+// それを指す Relation の参照先が修飾されないことを確認する。
+func TestQualifyByRegion(t *testing.T) {
+	resources := []Resource{
+		{ID: "aws:aws_lambda_function:api", Type: "aws_lambda_function", Name: "api"},
+		{ID: "aws:aws_iam_role:api-role", Type: "aws_iam_role", Name: "api_role"},
+		{ID: "aws:aws_iam_role_policy:api-role:inline", Type: "aws_iam_role_policy", Name: "api_role_inline"},
+	}
+	relations := []Relation{
+		{From: "aws:aws_lambda_function:api", To: "aws:aws_iam_role:api-role", Kind: RelationIAM},
+		{From: "aws:aws_iam_role_policy:api-role:inline", To: "aws:aws_iam_role:api-role", Kind: RelationDependsOn},
+	}
+
+	tests := []struct {
+		region        string
+		wantIDs       []string
+		wantNames     []string
+		wantRelations []Relation
+	}{
+		{
+			region:    "ap-northeast-1",
+			wantIDs:   []string{"aws:ap-northeast-1:aws_lambda_function:api", "aws:aws_iam_role:api-role", "aws:aws_iam_role_policy:api-role:inline"},
+			wantNames: []string{"ap_northeast_1_api", "api_role", "api_role_inline"},
+			wantRelations: []Relation{
+				{From: "aws:ap-northeast-1:aws_lambda_function:api", To: "aws:aws_iam_role:api-role", Kind: RelationIAM},
+				{From: "aws:aws_iam_role_policy:api-role:inline", To: "aws:aws_iam_role:api-role", Kind: RelationDependsOn},
+			},
+		},
+		{
+			region:    "us-east-1",
+			wantIDs:   []string{"aws:us-east-1:aws_lambda_function:api", "aws:aws_iam_role:api-role", "aws:aws_iam_role_policy:api-role:inline"},
+			wantNames: []string{"us_east_1_api", "api_role", "api_role_inline"},
+			wantRelations: []Relation{
+				{From: "aws:us-east-1:aws_lambda_function:api", To: "aws:aws_iam_role:api-role", Kind: RelationIAM},
+				{From: "aws:aws_iam_role_policy:api-role:inline", To: "aws:aws_iam_role:api-role", Kind: RelationDependsOn},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			rs, rels := QualifyByRegion(tt.region, resources, relations)
+			if len(rs) != len(tt.wantIDs) {
+				t.Fatalf("got %d resources, want %d", len(rs), len(tt.wantIDs))
+			}
+			for i, r := range rs {
+				if r.ID != tt.wantIDs[i] || r.Name != tt.wantNames[i] {
+					t.Errorf("resource %d = (%s, %s), want (%s, %s)", i, r.ID, r.Name, tt.wantIDs[i], tt.wantNames[i])
+				}
+			}
+			if len(rels) != len(tt.wantRelations) {
+				t.Fatalf("got %d relations, want %d", len(rels), len(tt.wantRelations))
+			}
+			for i, rel := range rels {
+				if rel != tt.wantRelations[i] {
+					t.Errorf("relation %d = %+v, want %+v", i, rel, tt.wantRelations[i])
+				}
+			}
+		})
+	}
+
+	// 入力は変更しない
+	if resources[0].ID != "aws:aws_lambda_function:api" || relations[0].From != "aws:aws_lambda_function:api" {
+		t.Errorf("input modified: %+v, %+v", resources[0], relations[0])
+	}
+}
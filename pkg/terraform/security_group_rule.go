@@ -0,0 +1,148 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SecurityGroupRuleMode はセキュリティグループのルールを HCL 上でどう表現するかを表す。
+type SecurityGroupRuleMode string
+
+const (
+	// SecurityGroupRulesSeparate はルールごとに aws_vpc_security_group_ingress_rule / egress_rule リソースとして出力する（デフォルト）。
+	SecurityGroupRulesSeparate SecurityGroupRuleMode = "separate"
+	// SecurityGroupRulesInline は aws_security_group の ingress / egress ブロックとして出力する。
+	SecurityGroupRulesInline SecurityGroupRuleMode = "inline"
+)
+
+// ParseSecurityGroupRuleMode は --sg-rules の値を SecurityGroupRuleMode に変換する。空文字は separate として扱う。
+func ParseSecurityGroupRuleMode(s string) (SecurityGroupRuleMode, error) {
+	switch SecurityGroupRuleMode(strings.TrimSpace(s)) {
+	case "", SecurityGroupRulesSeparate:
+		return SecurityGroupRulesSeparate, nil
+	case SecurityGroupRulesInline:
+		return SecurityGroupRulesInline, nil
+	default:
+		return "", fmt.Errorf("invalid security group rule mode %q (expected %q or %q)", s, SecurityGroupRulesSeparate, SecurityGroupRulesInline)
+	}
+}
+
+// InlineSecurityGroupRules は aws_vpc_security_group_ingress_rule / egress_rule を、
+// 対象の aws_security_group の ingress / egress ブロックにまとめた新しいスライスを返す。
+//   - 自身の SG を参照するルールは self = true、他の SG を参照するルールは security_groups になる
+//   - Relation: ルールの関係は security_group -> referenced_security_group (security) に付け替える
+//
+// 対象の SG がディスカバリ結果に含まれないルールは、スタンドアロンのまま残す。
+// ディスカバリ結果（キャッシュ・スナップショット）には影響させず、HCL 生成の直前に適用する。
+func InlineSecurityGroupRules(resources []Resource, relations []Relation) ([]Resource, []Relation) {
+	groupIndex := make(map[string]int)
+	for i, r := range resources {
+		if r.Type == "aws_security_group" {
+			groupIndex[r.ID] = i
+		}
+	}
+
+	// ルール Resource ID -> まとめ先の SG の Resource ID
+	inlined := make(map[string]string)
+	blocks := make(map[string]map[string][]HCLBlock)
+	for _, r := range resources {
+		if !isSecurityGroupRuleType(r.Type) {
+			continue
+		}
+		groupID, _ := r.Attributes["security_group_id"].(string)
+		groupResID := sameScopeResourceID(r, "aws_security_group", groupID)
+		if _, ok := groupIndex[groupResID]; !ok {
+			continue
+		}
+		key := "ingress"
+		if r.Type == "aws_vpc_security_group_egress_rule" {
+			key = "egress"
+		}
+		if blocks[groupResID] == nil {
+			blocks[groupResID] = make(map[string][]HCLBlock)
+		}
+		blocks[groupResID][key] = append(blocks[groupResID][key], securityGroupRuleBlock(r.Attributes, groupID))
+		inlined[r.ID] = groupResID
+	}
+
+	outResources := make([]Resource, 0, len(resources)-len(inlined))
+	for _, r := range resources {
+		if _, ok := inlined[r.ID]; ok {
+			continue
+		}
+		if b, ok := blocks[r.ID]; ok {
+			attrs := make(map[string]any, len(r.Attributes)+2)
+			for k, v := range r.Attributes {
+				attrs[k] = v
+			}
+			for k, v := range b {
+				attrs[k] = v
+			}
+			r.Attributes = attrs
+		}
+		outResources = append(outResources, r)
+	}
+
+	outRelations := make([]Relation, 0, len(relations))
+	seen := make(map[Relation]bool)
+	for _, rel := range relations {
+		if groupResID, ok := inlined[rel.From]; ok {
+			// ルール -> 自身の SG の関係は不要。参照先 SG への関係は SG からの関係にする
+			if rel.To == groupResID {
+				continue
+			}
+			rel.From = groupResID
+		}
+		if seen[rel] {
+			continue
+		}
+		seen[rel] = true
+		outRelations = append(outRelations, rel)
+	}
+
+	return outResources, outRelations
+}
+
+// isSecurityGroupRuleType はスタンドアロンのセキュリティグループルールのリソースタイプかどうかを判定する。
+func isSecurityGroupRuleType(resourceType string) bool {
+	return resourceType == "aws_vpc_security_group_ingress_rule" || resourceType == "aws_vpc_security_group_egress_rule"
+}
+
+// sameScopeResourceID は r と同じ形式（region 修飾の有無）で resourceType / cloudID の Resource ID を返す。
+func sameScopeResourceID(r Resource, resourceType string, cloudID string) string {
+	prefix := strings.TrimSuffix(r.ID, ":"+r.Type+":"+fmt.Sprint(r.Attributes["id"]))
+	return prefix + ":" + resourceType + ":" + cloudID
+}
+
+// securityGroupRuleBlock はスタンドアロンのルールの属性を aws_security_group の ingress / egress ブロックに変換する。
+func securityGroupRuleBlock(attrs map[string]any, groupID string) HCLBlock {
+	block := HCLBlock{
+		"protocol":  attrs["ip_protocol"],
+		"from_port": int32(0),
+		"to_port":   int32(0),
+	}
+	if v, ok := attrs["from_port"]; ok {
+		block["from_port"] = v
+		block["to_port"] = attrs["to_port"]
+	}
+	for src, dst := range map[string]string{
+		"cidr_ipv4":      "cidr_blocks",
+		"cidr_ipv6":      "ipv6_cidr_blocks",
+		"prefix_list_id": "prefix_list_ids",
+	} {
+		if v, ok := attrs[src].(string); ok && v != "" {
+			block[dst] = []string{v}
+		}
+	}
+	if ref, ok := attrs["referenced_security_group_id"].(string); ok && ref != "" {
+		if ref == groupID {
+			block["self"] = true
+		} else {
+			block["security_groups"] = []string{ref}
+		}
+	}
+	if v, ok := attrs["description"].(string); ok && v != "" {
+		block["description"] = v
+	}
+	return block
+}
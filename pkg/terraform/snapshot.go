@@ -0,0 +1,149 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SnapshotFormatVersion は DiscoverySnapshot のファイル形式のバージョン。
+// 互換性のない変更を加えた場合にインクリメントする。
+const SnapshotFormatVersion = 1
+
+// SnapshotMetadata はスナップショット取得時のメタデータ。
+type SnapshotMetadata struct {
+	FormatVersion int       `json:"formatVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Tool          string    `json:"tool,omitempty"` // 取得したツール名 (例: "vpc-importer")
+}
+
+// DiscoverySnapshot はクラウド側リソース列挙結果をファイルに保存するための形式。
+// 認証情報なしでの HCL / import スクリプトの再生成や、不具合報告時の再現入力として利用する。
+type DiscoverySnapshot struct {
+	Metadata  SnapshotMetadata `json:"metadata"`
+	Scope     DiscoveryScope   `json:"scope"`
+	Resources []Resource       `json:"resources"`
+	Relations []Relation       `json:"relations"`
+	Report    DiscoveryReport  `json:"report"`
+}
+
+// NewDiscoverySnapshot は列挙結果から DiscoverySnapshot を生成する。
+func NewDiscoverySnapshot(scope DiscoveryScope, resources []Resource, relations []Relation, report DiscoveryReport, tool string) DiscoverySnapshot {
+	return DiscoverySnapshot{
+		Metadata: SnapshotMetadata{
+			FormatVersion: SnapshotFormatVersion,
+			CreatedAt:     time.Now().UTC(),
+			Tool:          tool,
+		},
+		Scope:     scope,
+		Resources: resources,
+		Relations: relations,
+		Report:    report,
+	}
+}
+
+// WriteSnapshot は snapshot を JSON として path に書き出す。
+func WriteSnapshot(path string, snapshot DiscoverySnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadSnapshot は path から DiscoverySnapshot を読み込む。
+// JSON のデコードで型が失われる Attributes は NormalizeJSONAttributes で元の型に戻す。
+// HCLExpression は文字列として復元される（ディスカバリ結果には含まれない想定）。
+func ReadSnapshot(path string) (DiscoverySnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DiscoverySnapshot{}, fmt.Errorf("failed to read snapshot %s: %w", path, err)
+	}
+
+	var snapshot DiscoverySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return DiscoverySnapshot{}, fmt.Errorf("failed to decode snapshot %s: %w", path, err)
+	}
+	if v := snapshot.Metadata.FormatVersion; v != SnapshotFormatVersion {
+		return DiscoverySnapshot{}, fmt.Errorf("unsupported snapshot format version %d in %s (expected %d)", v, path, SnapshotFormatVersion)
+	}
+
+	NormalizeJSONAttributes(snapshot.Resources)
+	return snapshot, nil
+}
+
+// NormalizeJSONAttributes は JSON からデコードした Resource の Attributes について、
+// 型が失われた値（[]any, map[string]any）を可能な範囲で []string / map[string]string に戻す。
+func NormalizeJSONAttributes(resources []Resource) {
+	for i := range resources {
+		attrs := resources[i].Attributes
+		for k, v := range attrs {
+			attrs[k] = normalizeJSONValue(v)
+		}
+	}
+}
+
+// normalizeJSONValue は encoding/json が生成した []any / map[string]any のうち、
+// 要素がすべて文字列のものを []string / map[string]string に、
+// 要素がすべてオブジェクトの []any を []HCLBlock に変換する。
+// それ以外の値は入れ子を再帰的に処理してそのまま返す。
+func normalizeJSONValue(v any) any {
+	switch t := v.(type) {
+	case []any:
+		if blocks, ok := normalizeJSONBlocks(t); ok {
+			return blocks
+		}
+		strs := make([]string, 0, len(t))
+		for _, e := range t {
+			s, ok := e.(string)
+			if !ok {
+				for i := range t {
+					t[i] = normalizeJSONValue(t[i])
+				}
+				return t
+			}
+			strs = append(strs, s)
+		}
+		return strs
+	case map[string]any:
+		strs := make(map[string]string, len(t))
+		for k, e := range t {
+			s, ok := e.(string)
+			if !ok {
+				for k := range t {
+					t[k] = normalizeJSONValue(t[k])
+				}
+				return t
+			}
+			strs[k] = s
+		}
+		return strs
+	default:
+		return v
+	}
+}
+
+// normalizeJSONBlocks は要素がすべてオブジェクトの []any を []HCLBlock に変換する。
+// 空の配列や、オブジェクト以外の要素を含む場合は false を返す。
+func normalizeJSONBlocks(list []any) ([]HCLBlock, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+	blocks := make([]HCLBlock, 0, len(list))
+	for _, e := range list {
+		m, ok := e.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		block := make(HCLBlock, len(m))
+		for k, v := range m {
+			block[k] = normalizeJSONValue(v)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, true
+}